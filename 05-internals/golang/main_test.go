@@ -0,0 +1,338 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main_test
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go-generics-the-hard-way/internal/delve"
+)
+
+// TestInternals drives the Delve session from "Type erasure" and asserts
+// what it shows, so the chapter's claims are checked with each Go release.
+func TestInternals(t *testing.T) {
+	if _, err := exec.LookPath("dlv"); err != nil {
+		t.Skip("dlv is not installed")
+	}
+	if testing.Short() {
+		t.Skip("builds and debugs a binary")
+	}
+	t.Logf("debugging with %s", runtime.Version())
+
+	bin := build(t)
+	c, err := delve.Exec(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// printLen is stenciled once per GC shape, and each function is passed
+	// the dictionary for the concrete instantiation it is called for.
+	// Delve reads the dictionary to resolve the type of the list.
+	if _, err := c.CreateBreakpoint("main.printLen"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []struct {
+		fn  *regexp.Regexp
+		typ string
+		len int64
+	}{
+		{fn: shaped(`main.printLen[go.shape.int]`), typ: "main.List[int]", len: 3},
+		{fn: shaped(`main.printLen[go.shape.string]`), typ: "main.List[string]", len: 2},
+	} {
+		st, err := c.Continue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fn := function(st); !want.fn.MatchString(fn) {
+			t.Fatalf("stopped in %s, want %s", fn, want.fn)
+		}
+		args, err := c.FunctionArgs(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l := find(args, "list"); l == nil || l.Type != want.typ || l.Len != want.len {
+			t.Errorf("in %s, list = %+v, want a %s of length %d", function(st), l, want.typ, want.len)
+		}
+	}
+
+	// runtime.Breakpoint stops the process at the end of main.
+	st, err := c.Continue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fn := function(st); fn != "main.main" {
+		t.Fatalf("stopped in %s, want main.main", fn)
+	}
+	if out := c.Output(); !strings.HasPrefix(out, "3\n2\n") {
+		t.Errorf("got output %q, want 3 and 2", out)
+	}
+	locals, err := c.LocalVars(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]struct {
+		typ  string
+		vals []string
+	}{
+		"ints": {typ: "main.List[int]", vals: []string{"1", "2", "3"}},
+		"strs": {typ: "main.List[string]", vals: []string{"Hello", "world"}},
+	} {
+		v := find(locals, name)
+		if v == nil || v.Type != want.typ || len(v.Children) != len(want.vals) {
+			t.Errorf("%s = %+v, want a %s with %d values", name, v, want.typ, len(want.vals))
+			continue
+		}
+		for i, c := range v.Children {
+			if c.Value != want.vals[i] {
+				t.Errorf("%s[%d] = %q, want %q", name, i, c.Value, want.vals[i])
+			}
+		}
+	}
+
+	// The instantiations exist at runtime, but the generic "template"
+	// does not.
+	types, err := c.Types(`main\.List`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"main.List[int]", "main.List[string]", "*main.List[int]", "*main.List[string]"} {
+		if !contains(types, want) {
+			t.Errorf("types %q do not include %s", types, want)
+		}
+	}
+	for _, typ := range types {
+		if typ == "main.List" || strings.Contains(typ, "main.List[T]") {
+			t.Errorf("types include the generic type %s", typ)
+		}
+	}
+
+	funcs, err := c.Functions(`go\.shape`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`main.(*List[go.shape.int]).add`,
+		`main.(*List[go.shape.string]).add`,
+		`main.printLen[go.shape.int]`,
+		`main.printLen[go.shape.string]`,
+	} {
+		re := shaped(want)
+		found := false
+		for _, fn := range funcs {
+			found = found || re.MatchString(fn)
+		}
+		if !found {
+			t.Errorf("functions %q do not include %s", funcs, want)
+		}
+	}
+}
+
+// TestDictionary reads the dictionary each stenciled printLen is passed,
+// which Delve hides from the function's arguments, and asserts that it
+// describes the instantiation printLen was called for.
+func TestDictionary(t *testing.T) {
+	if _, err := exec.LookPath("dlv"); err != nil {
+		t.Skip("dlv is not installed")
+	}
+	if testing.Short() {
+		t.Skip("builds and debugs a binary")
+	}
+	// The dictionary is passed in the register of the first integer
+	// argument, which depends on the platform's ABI.
+	reg := map[string]string{"amd64": "rax", "arm64": "x0"}[runtime.GOARCH]
+	if runtime.GOOS != "linux" || reg == "" {
+		t.Skipf("reads the dictionary from ELF binaries on amd64 and arm64, not %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	bin := build(t)
+	syms, err := symbols(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := delve.Exec(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Break at the entry of each stenciled function rather than after its
+	// prologue, which may reuse the register.
+	type stencil struct {
+		fn, dict string
+		types    []string
+	}
+	stencils := map[string]stencil{}
+	var entries []uint64
+	for _, want := range []stencil{
+		{fn: `main.printLen[go.shape.int]`, dict: "main..dict.printLen[int]", types: []string{"int", "main.List[int]"}},
+		{fn: `main.printLen[go.shape.string]`, dict: "main..dict.printLen[string]", types: []string{"string", "main.List[string]"}},
+	} {
+		re := shaped(want.fn)
+		for name, addr := range syms {
+			if re.MatchString(name) {
+				stencils[name] = want
+				entries = append(entries, addr)
+			}
+		}
+	}
+	if len(entries) != 2 {
+		t.Fatalf("found the stenciled functions %v, want two printLen functions", stencils)
+	}
+	if _, err := c.CreateBreakpointAt(entries...); err != nil {
+		t.Fatal(err)
+	}
+
+	for range entries {
+		st, err := c.Continue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fn := function(st)
+		want, ok := stencils[fn]
+		if !ok {
+			t.Fatalf("stopped in %s, want a stenciled printLen", fn)
+		}
+		regs, err := c.Registers(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dict, ok := regs[reg]
+		if !ok || dict != syms[want.dict] {
+			t.Errorf("in %s, %s = %#x, want the address of %s, %#x", fn, reg, dict, want.dict, syms[want.dict])
+			continue
+		}
+
+		// The dictionary starts with the type descriptors the stenciled
+		// code needs, ex. that of the type argument.
+		word, err := c.ReadMemory(dict, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		typ, err := typeName(c, syms["runtime.types"], binary.LittleEndian.Uint64(word))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !contains(want.types, typ) {
+			t.Errorf("in %s, the dictionary describes %s, want one of %q", fn, typ, want.types)
+		}
+	}
+}
+
+// build builds the binary the way dlv debug does, without optimizations or
+// inlining, so the stenciled functions keep their frames.
+func build(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "golang")
+	cmd := exec.Command("go", "build", "-gcflags=all=-N -l", "-o", bin, ".")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s", err, out)
+	}
+	return bin
+}
+
+// symbols returns the addresses of an ELF binary's symbols by name.
+func symbols(bin string) (map[string]uint64, error) {
+	f, err := elf.Open(bin)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	syms, err := f.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	addrs := map[string]uint64{}
+	for _, s := range syms {
+		addrs[s.Name] = s.Value
+	}
+	return addrs, nil
+}
+
+// typeName reads the name of the type descriptor at addr from the process's
+// memory. The name is stored at an offset from the start of the types, at
+// the same place in each descriptor of a 64-bit platform since Go 1.17, ex.
+// runtime._type and internal/abi.Type.
+func typeName(c *delve.Client, types, addr uint64) (string, error) {
+	const (
+		tflagOff    = 20
+		nameOffOff  = 40
+		extraStar   = 1 << 1
+		maxNameSize = 64
+	)
+	typ, err := c.ReadMemory(addr, nameOffOff+4)
+	if err != nil {
+		return "", err
+	}
+	off := binary.LittleEndian.Uint32(typ[nameOffOff:])
+	name, err := c.ReadMemory(types+uint64(off), maxNameSize)
+	if err != nil {
+		return "", err
+	}
+	// A name is a byte of flags, followed by its length as a varint.
+	n, size := binary.Uvarint(name[1:])
+	if size <= 0 || 1+size+int(n) > len(name) {
+		return "", fmt.Errorf("invalid type name at %#x", types+uint64(off))
+	}
+	s := string(name[1+size : 1+size+int(n)])
+	if typ[tflagOff]&extraStar != 0 {
+		s = strings.TrimPrefix(s, "*")
+	}
+	return s, nil
+}
+
+// shaped returns a regular expression that matches a function name with GC
+// shapes, which Go 1.18 suffixed with the index of the type parameter, ex.
+// go.shape.int_0.
+func shaped(name string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(name)
+	expr = regexp.MustCompile(`go\\\.shape\\\.(\w+)`).ReplaceAllString(expr, `go\.shape\.$1(_\d+)?`)
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+func function(st *delve.State) string {
+	if st.CurrentThread == nil || st.CurrentThread.Function == nil {
+		return "???"
+	}
+	return st.CurrentThread.Function.Name
+}
+
+func find(vars []delve.Variable, name string) *delve.Variable {
+	for i := range vars {
+		if vars[i].Name == name {
+			return &vars[i]
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
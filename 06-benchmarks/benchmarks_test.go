@@ -17,29 +17,36 @@ limitations under the License.
 package benchmarks_test
 
 import (
+	"debug/elf"
+	"errors"
 	"os"
 	"os/exec"
 	"testing"
 
+	"go-generics-the-hard-way/internal/binsize"
+
 	blist "go-generics-the-hard-way/06-benchmarks/lists/boxed"
 	glist "go-generics-the-hard-way/06-benchmarks/lists/generic"
 	tlist "go-generics-the-hard-way/06-benchmarks/lists/typed"
 )
 
+// BenchmarkBoxing adds ints to each kind of list. Like BenchmarkGoBuild, the
+// sub-benchmarks are named by the number of types the list is instantiated
+// with, which is always one.
 func BenchmarkBoxing(b *testing.B) {
-	b.Run("boxed", func(b *testing.B) {
+	b.Run("boxed/1-types", func(b *testing.B) {
 		var list blist.List
 		for i := 0; i < b.N; i++ {
 			list = append(list, i)
 		}
 	})
-	b.Run("generic", func(b *testing.B) {
+	b.Run("generic/1-types", func(b *testing.B) {
 		var list glist.List[int]
 		for i := 0; i < b.N; i++ {
 			list = append(list, i)
 		}
 	})
-	b.Run("typed", func(b *testing.B) {
+	b.Run("typed/1-types", func(b *testing.B) {
 		var list tlist.IntList
 		for i := 0; i < b.N; i++ {
 			list = append(list, i)
@@ -47,15 +54,6 @@ func BenchmarkBoxing(b *testing.B) {
 	})
 }
 
-var (
-	tags0Types = "no_int"
-	tags1Types = "int"
-	tags2Types = "int,int8"
-	tags3Types = "int,int8,int16"
-	tags4Types = "int,int8,int16,int32"
-	tags5Types = "int,int8,int16,int32,int64"
-)
-
 type gobuildTestCase struct {
 	name     string
 	listType string
@@ -71,13 +69,15 @@ type gobuildSubTestGroup struct {
 type gobuildSubTestCase struct {
 	name     string
 	args     []string
-	tags     []string
 	filePath string
 	fileSize int64
 }
 
+// BenchmarkGoBuild builds the boxed list and the generic and typed lists
+// with each number of types. The cases for the generic and typed lists are
+// generated along with their fixtures, please see ./lists/gen.
 func BenchmarkGoBuild(b *testing.B) {
-	testCases := []gobuildTestCase{
+	testCases := append([]gobuildTestCase{
 		{
 			name:     "boxed",
 			listType: "boxed",
@@ -106,163 +106,7 @@ func BenchmarkGoBuild(b *testing.B) {
 				},
 			},
 		},
-		{
-			name:     "generic",
-			listType: "generic",
-			testGrps: []gobuildSubTestGroup{
-				{
-					name:     "bin",
-					fileType: ".bin",
-					subTests: []gobuildSubTestCase{
-						{
-							name:     "0-types",
-							args:     []string{"build", "-a", "-tags", tags0Types, "-o", "generic-0-types.bin", "./lists/generic/cmd/"},
-							filePath: "generic-0-types.bin",
-						},
-						{
-							name:     "1-types",
-							args:     []string{"build", "-a", "-tags", tags1Types, "-o", "generic-1-types.bin", "./lists/generic/cmd/"},
-							filePath: "generic-1-types.bin",
-						},
-						{
-							name:     "2-types",
-							args:     []string{"build", "-a", "-tags", tags2Types, "-o", "generic-2-types.bin", "./lists/generic/cmd/"},
-							filePath: "generic-2-types.bin",
-						},
-						{
-							name:     "3-types",
-							args:     []string{"build", "-a", "-tags", tags3Types, "-o", "generic-3-types.bin", "./lists/generic/cmd/"},
-							filePath: "generic-3-types.bin",
-						},
-						{
-							name:     "4-types",
-							args:     []string{"build", "-a", "-tags", tags4Types, "-o", "generic-4-types.bin", "./lists/generic/cmd/"},
-							filePath: "generic-4-types.bin",
-						},
-						{
-							name:     "5-types",
-							args:     []string{"build", "-a", "-tags", tags5Types, "-o", "generic-5-types.bin", "./lists/generic/cmd/"},
-							filePath: "generic-5-types.bin",
-						},
-					},
-				},
-				{
-					name:     "pkg",
-					fileType: ".a",
-					subTests: []gobuildSubTestCase{
-						{
-							name:     "0-types",
-							args:     []string{"build", "-a", "-tags", tags0Types, "-o", "generic-0-types.a", "./lists/generic/"},
-							filePath: "generic-0-types.a",
-						},
-						{
-							name:     "1-types",
-							args:     []string{"build", "-a", "-tags", tags1Types, "-o", "generic-1-types.a", "./lists/generic/"},
-							filePath: "generic-1-types.a",
-						},
-						{
-							name:     "2-types",
-							args:     []string{"build", "-a", "-tags", tags2Types, "-o", "generic-2-types.a", "./lists/generic/"},
-							filePath: "generic-2-types.a",
-						},
-						{
-							name:     "3-types",
-							args:     []string{"build", "-a", "-tags", tags3Types, "-o", "generic-3-types.a", "./lists/generic/"},
-							filePath: "generic-3-types.a",
-						},
-						{
-							name:     "4-types",
-							args:     []string{"build", "-a", "-tags", tags4Types, "-o", "generic-4-types.a", "./lists/generic/"},
-							filePath: "generic-4-types.a",
-						},
-						{
-							name:     "5-types",
-							args:     []string{"build", "-a", "-tags", tags5Types, "-o", "generic-5-types.a", "./lists/generic/"},
-							filePath: "generic-5-types.a",
-						},
-					},
-				},
-			},
-		},
-		{
-			name:     "typed",
-			listType: "typed",
-			testGrps: []gobuildSubTestGroup{
-				{
-					name:     "bin",
-					fileType: ".bin",
-					subTests: []gobuildSubTestCase{
-						{
-							name:     "0-types",
-							args:     []string{"build", "-a", "-tags", tags0Types, "-o", "typed-0-types.bin", "./lists/typed/cmd/"},
-							filePath: "typed-0-types.bin",
-						},
-						{
-							name:     "1-types",
-							args:     []string{"build", "-a", "-tags", tags1Types, "-o", "typed-1-types.bin", "./lists/typed/cmd/"},
-							filePath: "typed-1-types.bin",
-						},
-						{
-							name:     "2-types",
-							args:     []string{"build", "-a", "-tags", tags2Types, "-o", "typed-2-types.bin", "./lists/typed/cmd/"},
-							filePath: "typed-2-types.bin",
-						},
-						{
-							name:     "3-types",
-							args:     []string{"build", "-a", "-tags", tags3Types, "-o", "typed-3-types.bin", "./lists/typed/cmd/"},
-							filePath: "typed-3-types.bin",
-						},
-						{
-							name:     "4-types",
-							args:     []string{"build", "-a", "-tags", tags4Types, "-o", "typed-4-types.bin", "./lists/typed/cmd/"},
-							filePath: "typed-4-types.bin",
-						},
-						{
-							name:     "5-types",
-							args:     []string{"build", "-a", "-tags", tags5Types, "-o", "typed-5-types.bin", "./lists/typed/cmd/"},
-							filePath: "typed-5-types.bin",
-						},
-					},
-				},
-				{
-					name:     "pkg",
-					fileType: ".a",
-					subTests: []gobuildSubTestCase{
-						{
-							name:     "0-types",
-							args:     []string{"build", "-a", "-tags", tags0Types, "-o", "typed-0-types.a", "./lists/typed/"},
-							filePath: "typed-0-types.a",
-						},
-						{
-							name:     "1-types",
-							args:     []string{"build", "-a", "-tags", tags1Types, "-o", "typed-1-types.a", "./lists/typed/"},
-							filePath: "typed-1-types.a",
-						},
-						{
-							name:     "2-types",
-							args:     []string{"build", "-a", "-tags", tags2Types, "-o", "typed-2-types.a", "./lists/typed/"},
-							filePath: "typed-2-types.a",
-						},
-						{
-							name:     "3-types",
-							args:     []string{"build", "-a", "-tags", tags3Types, "-o", "typed-3-types.a", "./lists/typed/"},
-							filePath: "typed-3-types.a",
-						},
-						{
-							name:     "4-types",
-							args:     []string{"build", "-a", "-tags", tags4Types, "-o", "typed-4-types.a", "./lists/typed/"},
-							filePath: "typed-4-types.a",
-						},
-						{
-							name:     "5-types",
-							args:     []string{"build", "-a", "-tags", tags5Types, "-o", "typed-5-types.a", "./lists/typed/"},
-							filePath: "typed-5-types.a",
-						},
-					},
-				},
-			},
-		},
-	}
+	}, gobuildGeneratedTestCases...)
 
 	b.ResetTimer()
 
@@ -299,6 +143,14 @@ func BenchmarkGoBuild(b *testing.B) {
 								}
 								b.ReportMetric(float64(info.Size()), "filesize/op")
 							}
+
+							// Every iteration builds the same binary, so
+							// it is only inspected once, outside of the
+							// timed loop.
+							if tg.fileType == ".bin" {
+								b.StopTimer()
+								reportBinSize(b, st.filePath)
+							}
 						})
 					}
 				})
@@ -308,3 +160,26 @@ func BenchmarkGoBuild(b *testing.B) {
 
 	b.StopTimer()
 }
+
+// reportBinSize reports the bytes of a binary's text and read-only data that
+// belong to generic instantiations, and, with -v, logs the size of each
+// instantiation. Binaries that are not ELF files, such as those built on
+// macOS, are skipped.
+func reportBinSize(b *testing.B, path string) {
+	r, err := binsize.Open(path)
+	if errors.As(err, new(*elf.FormatError)) {
+		return
+	}
+	if err != nil {
+		b.Error(err)
+		return
+	}
+	b.ReportMetric(float64(r.GenericText), "generic-text/op")
+	b.ReportMetric(float64(r.GenericRoData), "generic-rodata/op")
+
+	if testing.Verbose() {
+		for _, in := range r.Instantiations {
+			b.Logf("%8d text %8d rodata  %s", in.Text, in.RoData, in.Name)
+		}
+	}
+}
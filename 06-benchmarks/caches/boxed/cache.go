@@ -0,0 +1,77 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+type entry struct {
+	key interface{}
+	val interface{}
+}
+
+// Cache is an LRU cache built the way it had to be before generics, with a
+// map keyed by the empty interface and a container/list whose elements hold
+// their values in the empty interface.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[interface{}]*list.Element
+	order    *list.List
+}
+
+func New(capacity int) *Cache {
+	return &Cache{
+		capacity: capacity,
+		items:    map[interface{}]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (c *Cache) Get(key interface{}) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*entry).val, true
+}
+
+// Put caches val for key.
+//
+// Please note that key and val will be boxed in order to pass them into the
+// method using the empty interface.
+func (c *Cache) Put(key, val interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*entry).val = val
+		c.order.MoveToFront(el)
+		return
+	}
+	if c.capacity > 0 && len(c.items) >= c.capacity {
+		if el := c.order.Back(); el != nil {
+			c.order.Remove(el)
+			delete(c.items, el.Value.(*entry).key)
+		}
+	}
+	c.items[key] = c.order.PushFront(&entry{key: key, val: val})
+}
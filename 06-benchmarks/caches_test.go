@@ -0,0 +1,144 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"testing"
+
+	bcache "go-generics-the-hard-way/06-benchmarks/caches/boxed"
+	gcache "go-generics-the-hard-way/pkg/cache"
+	"go-generics-the-hard-way/pkg/hasher"
+)
+
+// cacheSize is the capacity of the caches. The Put benchmarks use twice as
+// many keys so roughly half of the puts cause an eviction.
+const cacheSize = 1 << 12
+
+func BenchmarkCacheGet(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		b.ReportAllocs()
+		c := bcache.New(cacheSize)
+		for i := 0; i < cacheSize; i++ {
+			c.Put(i, i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if v, ok := c.Get(i % cacheSize); ok {
+				_ = v.(int)
+			}
+		}
+	})
+	b.Run("generic-lru", func(b *testing.B) {
+		b.ReportAllocs()
+		c := gcache.New(gcache.Options[int, int]{Capacity: cacheSize})
+		for i := 0; i < cacheSize; i++ {
+			c.Put(i, i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = c.Get(i % cacheSize)
+		}
+	})
+	b.Run("generic-lfu", func(b *testing.B) {
+		b.ReportAllocs()
+		c := gcache.New(gcache.Options[int, int]{
+			Capacity: cacheSize,
+			Policy:   gcache.LFU,
+		})
+		for i := 0; i < cacheSize; i++ {
+			c.Put(i, i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = c.Get(i % cacheSize)
+		}
+	})
+}
+
+func BenchmarkCachePut(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		b.ReportAllocs()
+		c := bcache.New(cacheSize)
+		for i := 0; i < b.N; i++ {
+			c.Put(i%(2*cacheSize), i)
+		}
+	})
+	b.Run("generic-lru", func(b *testing.B) {
+		b.ReportAllocs()
+		c := gcache.New(gcache.Options[int, int]{Capacity: cacheSize})
+		for i := 0; i < b.N; i++ {
+			c.Put(i%(2*cacheSize), i)
+		}
+	})
+	b.Run("generic-lfu", func(b *testing.B) {
+		b.ReportAllocs()
+		c := gcache.New(gcache.Options[int, int]{
+			Capacity: cacheSize,
+			Policy:   gcache.LFU,
+		})
+		for i := 0; i < b.N; i++ {
+			c.Put(i%(2*cacheSize), i)
+		}
+	})
+}
+
+func BenchmarkCacheParallelGet(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		b.ReportAllocs()
+		c := bcache.New(cacheSize)
+		for i := 0; i < cacheSize; i++ {
+			c.Put(i, i)
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				if v, ok := c.Get(i % cacheSize); ok {
+					_ = v.(int)
+				}
+			}
+		})
+	})
+	b.Run("generic", func(b *testing.B) {
+		b.ReportAllocs()
+		c := gcache.New(gcache.Options[int, int]{Capacity: cacheSize})
+		for i := 0; i < cacheSize; i++ {
+			c.Put(i, i)
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				_, _ = c.Get(i % cacheSize)
+			}
+		})
+	})
+	b.Run("generic-sharded", func(b *testing.B) {
+		b.ReportAllocs()
+		c := gcache.NewSharded(
+			16,
+			hasher.Integer[int],
+			gcache.Options[int, int]{Capacity: cacheSize})
+		for i := 0; i < cacheSize; i++ {
+			c.Put(i, i)
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				_, _ = c.Get(i % cacheSize)
+			}
+		})
+	})
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/concurrent"
+	"go-generics-the-hard-way/pkg/hasher"
+)
+
+// concurrentProcs are the values of GOMAXPROCS used by the contention
+// benchmarks. Values greater than the number of CPUs are skipped.
+var concurrentProcs = []int{1, 2, 4, 8, 16}
+
+// concurrentKeys is the number of distinct keys used by the map benchmarks.
+const concurrentKeys = 1 << 12
+
+// withProcs runs fn as a sub-benchmark for each value in concurrentProcs
+// with GOMAXPROCS set to that value.
+func withProcs(b *testing.B, fn func(b *testing.B)) {
+	for _, procs := range concurrentProcs {
+		if procs > runtime.NumCPU() {
+			continue
+		}
+		procs := procs
+		b.Run(fmt.Sprintf("procs-%d", procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			fn(b)
+		})
+	}
+}
+
+// BenchmarkSyncMap has each goroutine perform nine loads for every store.
+func BenchmarkSyncMap(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		withProcs(b, func(b *testing.B) {
+			var m sync.Map
+			for i := 0; i < concurrentKeys; i++ {
+				m.Store(i, i)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					k := (i * 7919) % concurrentKeys
+					if i%10 == 0 {
+						m.Store(k, i)
+					} else if v, ok := m.Load(k); ok {
+						_ = v.(int)
+					}
+				}
+			})
+		})
+	})
+	b.Run("generic-wrapper", func(b *testing.B) {
+		withProcs(b, func(b *testing.B) {
+			var m concurrent.Map[int, int]
+			for i := 0; i < concurrentKeys; i++ {
+				m.Store(i, i)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					k := (i * 7919) % concurrentKeys
+					if i%10 == 0 {
+						m.Store(k, i)
+					} else {
+						_, _ = m.Load(k)
+					}
+				}
+			})
+		})
+	})
+	b.Run("generic-sharded", func(b *testing.B) {
+		withProcs(b, func(b *testing.B) {
+			m := concurrent.NewSyncMap[int, int](
+				4*runtime.GOMAXPROCS(0), hasher.Integer[int])
+			for i := 0; i < concurrentKeys; i++ {
+				m.Store(i, i)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					k := (i * 7919) % concurrentKeys
+					if i%10 == 0 {
+						m.Store(k, i)
+					} else {
+						_, _ = m.Load(k)
+					}
+				}
+			})
+		})
+	})
+}
+
+type valueConfig struct {
+	Host string
+	Port int
+}
+
+// BenchmarkAtomicValue has each goroutine perform 99 loads for every store.
+func BenchmarkAtomicValue(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		withProcs(b, func(b *testing.B) {
+			var v atomic.Value
+			v.Store(valueConfig{"local", 80})
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if i%100 == 0 {
+						v.Store(valueConfig{"local", i})
+					} else {
+						_ = v.Load().(valueConfig)
+					}
+				}
+			})
+		})
+	})
+	b.Run("generic", func(b *testing.B) {
+		withProcs(b, func(b *testing.B) {
+			var v concurrent.Value[valueConfig]
+			v.Store(valueConfig{"local", 80})
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if i%100 == 0 {
+						v.Store(valueConfig{"local", i})
+					} else {
+						_ = v.Load()
+					}
+				}
+			})
+		})
+	})
+}
+
+func BenchmarkCounter(b *testing.B) {
+	b.Run("atomic", func(b *testing.B) {
+		withProcs(b, func(b *testing.B) {
+			var n int64
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					atomic.AddInt64(&n, 1)
+				}
+			})
+		})
+	})
+	b.Run("generic-sharded", func(b *testing.B) {
+		withProcs(b, func(b *testing.B) {
+			c := concurrent.NewCounter[int64]()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					c.Inc()
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"sync"
+)
+
+// Handler is called with the events published to a topic. The event is
+// boxed in the empty interface.
+type Handler func(event interface{})
+
+// Bus is an event bus built the way it had to be before generics: topics are
+// named by strings and the events are boxed.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func New() *Bus {
+	return &Bus{handlers: map[string][]Handler{}}
+}
+
+func (b *Bus) Subscribe(topic string, fn Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], fn)
+}
+
+// Publish calls the handlers for the topic.
+//
+// Please note that event will be boxed in order to pass it into the method
+// using the empty interface, and each handler must assert it back to the
+// type it expects.
+func (b *Bus) Publish(topic string, event interface{}) {
+	b.mu.RLock()
+	handlers := b.handlers[topic]
+	b.mu.RUnlock()
+	for _, fn := range handlers {
+		fn(event)
+	}
+}
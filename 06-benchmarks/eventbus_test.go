@@ -0,0 +1,70 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"testing"
+
+	beventbus "go-generics-the-hard-way/06-benchmarks/eventbus/boxed"
+	geventbus "go-generics-the-hard-way/pkg/eventbus"
+)
+
+type priceChanged struct {
+	SKU      string
+	Old, New float64
+}
+
+func BenchmarkEventBus(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		bus := beventbus.New()
+		var total float64
+		bus.Subscribe("priceChanged", func(event interface{}) {
+			e := event.(priceChanged)
+			total += e.New - e.Old
+		})
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			bus.Publish("priceChanged", priceChanged{SKU: "apple", Old: 1, New: float64(i)})
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		bus := geventbus.New(geventbus.Sync)
+		var total float64
+		geventbus.Subscribe(bus, func(e priceChanged) {
+			total += e.New - e.Old
+		})
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			geventbus.Publish(bus, priceChanged{SKU: "apple", Old: 1, New: float64(i)})
+		}
+	})
+	b.Run("generic-async", func(b *testing.B) {
+		bus := geventbus.New(geventbus.Async)
+		var total float64
+		geventbus.Subscribe(bus, func(e priceChanged) {
+			total += e.New - e.Old
+		})
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			geventbus.Publish(bus, priceChanged{SKU: "apple", Old: 1, New: float64(i)})
+		}
+		bus.Wait()
+	})
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lists/gen. DO NOT EDIT.
+
+package benchmarks_test
+
+// gobuildGeneratedTestCases are the BenchmarkGoBuild cases for the generic
+// and typed lists generated by lists/gen.
+var gobuildGeneratedTestCases = []gobuildTestCase{
+	{
+		name:     "generic",
+		listType: "generic",
+		testGrps: []gobuildSubTestGroup{
+			{
+				name:     "bin",
+				fileType: ".bin",
+				subTests: []gobuildSubTestCase{
+					{
+						name:     "0-types",
+						args:     []string{"build", "-a", "-o", "generic-0-types.bin", "./lists/generic/n000/cmd/"},
+						filePath: "generic-0-types.bin",
+					},
+					{
+						name:     "1-types",
+						args:     []string{"build", "-a", "-o", "generic-1-types.bin", "./lists/generic/n001/cmd/"},
+						filePath: "generic-1-types.bin",
+					},
+					{
+						name:     "2-types",
+						args:     []string{"build", "-a", "-o", "generic-2-types.bin", "./lists/generic/n002/cmd/"},
+						filePath: "generic-2-types.bin",
+					},
+					{
+						name:     "3-types",
+						args:     []string{"build", "-a", "-o", "generic-3-types.bin", "./lists/generic/n003/cmd/"},
+						filePath: "generic-3-types.bin",
+					},
+					{
+						name:     "4-types",
+						args:     []string{"build", "-a", "-o", "generic-4-types.bin", "./lists/generic/n004/cmd/"},
+						filePath: "generic-4-types.bin",
+					},
+					{
+						name:     "5-types",
+						args:     []string{"build", "-a", "-o", "generic-5-types.bin", "./lists/generic/n005/cmd/"},
+						filePath: "generic-5-types.bin",
+					},
+					{
+						name:     "10-types",
+						args:     []string{"build", "-a", "-o", "generic-10-types.bin", "./lists/generic/n010/cmd/"},
+						filePath: "generic-10-types.bin",
+					},
+					{
+						name:     "25-types",
+						args:     []string{"build", "-a", "-o", "generic-25-types.bin", "./lists/generic/n025/cmd/"},
+						filePath: "generic-25-types.bin",
+					},
+					{
+						name:     "50-types",
+						args:     []string{"build", "-a", "-o", "generic-50-types.bin", "./lists/generic/n050/cmd/"},
+						filePath: "generic-50-types.bin",
+					},
+					{
+						name:     "100-types",
+						args:     []string{"build", "-a", "-o", "generic-100-types.bin", "./lists/generic/n100/cmd/"},
+						filePath: "generic-100-types.bin",
+					},
+				},
+			},
+			{
+				name:     "pkg",
+				fileType: ".a",
+				subTests: []gobuildSubTestCase{
+					{
+						name:     "0-types",
+						args:     []string{"build", "-a", "-o", "generic-0-types.a", "./lists/generic/n000/"},
+						filePath: "generic-0-types.a",
+					},
+					{
+						name:     "1-types",
+						args:     []string{"build", "-a", "-o", "generic-1-types.a", "./lists/generic/n001/"},
+						filePath: "generic-1-types.a",
+					},
+					{
+						name:     "2-types",
+						args:     []string{"build", "-a", "-o", "generic-2-types.a", "./lists/generic/n002/"},
+						filePath: "generic-2-types.a",
+					},
+					{
+						name:     "3-types",
+						args:     []string{"build", "-a", "-o", "generic-3-types.a", "./lists/generic/n003/"},
+						filePath: "generic-3-types.a",
+					},
+					{
+						name:     "4-types",
+						args:     []string{"build", "-a", "-o", "generic-4-types.a", "./lists/generic/n004/"},
+						filePath: "generic-4-types.a",
+					},
+					{
+						name:     "5-types",
+						args:     []string{"build", "-a", "-o", "generic-5-types.a", "./lists/generic/n005/"},
+						filePath: "generic-5-types.a",
+					},
+					{
+						name:     "10-types",
+						args:     []string{"build", "-a", "-o", "generic-10-types.a", "./lists/generic/n010/"},
+						filePath: "generic-10-types.a",
+					},
+					{
+						name:     "25-types",
+						args:     []string{"build", "-a", "-o", "generic-25-types.a", "./lists/generic/n025/"},
+						filePath: "generic-25-types.a",
+					},
+					{
+						name:     "50-types",
+						args:     []string{"build", "-a", "-o", "generic-50-types.a", "./lists/generic/n050/"},
+						filePath: "generic-50-types.a",
+					},
+					{
+						name:     "100-types",
+						args:     []string{"build", "-a", "-o", "generic-100-types.a", "./lists/generic/n100/"},
+						filePath: "generic-100-types.a",
+					},
+				},
+			},
+		},
+	},
+	{
+		name:     "typed",
+		listType: "typed",
+		testGrps: []gobuildSubTestGroup{
+			{
+				name:     "bin",
+				fileType: ".bin",
+				subTests: []gobuildSubTestCase{
+					{
+						name:     "0-types",
+						args:     []string{"build", "-a", "-o", "typed-0-types.bin", "./lists/typed/n000/cmd/"},
+						filePath: "typed-0-types.bin",
+					},
+					{
+						name:     "1-types",
+						args:     []string{"build", "-a", "-o", "typed-1-types.bin", "./lists/typed/n001/cmd/"},
+						filePath: "typed-1-types.bin",
+					},
+					{
+						name:     "2-types",
+						args:     []string{"build", "-a", "-o", "typed-2-types.bin", "./lists/typed/n002/cmd/"},
+						filePath: "typed-2-types.bin",
+					},
+					{
+						name:     "3-types",
+						args:     []string{"build", "-a", "-o", "typed-3-types.bin", "./lists/typed/n003/cmd/"},
+						filePath: "typed-3-types.bin",
+					},
+					{
+						name:     "4-types",
+						args:     []string{"build", "-a", "-o", "typed-4-types.bin", "./lists/typed/n004/cmd/"},
+						filePath: "typed-4-types.bin",
+					},
+					{
+						name:     "5-types",
+						args:     []string{"build", "-a", "-o", "typed-5-types.bin", "./lists/typed/n005/cmd/"},
+						filePath: "typed-5-types.bin",
+					},
+					{
+						name:     "10-types",
+						args:     []string{"build", "-a", "-o", "typed-10-types.bin", "./lists/typed/n010/cmd/"},
+						filePath: "typed-10-types.bin",
+					},
+					{
+						name:     "25-types",
+						args:     []string{"build", "-a", "-o", "typed-25-types.bin", "./lists/typed/n025/cmd/"},
+						filePath: "typed-25-types.bin",
+					},
+					{
+						name:     "50-types",
+						args:     []string{"build", "-a", "-o", "typed-50-types.bin", "./lists/typed/n050/cmd/"},
+						filePath: "typed-50-types.bin",
+					},
+					{
+						name:     "100-types",
+						args:     []string{"build", "-a", "-o", "typed-100-types.bin", "./lists/typed/n100/cmd/"},
+						filePath: "typed-100-types.bin",
+					},
+				},
+			},
+			{
+				name:     "pkg",
+				fileType: ".a",
+				subTests: []gobuildSubTestCase{
+					{
+						name:     "0-types",
+						args:     []string{"build", "-a", "-o", "typed-0-types.a", "./lists/typed/n000/"},
+						filePath: "typed-0-types.a",
+					},
+					{
+						name:     "1-types",
+						args:     []string{"build", "-a", "-o", "typed-1-types.a", "./lists/typed/n001/"},
+						filePath: "typed-1-types.a",
+					},
+					{
+						name:     "2-types",
+						args:     []string{"build", "-a", "-o", "typed-2-types.a", "./lists/typed/n002/"},
+						filePath: "typed-2-types.a",
+					},
+					{
+						name:     "3-types",
+						args:     []string{"build", "-a", "-o", "typed-3-types.a", "./lists/typed/n003/"},
+						filePath: "typed-3-types.a",
+					},
+					{
+						name:     "4-types",
+						args:     []string{"build", "-a", "-o", "typed-4-types.a", "./lists/typed/n004/"},
+						filePath: "typed-4-types.a",
+					},
+					{
+						name:     "5-types",
+						args:     []string{"build", "-a", "-o", "typed-5-types.a", "./lists/typed/n005/"},
+						filePath: "typed-5-types.a",
+					},
+					{
+						name:     "10-types",
+						args:     []string{"build", "-a", "-o", "typed-10-types.a", "./lists/typed/n010/"},
+						filePath: "typed-10-types.a",
+					},
+					{
+						name:     "25-types",
+						args:     []string{"build", "-a", "-o", "typed-25-types.a", "./lists/typed/n025/"},
+						filePath: "typed-25-types.a",
+					},
+					{
+						name:     "50-types",
+						args:     []string{"build", "-a", "-o", "typed-50-types.a", "./lists/typed/n050/"},
+						filePath: "typed-50-types.a",
+					},
+					{
+						name:     "100-types",
+						args:     []string{"build", "-a", "-o", "typed-100-types.a", "./lists/typed/n100/"},
+						filePath: "typed-100-types.a",
+					},
+				},
+			},
+		},
+	},
+}
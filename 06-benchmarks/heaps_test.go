@@ -0,0 +1,88 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"container/heap"
+	"math/rand"
+	"testing"
+
+	bheap "go-generics-the-hard-way/06-benchmarks/heaps/boxed"
+	theap "go-generics-the-hard-way/06-benchmarks/heaps/typed"
+	gheap "go-generics-the-hard-way/pkg/heap"
+)
+
+// heapSize is the number of values a heap holds while it is benchmarked.
+const heapSize = 1024
+
+// heapVals are the pseudo-random values pushed onto the heaps. The values
+// are large enough that boxing them requires an allocation.
+var heapVals = func() []int {
+	r := rand.New(rand.NewSource(0))
+	vals := make([]int, heapSize*4)
+	for i := range vals {
+		vals[i] = 1024 + r.Intn(1<<20)
+	}
+	return vals
+}()
+
+func BenchmarkHeap(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		h := &bheap.IntHeap{}
+		for i := 0; i < heapSize; i++ {
+			heap.Push(h, heapVals[i])
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			heap.Push(h, heapVals[i%len(heapVals)])
+			_ = heap.Pop(h).(int)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		h := gheap.NewOrdered[int]()
+		for i := 0; i < heapSize; i++ {
+			h.Push(heapVals[i])
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			h.Push(heapVals[i%len(heapVals)])
+			_, _ = h.Pop()
+		}
+	})
+	b.Run("generic-stable", func(b *testing.B) {
+		h := gheap.NewStable(gheap.Less[int])
+		for i := 0; i < heapSize; i++ {
+			h.Push(heapVals[i])
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			h.Push(heapVals[i%len(heapVals)])
+			_, _ = h.Pop()
+		}
+	})
+	b.Run("typed", func(b *testing.B) {
+		h := &theap.IntHeap{}
+		for i := 0; i < heapSize; i++ {
+			h.Push(heapVals[i])
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			h.Push(heapVals[i%len(heapVals)])
+			_ = h.Pop()
+		}
+	})
+}
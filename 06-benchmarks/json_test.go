@@ -0,0 +1,208 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/typedjson"
+)
+
+type jsonItem struct {
+	SKU      string  `json:"sku"`
+	Quantity int     `json:"qty"`
+	Price    float64 `json:"price"`
+}
+
+type jsonOrder struct {
+	ID       string     `json:"id"`
+	Customer string     `json:"customer"`
+	Items    []jsonItem `json:"items"`
+	Amounts  []int64    `json:"amounts"`
+	Discount *float64   `json:"discount,omitempty"`
+	Paid     bool       `json:"paid"`
+}
+
+func newJSONOrder() *jsonOrder {
+	discount := 0.15
+	o := &jsonOrder{
+		ID:       "order-42",
+		Customer: "Ada <ada@example.com>",
+		Discount: &discount,
+		Paid:     true,
+	}
+	for i := 0; i < 16; i++ {
+		o.Items = append(o.Items, jsonItem{
+			SKU:      "sku-" + strconv.Itoa(i),
+			Quantity: i + 1,
+			Price:    float64(i) * 1.25,
+		})
+		o.Amounts = append(o.Amounts, int64(i)*1000)
+	}
+	return o
+}
+
+func newJSONMap() *map[string]int {
+	m := map[string]int{}
+	for i := 0; i < 64; i++ {
+		m["key-"+strconv.Itoa(i)] = i
+	}
+	return &m
+}
+
+// BenchmarkJSONEncode compares encoding/json, which takes its argument as an
+// interface{}, with typedjson.Encoder[T]. The generic-append case encodes
+// into a reused buffer, so any remaining allocations are due to the encoding
+// itself rather than to the result.
+func BenchmarkJSONEncode(b *testing.B) {
+	b.Run("struct", func(b *testing.B) {
+		benchmarkJSONEncode(b, newJSONOrder())
+	})
+	b.Run("map", func(b *testing.B) {
+		benchmarkJSONEncode(b, newJSONMap())
+	})
+}
+
+func benchmarkJSONEncode[T any](b *testing.B, v *T) {
+	b.Run("boxed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		enc, err := typedjson.NewEncoder[T]()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := enc.Marshal(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("generic-append", func(b *testing.B) {
+		enc, err := typedjson.NewEncoder[T]()
+		if err != nil {
+			b.Fatal(err)
+		}
+		var buf []byte
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if buf, err = enc.Append(buf[:0], v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkJSONDecode compares json.Unmarshal with typedjson.Unmarshal[T],
+// both decoding into a reused value, and with typedjson.Decode[T], which
+// returns a new value.
+func BenchmarkJSONDecode(b *testing.B) {
+	b.Run("struct", func(b *testing.B) {
+		benchmarkJSONDecode[jsonOrder](b, newJSONOrder())
+	})
+	b.Run("map", func(b *testing.B) {
+		benchmarkJSONDecode[map[string]int](b, newJSONMap())
+	})
+}
+
+func benchmarkJSONDecode[T any](b *testing.B, v *T) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("boxed", func(b *testing.B) {
+		var v T
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := json.Unmarshal(data, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		var v T
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := typedjson.Unmarshal(data, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("generic-decode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := typedjson.Decode[T](data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkJSONDecodeStream compares json.Decoder with typedjson.Decoder[T]
+// decoding a stream of newline-delimited values. Each op is one value.
+func BenchmarkJSONDecodeStream(b *testing.B) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := 0; i < 1000; i++ {
+		if err := enc.Encode(newJSONOrder()); err != nil {
+			b.Fatal(err)
+		}
+	}
+	data := buf.Bytes()
+
+	b.Run("boxed", func(b *testing.B) {
+		b.ReportAllocs()
+		var dec *json.Decoder
+		var v jsonOrder
+		for i := 0; i < b.N; i++ {
+			if i%1000 == 0 {
+				dec = json.NewDecoder(bytes.NewReader(data))
+			}
+			if err := dec.Decode(&v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		b.ReportAllocs()
+		var dec *typedjson.Decoder[jsonOrder]
+		var v jsonOrder
+		for i := 0; i < b.N; i++ {
+			if i%1000 == 0 {
+				var err error
+				if dec, err = typedjson.NewDecoder[jsonOrder](bytes.NewReader(data), typedjson.NDJSON); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if err := dec.Decode(&v); err != nil && err != io.EOF {
+				b.Fatal(err)
+			}
+		}
+	})
+}
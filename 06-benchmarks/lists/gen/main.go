@@ -0,0 +1,373 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command gen generates the fixtures for BenchmarkGoBuild.
+//
+// For each number of types in -counts, gen writes two packages, each with a
+// command that uses every list the package defines:
+//
+//   - generic/nNNN: List[T any] and an instantiation of it for each of the
+//     first NNN types in the -types file
+//   - typed/nNNN: a list type, such as IntList, for each of the first NNN
+//     types in the -types file
+//
+// gen also writes the BenchmarkGoBuild cases that build them, so the
+// number of instantiations that are measured is limited only by the number
+// of types in the -types file.
+//
+// The -types file has one Go type per line, and blank lines and lines that
+// begin with # are ignored. For the command to measure one instantiation per
+// type, every type should have a different underlying type, otherwise
+// instantiations share a GC shape.
+//
+// gen is run by go generate from the lists directory:
+//
+//	go generate ./06-benchmarks/lists
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+var (
+	typesFile  = flag.String("types", "types.txt", "the file that lists the element types")
+	countsFlag = flag.String("counts", "0,1,2,3,4,5", "comma-separated numbers of types to generate fixtures for")
+	benchFile  = flag.String("bench", "../gobuild_generated_test.go", "the file to write the BenchmarkGoBuild cases to")
+	importPath = flag.String("import", "go-generics-the-hard-way/06-benchmarks/lists", "the import path of the lists directory")
+)
+
+// elemType is a type from the -types file.
+type elemType struct {
+
+	// Type is the Go type, ex. int8 or [2]int8.
+	Type string
+
+	// Name is the exported identifier derived from Type, ex. Int8 or
+	// Array2Int8.
+	Name string
+
+	// Value is an expression of type Type that is added to a list.
+	Value string
+}
+
+// VarName returns the name of the variable for a list of the type.
+func (t elemType) VarName() string {
+	return strings.ToLower(t.Name[:1]) + t.Name[1:] + "List"
+}
+
+// fixture is a package, and its command, with a number of list types.
+type fixture struct {
+	Header     string
+	ListType   string // generic or typed
+	Dir        string // ex. n005
+	ImportPath string
+	Types      []elemType
+}
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	types, err := readTypes(*typesFile)
+	if err != nil {
+		return err
+	}
+	counts, err := parseCounts(*countsFlag, len(types))
+	if err != nil {
+		return err
+	}
+
+	listTypes := []string{"generic", "typed"}
+	for _, listType := range listTypes {
+		if err := removeFixtures(listType); err != nil {
+			return err
+		}
+		for _, n := range counts {
+			dir := fmt.Sprintf("n%03d", n)
+			f := fixture{
+				Header:     header,
+				ListType:   listType,
+				Dir:        dir,
+				ImportPath: *importPath + "/" + listType + "/" + dir,
+				Types:      types[:n],
+			}
+			if err := writeFixture(f); err != nil {
+				return err
+			}
+		}
+	}
+	return writeGoFile(*benchFile, benchTemplate, struct {
+		Header    string
+		ListTypes []string
+		Counts    []int
+	}{header, listTypes, counts})
+}
+
+// readTypes reads the element types from the named file.
+func readTypes(name string) ([]elemType, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		types []elemType
+		seen  = map[string]bool{}
+	)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		typ := strings.TrimSpace(scanner.Text())
+		if typ == "" || strings.HasPrefix(typ, "#") {
+			continue
+		}
+		t := elemType{Type: typ, Name: typeName(typ), Value: typeValue(typ)}
+		if t.Name == "" {
+			return nil, fmt.Errorf("%s:%d: cannot name type %q", name, line, typ)
+		}
+		if seen[t.Name] {
+			return nil, fmt.Errorf("%s:%d: duplicate type %q", name, line, typ)
+		}
+		seen[t.Name] = true
+		types = append(types, t)
+	}
+	return types, scanner.Err()
+}
+
+func parseCounts(s string, max int) ([]int, error) {
+	var counts []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, fmt.Errorf("invalid count %q", f)
+		}
+		if n < 0 || n > max || n > 999 {
+			return nil, fmt.Errorf("count %d is not between 0 and the %d types", n, max)
+		}
+		counts = append(counts, n)
+	}
+	return counts, nil
+}
+
+var arrayPrefix = regexp.MustCompile(`^\[(\d+)\]`)
+
+// typeName derives an exported identifier from a type, ex. Int8 from int8,
+// Array2Int8 from [2]int8, and SliceString from []string.
+func typeName(typ string) string {
+	var b strings.Builder
+	for typ != "" {
+		switch {
+		case strings.HasPrefix(typ, "[]"):
+			b.WriteString("Slice")
+			typ = typ[2:]
+		case arrayPrefix.MatchString(typ):
+			m := arrayPrefix.FindStringSubmatch(typ)
+			b.WriteString("Array" + m[1])
+			typ = typ[len(m[0]):]
+		case strings.HasPrefix(typ, "*"):
+			b.WriteString("Ptr")
+			typ = typ[1:]
+		default:
+			for _, f := range strings.FieldsFunc(typ, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			}) {
+				b.WriteString(strings.ToUpper(f[:1]) + f[1:])
+			}
+			typ = ""
+		}
+	}
+	return b.String()
+}
+
+// typeValue returns an expression of the given type.
+func typeValue(typ string) string {
+	switch typ {
+	case "bool":
+		return "true"
+	case "string":
+		return `"1"`
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128", "byte", "rune":
+		return "1"
+	}
+	if arrayPrefix.MatchString(typ) {
+		return typ + "{}"
+	}
+	return "*new(" + typ + ")"
+}
+
+// removeFixtures removes the fixtures gen previously wrote for the list type
+// so fixtures for counts that are no longer generated do not linger.
+func removeFixtures(listType string) error {
+	dirs, err := filepath.Glob(filepath.Join(listType, "n[0-9][0-9][0-9]"))
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFixture(f fixture) error {
+	pkg := genericTemplate
+	if f.ListType == "typed" {
+		pkg = typedTemplate
+	}
+	if err := writeGoFile(filepath.Join(f.ListType, f.Dir, "list.go"), pkg, f); err != nil {
+		return err
+	}
+	return writeGoFile(filepath.Join(f.ListType, f.Dir, "cmd", "main.go"), cmdTemplate, f)
+}
+
+// writeGoFile executes tmpl with data and writes the formatted result to
+// the named file.
+func writeGoFile(name string, tmpl *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %w\n%s", name, err, buf.Bytes())
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, src, 0o644)
+}
+
+const header = `/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lists/gen. DO NOT EDIT.
+`
+
+var genericTemplate = template.Must(template.New("generic").Parse(`{{.Header}}
+package list
+
+type List[T any] []T
+
+func (l *List[T]) Add(val T) {
+	*l = append(*l, val)
+}
+{{range .Types}}
+var _ List[{{.Type}}]
+{{- end}}
+`))
+
+var typedTemplate = template.Must(template.New("typed").Parse(`{{.Header}}
+package list
+{{range .Types}}
+type {{.Name}}List []{{.Type}}
+
+func (l *{{.Name}}List) Add(val {{.Type}}) {
+	*l = append(*l, val)
+}
+{{end}}`))
+
+var cmdTemplate = template.Must(template.New("cmd").Parse(`{{.Header}}
+package main
+{{if .Types}}
+import (
+	list "{{.ImportPath}}"
+)
+{{end}}
+func main() {
+{{- $generic := eq .ListType "generic"}}
+{{- range $i, $t := .Types}}
+{{- if $i}}
+{{end}}
+	var {{$t.VarName}} list.{{if $generic}}List[{{$t.Type}}]{{else}}{{$t.Name}}List{{end}}
+	{{$t.VarName}}.Add({{$t.Value}})
+{{- end}}
+}
+`))
+
+var benchTemplate = template.Must(template.New("bench").Parse(`{{.Header}}
+package benchmarks_test
+
+// gobuildGeneratedTestCases are the BenchmarkGoBuild cases for the generic
+// and typed lists generated by lists/gen.
+var gobuildGeneratedTestCases = []gobuildTestCase{
+{{- range $listType := .ListTypes}}
+	{
+		name:     "{{$listType}}",
+		listType: "{{$listType}}",
+		testGrps: []gobuildSubTestGroup{
+			{
+				name:     "bin",
+				fileType: ".bin",
+				subTests: []gobuildSubTestCase{
+{{- range $.Counts}}
+					{
+						name:     "{{.}}-types",
+						args:     []string{"build", "-a", "-o", "{{$listType}}-{{.}}-types.bin", "./lists/{{$listType}}/{{printf "n%03d" .}}/cmd/"},
+						filePath: "{{$listType}}-{{.}}-types.bin",
+					},
+{{- end}}
+				},
+			},
+			{
+				name:     "pkg",
+				fileType: ".a",
+				subTests: []gobuildSubTestCase{
+{{- range $.Counts}}
+					{
+						name:     "{{.}}-types",
+						args:     []string{"build", "-a", "-o", "{{$listType}}-{{.}}-types.a", "./lists/{{$listType}}/{{printf "n%03d" .}}/"},
+						filePath: "{{$listType}}-{{.}}-types.a",
+					},
+{{- end}}
+				},
+			},
+		},
+	},
+{{- end}}
+}
+`))
@@ -1,6 +1,3 @@
-//go:build int16
-// +build int16
-
 /*
 Copyright 2022
 
@@ -17,10 +14,12 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Code generated by lists/gen. DO NOT EDIT.
+
 package list
 
-type Int16List []int16
+type List[T any] []T
 
-func (l *Int16List) Add(val int16) {
+func (l *List[T]) Add(val T) {
 	*l = append(*l, val)
 }
@@ -1,6 +1,3 @@
-//go:build int64
-// +build int64
-
 /*
 Copyright 2022
 
@@ -17,10 +14,14 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Code generated by lists/gen. DO NOT EDIT.
+
 package list
 
-type Int64List []int64
+type List[T any] []T
 
-func (l *Int64List) Add(val int64) {
+func (l *List[T]) Add(val T) {
 	*l = append(*l, val)
 }
+
+var _ List[int]
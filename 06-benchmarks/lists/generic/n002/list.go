@@ -1,6 +1,3 @@
-//go:build int32
-// +build int32
-
 /*
 Copyright 2022
 
@@ -17,10 +14,15 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Code generated by lists/gen. DO NOT EDIT.
+
 package list
 
-type Int32List []int32
+type List[T any] []T
 
-func (l *Int32List) Add(val int32) {
+func (l *List[T]) Add(val T) {
 	*l = append(*l, val)
 }
+
+var _ List[int]
+var _ List[int8]
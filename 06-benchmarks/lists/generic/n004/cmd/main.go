@@ -1,6 +1,3 @@
-//go:build int && int8 && int16 && int32 && !int64
-// +build int,int8,int16,int32,!int64
-
 /*
 Copyright 2022
 
@@ -17,10 +14,12 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Code generated by lists/gen. DO NOT EDIT.
+
 package main
 
 import (
-	list "go-generics-the-hard-way/06-benchmarks/lists/generic"
+	list "go-generics-the-hard-way/06-benchmarks/lists/generic/n004"
 )
 
 func main() {
@@ -0,0 +1,175 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lists/gen. DO NOT EDIT.
+
+package main
+
+import (
+	list "go-generics-the-hard-way/06-benchmarks/lists/generic/n050"
+)
+
+func main() {
+	var intList list.List[int]
+	intList.Add(1)
+
+	var int8List list.List[int8]
+	int8List.Add(1)
+
+	var int16List list.List[int16]
+	int16List.Add(1)
+
+	var int32List list.List[int32]
+	int32List.Add(1)
+
+	var int64List list.List[int64]
+	int64List.Add(1)
+
+	var uintList list.List[uint]
+	uintList.Add(1)
+
+	var uint8List list.List[uint8]
+	uint8List.Add(1)
+
+	var uint16List list.List[uint16]
+	uint16List.Add(1)
+
+	var uint32List list.List[uint32]
+	uint32List.Add(1)
+
+	var uint64List list.List[uint64]
+	uint64List.Add(1)
+
+	var uintptrList list.List[uintptr]
+	uintptrList.Add(1)
+
+	var float32List list.List[float32]
+	float32List.Add(1)
+
+	var float64List list.List[float64]
+	float64List.Add(1)
+
+	var complex64List list.List[complex64]
+	complex64List.Add(1)
+
+	var complex128List list.List[complex128]
+	complex128List.Add(1)
+
+	var stringList list.List[string]
+	stringList.Add("1")
+
+	var boolList list.List[bool]
+	boolList.Add(true)
+
+	var array1Int8List list.List[[1]int8]
+	array1Int8List.Add([1]int8{})
+
+	var array2Int8List list.List[[2]int8]
+	array2Int8List.Add([2]int8{})
+
+	var array3Int8List list.List[[3]int8]
+	array3Int8List.Add([3]int8{})
+
+	var array4Int8List list.List[[4]int8]
+	array4Int8List.Add([4]int8{})
+
+	var array5Int8List list.List[[5]int8]
+	array5Int8List.Add([5]int8{})
+
+	var array6Int8List list.List[[6]int8]
+	array6Int8List.Add([6]int8{})
+
+	var array7Int8List list.List[[7]int8]
+	array7Int8List.Add([7]int8{})
+
+	var array8Int8List list.List[[8]int8]
+	array8Int8List.Add([8]int8{})
+
+	var array9Int8List list.List[[9]int8]
+	array9Int8List.Add([9]int8{})
+
+	var array10Int8List list.List[[10]int8]
+	array10Int8List.Add([10]int8{})
+
+	var array11Int8List list.List[[11]int8]
+	array11Int8List.Add([11]int8{})
+
+	var array12Int8List list.List[[12]int8]
+	array12Int8List.Add([12]int8{})
+
+	var array13Int8List list.List[[13]int8]
+	array13Int8List.Add([13]int8{})
+
+	var array14Int8List list.List[[14]int8]
+	array14Int8List.Add([14]int8{})
+
+	var array15Int8List list.List[[15]int8]
+	array15Int8List.Add([15]int8{})
+
+	var array16Int8List list.List[[16]int8]
+	array16Int8List.Add([16]int8{})
+
+	var array17Int8List list.List[[17]int8]
+	array17Int8List.Add([17]int8{})
+
+	var array18Int8List list.List[[18]int8]
+	array18Int8List.Add([18]int8{})
+
+	var array19Int8List list.List[[19]int8]
+	array19Int8List.Add([19]int8{})
+
+	var array20Int8List list.List[[20]int8]
+	array20Int8List.Add([20]int8{})
+
+	var array21Int8List list.List[[21]int8]
+	array21Int8List.Add([21]int8{})
+
+	var array22Int8List list.List[[22]int8]
+	array22Int8List.Add([22]int8{})
+
+	var array23Int8List list.List[[23]int8]
+	array23Int8List.Add([23]int8{})
+
+	var array24Int8List list.List[[24]int8]
+	array24Int8List.Add([24]int8{})
+
+	var array25Int8List list.List[[25]int8]
+	array25Int8List.Add([25]int8{})
+
+	var array26Int8List list.List[[26]int8]
+	array26Int8List.Add([26]int8{})
+
+	var array27Int8List list.List[[27]int8]
+	array27Int8List.Add([27]int8{})
+
+	var array28Int8List list.List[[28]int8]
+	array28Int8List.Add([28]int8{})
+
+	var array29Int8List list.List[[29]int8]
+	array29Int8List.Add([29]int8{})
+
+	var array30Int8List list.List[[30]int8]
+	array30Int8List.Add([30]int8{})
+
+	var array31Int8List list.List[[31]int8]
+	array31Int8List.Add([31]int8{})
+
+	var array32Int8List list.List[[32]int8]
+	array32Int8List.Add([32]int8{})
+
+	var array33Int8List list.List[[33]int8]
+	array33Int8List.Add([33]int8{})
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lists contains the list types measured by the benchmarks.
+//
+// The fixtures for BenchmarkGoBuild, generic/nNNN and typed/nNNN, along with
+// the benchmark's cases, are generated by ./gen from the types in types.txt.
+package lists
+
+//go:generate go run ./gen -types types.txt -counts 0,1,2,3,4,5,10,25,50,100
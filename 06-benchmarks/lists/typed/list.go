@@ -1,6 +1,3 @@
-//go:build no_int
-// +build no_int
-
 /*
 Copyright 2022
 
@@ -18,3 +15,9 @@ limitations under the License.
 */
 
 package list
+
+type IntList []int
+
+func (l *IntList) Add(val int) {
+	*l = append(*l, val)
+}
@@ -1,6 +1,3 @@
-//go:build !no_int
-// +build !no_int
-
 /*
 Copyright 2022
 
@@ -17,6 +14,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Code generated by lists/gen. DO NOT EDIT.
+
 package list
 
 type IntList []int
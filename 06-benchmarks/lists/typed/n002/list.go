@@ -1,6 +1,3 @@
-//go:build int8
-// +build int8
-
 /*
 Copyright 2022
 
@@ -17,8 +14,16 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Code generated by lists/gen. DO NOT EDIT.
+
 package list
 
+type IntList []int
+
+func (l *IntList) Add(val int) {
+	*l = append(*l, val)
+}
+
 type Int8List []int8
 
 func (l *Int8List) Add(val int8) {
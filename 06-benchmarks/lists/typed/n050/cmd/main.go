@@ -0,0 +1,175 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lists/gen. DO NOT EDIT.
+
+package main
+
+import (
+	list "go-generics-the-hard-way/06-benchmarks/lists/typed/n050"
+)
+
+func main() {
+	var intList list.IntList
+	intList.Add(1)
+
+	var int8List list.Int8List
+	int8List.Add(1)
+
+	var int16List list.Int16List
+	int16List.Add(1)
+
+	var int32List list.Int32List
+	int32List.Add(1)
+
+	var int64List list.Int64List
+	int64List.Add(1)
+
+	var uintList list.UintList
+	uintList.Add(1)
+
+	var uint8List list.Uint8List
+	uint8List.Add(1)
+
+	var uint16List list.Uint16List
+	uint16List.Add(1)
+
+	var uint32List list.Uint32List
+	uint32List.Add(1)
+
+	var uint64List list.Uint64List
+	uint64List.Add(1)
+
+	var uintptrList list.UintptrList
+	uintptrList.Add(1)
+
+	var float32List list.Float32List
+	float32List.Add(1)
+
+	var float64List list.Float64List
+	float64List.Add(1)
+
+	var complex64List list.Complex64List
+	complex64List.Add(1)
+
+	var complex128List list.Complex128List
+	complex128List.Add(1)
+
+	var stringList list.StringList
+	stringList.Add("1")
+
+	var boolList list.BoolList
+	boolList.Add(true)
+
+	var array1Int8List list.Array1Int8List
+	array1Int8List.Add([1]int8{})
+
+	var array2Int8List list.Array2Int8List
+	array2Int8List.Add([2]int8{})
+
+	var array3Int8List list.Array3Int8List
+	array3Int8List.Add([3]int8{})
+
+	var array4Int8List list.Array4Int8List
+	array4Int8List.Add([4]int8{})
+
+	var array5Int8List list.Array5Int8List
+	array5Int8List.Add([5]int8{})
+
+	var array6Int8List list.Array6Int8List
+	array6Int8List.Add([6]int8{})
+
+	var array7Int8List list.Array7Int8List
+	array7Int8List.Add([7]int8{})
+
+	var array8Int8List list.Array8Int8List
+	array8Int8List.Add([8]int8{})
+
+	var array9Int8List list.Array9Int8List
+	array9Int8List.Add([9]int8{})
+
+	var array10Int8List list.Array10Int8List
+	array10Int8List.Add([10]int8{})
+
+	var array11Int8List list.Array11Int8List
+	array11Int8List.Add([11]int8{})
+
+	var array12Int8List list.Array12Int8List
+	array12Int8List.Add([12]int8{})
+
+	var array13Int8List list.Array13Int8List
+	array13Int8List.Add([13]int8{})
+
+	var array14Int8List list.Array14Int8List
+	array14Int8List.Add([14]int8{})
+
+	var array15Int8List list.Array15Int8List
+	array15Int8List.Add([15]int8{})
+
+	var array16Int8List list.Array16Int8List
+	array16Int8List.Add([16]int8{})
+
+	var array17Int8List list.Array17Int8List
+	array17Int8List.Add([17]int8{})
+
+	var array18Int8List list.Array18Int8List
+	array18Int8List.Add([18]int8{})
+
+	var array19Int8List list.Array19Int8List
+	array19Int8List.Add([19]int8{})
+
+	var array20Int8List list.Array20Int8List
+	array20Int8List.Add([20]int8{})
+
+	var array21Int8List list.Array21Int8List
+	array21Int8List.Add([21]int8{})
+
+	var array22Int8List list.Array22Int8List
+	array22Int8List.Add([22]int8{})
+
+	var array23Int8List list.Array23Int8List
+	array23Int8List.Add([23]int8{})
+
+	var array24Int8List list.Array24Int8List
+	array24Int8List.Add([24]int8{})
+
+	var array25Int8List list.Array25Int8List
+	array25Int8List.Add([25]int8{})
+
+	var array26Int8List list.Array26Int8List
+	array26Int8List.Add([26]int8{})
+
+	var array27Int8List list.Array27Int8List
+	array27Int8List.Add([27]int8{})
+
+	var array28Int8List list.Array28Int8List
+	array28Int8List.Add([28]int8{})
+
+	var array29Int8List list.Array29Int8List
+	array29Int8List.Add([29]int8{})
+
+	var array30Int8List list.Array30Int8List
+	array30Int8List.Add([30]int8{})
+
+	var array31Int8List list.Array31Int8List
+	array31Int8List.Add([31]int8{})
+
+	var array32Int8List list.Array32Int8List
+	array32Int8List.Add([32]int8{})
+
+	var array33Int8List list.Array33Int8List
+	array33Int8List.Add([33]int8{})
+}
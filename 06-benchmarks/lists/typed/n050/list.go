@@ -0,0 +1,319 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lists/gen. DO NOT EDIT.
+
+package list
+
+type IntList []int
+
+func (l *IntList) Add(val int) {
+	*l = append(*l, val)
+}
+
+type Int8List []int8
+
+func (l *Int8List) Add(val int8) {
+	*l = append(*l, val)
+}
+
+type Int16List []int16
+
+func (l *Int16List) Add(val int16) {
+	*l = append(*l, val)
+}
+
+type Int32List []int32
+
+func (l *Int32List) Add(val int32) {
+	*l = append(*l, val)
+}
+
+type Int64List []int64
+
+func (l *Int64List) Add(val int64) {
+	*l = append(*l, val)
+}
+
+type UintList []uint
+
+func (l *UintList) Add(val uint) {
+	*l = append(*l, val)
+}
+
+type Uint8List []uint8
+
+func (l *Uint8List) Add(val uint8) {
+	*l = append(*l, val)
+}
+
+type Uint16List []uint16
+
+func (l *Uint16List) Add(val uint16) {
+	*l = append(*l, val)
+}
+
+type Uint32List []uint32
+
+func (l *Uint32List) Add(val uint32) {
+	*l = append(*l, val)
+}
+
+type Uint64List []uint64
+
+func (l *Uint64List) Add(val uint64) {
+	*l = append(*l, val)
+}
+
+type UintptrList []uintptr
+
+func (l *UintptrList) Add(val uintptr) {
+	*l = append(*l, val)
+}
+
+type Float32List []float32
+
+func (l *Float32List) Add(val float32) {
+	*l = append(*l, val)
+}
+
+type Float64List []float64
+
+func (l *Float64List) Add(val float64) {
+	*l = append(*l, val)
+}
+
+type Complex64List []complex64
+
+func (l *Complex64List) Add(val complex64) {
+	*l = append(*l, val)
+}
+
+type Complex128List []complex128
+
+func (l *Complex128List) Add(val complex128) {
+	*l = append(*l, val)
+}
+
+type StringList []string
+
+func (l *StringList) Add(val string) {
+	*l = append(*l, val)
+}
+
+type BoolList []bool
+
+func (l *BoolList) Add(val bool) {
+	*l = append(*l, val)
+}
+
+type Array1Int8List [][1]int8
+
+func (l *Array1Int8List) Add(val [1]int8) {
+	*l = append(*l, val)
+}
+
+type Array2Int8List [][2]int8
+
+func (l *Array2Int8List) Add(val [2]int8) {
+	*l = append(*l, val)
+}
+
+type Array3Int8List [][3]int8
+
+func (l *Array3Int8List) Add(val [3]int8) {
+	*l = append(*l, val)
+}
+
+type Array4Int8List [][4]int8
+
+func (l *Array4Int8List) Add(val [4]int8) {
+	*l = append(*l, val)
+}
+
+type Array5Int8List [][5]int8
+
+func (l *Array5Int8List) Add(val [5]int8) {
+	*l = append(*l, val)
+}
+
+type Array6Int8List [][6]int8
+
+func (l *Array6Int8List) Add(val [6]int8) {
+	*l = append(*l, val)
+}
+
+type Array7Int8List [][7]int8
+
+func (l *Array7Int8List) Add(val [7]int8) {
+	*l = append(*l, val)
+}
+
+type Array8Int8List [][8]int8
+
+func (l *Array8Int8List) Add(val [8]int8) {
+	*l = append(*l, val)
+}
+
+type Array9Int8List [][9]int8
+
+func (l *Array9Int8List) Add(val [9]int8) {
+	*l = append(*l, val)
+}
+
+type Array10Int8List [][10]int8
+
+func (l *Array10Int8List) Add(val [10]int8) {
+	*l = append(*l, val)
+}
+
+type Array11Int8List [][11]int8
+
+func (l *Array11Int8List) Add(val [11]int8) {
+	*l = append(*l, val)
+}
+
+type Array12Int8List [][12]int8
+
+func (l *Array12Int8List) Add(val [12]int8) {
+	*l = append(*l, val)
+}
+
+type Array13Int8List [][13]int8
+
+func (l *Array13Int8List) Add(val [13]int8) {
+	*l = append(*l, val)
+}
+
+type Array14Int8List [][14]int8
+
+func (l *Array14Int8List) Add(val [14]int8) {
+	*l = append(*l, val)
+}
+
+type Array15Int8List [][15]int8
+
+func (l *Array15Int8List) Add(val [15]int8) {
+	*l = append(*l, val)
+}
+
+type Array16Int8List [][16]int8
+
+func (l *Array16Int8List) Add(val [16]int8) {
+	*l = append(*l, val)
+}
+
+type Array17Int8List [][17]int8
+
+func (l *Array17Int8List) Add(val [17]int8) {
+	*l = append(*l, val)
+}
+
+type Array18Int8List [][18]int8
+
+func (l *Array18Int8List) Add(val [18]int8) {
+	*l = append(*l, val)
+}
+
+type Array19Int8List [][19]int8
+
+func (l *Array19Int8List) Add(val [19]int8) {
+	*l = append(*l, val)
+}
+
+type Array20Int8List [][20]int8
+
+func (l *Array20Int8List) Add(val [20]int8) {
+	*l = append(*l, val)
+}
+
+type Array21Int8List [][21]int8
+
+func (l *Array21Int8List) Add(val [21]int8) {
+	*l = append(*l, val)
+}
+
+type Array22Int8List [][22]int8
+
+func (l *Array22Int8List) Add(val [22]int8) {
+	*l = append(*l, val)
+}
+
+type Array23Int8List [][23]int8
+
+func (l *Array23Int8List) Add(val [23]int8) {
+	*l = append(*l, val)
+}
+
+type Array24Int8List [][24]int8
+
+func (l *Array24Int8List) Add(val [24]int8) {
+	*l = append(*l, val)
+}
+
+type Array25Int8List [][25]int8
+
+func (l *Array25Int8List) Add(val [25]int8) {
+	*l = append(*l, val)
+}
+
+type Array26Int8List [][26]int8
+
+func (l *Array26Int8List) Add(val [26]int8) {
+	*l = append(*l, val)
+}
+
+type Array27Int8List [][27]int8
+
+func (l *Array27Int8List) Add(val [27]int8) {
+	*l = append(*l, val)
+}
+
+type Array28Int8List [][28]int8
+
+func (l *Array28Int8List) Add(val [28]int8) {
+	*l = append(*l, val)
+}
+
+type Array29Int8List [][29]int8
+
+func (l *Array29Int8List) Add(val [29]int8) {
+	*l = append(*l, val)
+}
+
+type Array30Int8List [][30]int8
+
+func (l *Array30Int8List) Add(val [30]int8) {
+	*l = append(*l, val)
+}
+
+type Array31Int8List [][31]int8
+
+func (l *Array31Int8List) Add(val [31]int8) {
+	*l = append(*l, val)
+}
+
+type Array32Int8List [][32]int8
+
+func (l *Array32Int8List) Add(val [32]int8) {
+	*l = append(*l, val)
+}
+
+type Array33Int8List [][33]int8
+
+func (l *Array33Int8List) Add(val [33]int8) {
+	*l = append(*l, val)
+}
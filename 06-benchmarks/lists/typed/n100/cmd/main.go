@@ -0,0 +1,325 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lists/gen. DO NOT EDIT.
+
+package main
+
+import (
+	list "go-generics-the-hard-way/06-benchmarks/lists/typed/n100"
+)
+
+func main() {
+	var intList list.IntList
+	intList.Add(1)
+
+	var int8List list.Int8List
+	int8List.Add(1)
+
+	var int16List list.Int16List
+	int16List.Add(1)
+
+	var int32List list.Int32List
+	int32List.Add(1)
+
+	var int64List list.Int64List
+	int64List.Add(1)
+
+	var uintList list.UintList
+	uintList.Add(1)
+
+	var uint8List list.Uint8List
+	uint8List.Add(1)
+
+	var uint16List list.Uint16List
+	uint16List.Add(1)
+
+	var uint32List list.Uint32List
+	uint32List.Add(1)
+
+	var uint64List list.Uint64List
+	uint64List.Add(1)
+
+	var uintptrList list.UintptrList
+	uintptrList.Add(1)
+
+	var float32List list.Float32List
+	float32List.Add(1)
+
+	var float64List list.Float64List
+	float64List.Add(1)
+
+	var complex64List list.Complex64List
+	complex64List.Add(1)
+
+	var complex128List list.Complex128List
+	complex128List.Add(1)
+
+	var stringList list.StringList
+	stringList.Add("1")
+
+	var boolList list.BoolList
+	boolList.Add(true)
+
+	var array1Int8List list.Array1Int8List
+	array1Int8List.Add([1]int8{})
+
+	var array2Int8List list.Array2Int8List
+	array2Int8List.Add([2]int8{})
+
+	var array3Int8List list.Array3Int8List
+	array3Int8List.Add([3]int8{})
+
+	var array4Int8List list.Array4Int8List
+	array4Int8List.Add([4]int8{})
+
+	var array5Int8List list.Array5Int8List
+	array5Int8List.Add([5]int8{})
+
+	var array6Int8List list.Array6Int8List
+	array6Int8List.Add([6]int8{})
+
+	var array7Int8List list.Array7Int8List
+	array7Int8List.Add([7]int8{})
+
+	var array8Int8List list.Array8Int8List
+	array8Int8List.Add([8]int8{})
+
+	var array9Int8List list.Array9Int8List
+	array9Int8List.Add([9]int8{})
+
+	var array10Int8List list.Array10Int8List
+	array10Int8List.Add([10]int8{})
+
+	var array11Int8List list.Array11Int8List
+	array11Int8List.Add([11]int8{})
+
+	var array12Int8List list.Array12Int8List
+	array12Int8List.Add([12]int8{})
+
+	var array13Int8List list.Array13Int8List
+	array13Int8List.Add([13]int8{})
+
+	var array14Int8List list.Array14Int8List
+	array14Int8List.Add([14]int8{})
+
+	var array15Int8List list.Array15Int8List
+	array15Int8List.Add([15]int8{})
+
+	var array16Int8List list.Array16Int8List
+	array16Int8List.Add([16]int8{})
+
+	var array17Int8List list.Array17Int8List
+	array17Int8List.Add([17]int8{})
+
+	var array18Int8List list.Array18Int8List
+	array18Int8List.Add([18]int8{})
+
+	var array19Int8List list.Array19Int8List
+	array19Int8List.Add([19]int8{})
+
+	var array20Int8List list.Array20Int8List
+	array20Int8List.Add([20]int8{})
+
+	var array21Int8List list.Array21Int8List
+	array21Int8List.Add([21]int8{})
+
+	var array22Int8List list.Array22Int8List
+	array22Int8List.Add([22]int8{})
+
+	var array23Int8List list.Array23Int8List
+	array23Int8List.Add([23]int8{})
+
+	var array24Int8List list.Array24Int8List
+	array24Int8List.Add([24]int8{})
+
+	var array25Int8List list.Array25Int8List
+	array25Int8List.Add([25]int8{})
+
+	var array26Int8List list.Array26Int8List
+	array26Int8List.Add([26]int8{})
+
+	var array27Int8List list.Array27Int8List
+	array27Int8List.Add([27]int8{})
+
+	var array28Int8List list.Array28Int8List
+	array28Int8List.Add([28]int8{})
+
+	var array29Int8List list.Array29Int8List
+	array29Int8List.Add([29]int8{})
+
+	var array30Int8List list.Array30Int8List
+	array30Int8List.Add([30]int8{})
+
+	var array31Int8List list.Array31Int8List
+	array31Int8List.Add([31]int8{})
+
+	var array32Int8List list.Array32Int8List
+	array32Int8List.Add([32]int8{})
+
+	var array33Int8List list.Array33Int8List
+	array33Int8List.Add([33]int8{})
+
+	var array34Int8List list.Array34Int8List
+	array34Int8List.Add([34]int8{})
+
+	var array35Int8List list.Array35Int8List
+	array35Int8List.Add([35]int8{})
+
+	var array36Int8List list.Array36Int8List
+	array36Int8List.Add([36]int8{})
+
+	var array37Int8List list.Array37Int8List
+	array37Int8List.Add([37]int8{})
+
+	var array38Int8List list.Array38Int8List
+	array38Int8List.Add([38]int8{})
+
+	var array39Int8List list.Array39Int8List
+	array39Int8List.Add([39]int8{})
+
+	var array40Int8List list.Array40Int8List
+	array40Int8List.Add([40]int8{})
+
+	var array41Int8List list.Array41Int8List
+	array41Int8List.Add([41]int8{})
+
+	var array42Int8List list.Array42Int8List
+	array42Int8List.Add([42]int8{})
+
+	var array43Int8List list.Array43Int8List
+	array43Int8List.Add([43]int8{})
+
+	var array44Int8List list.Array44Int8List
+	array44Int8List.Add([44]int8{})
+
+	var array45Int8List list.Array45Int8List
+	array45Int8List.Add([45]int8{})
+
+	var array46Int8List list.Array46Int8List
+	array46Int8List.Add([46]int8{})
+
+	var array47Int8List list.Array47Int8List
+	array47Int8List.Add([47]int8{})
+
+	var array48Int8List list.Array48Int8List
+	array48Int8List.Add([48]int8{})
+
+	var array49Int8List list.Array49Int8List
+	array49Int8List.Add([49]int8{})
+
+	var array50Int8List list.Array50Int8List
+	array50Int8List.Add([50]int8{})
+
+	var array51Int8List list.Array51Int8List
+	array51Int8List.Add([51]int8{})
+
+	var array52Int8List list.Array52Int8List
+	array52Int8List.Add([52]int8{})
+
+	var array53Int8List list.Array53Int8List
+	array53Int8List.Add([53]int8{})
+
+	var array54Int8List list.Array54Int8List
+	array54Int8List.Add([54]int8{})
+
+	var array55Int8List list.Array55Int8List
+	array55Int8List.Add([55]int8{})
+
+	var array56Int8List list.Array56Int8List
+	array56Int8List.Add([56]int8{})
+
+	var array57Int8List list.Array57Int8List
+	array57Int8List.Add([57]int8{})
+
+	var array58Int8List list.Array58Int8List
+	array58Int8List.Add([58]int8{})
+
+	var array59Int8List list.Array59Int8List
+	array59Int8List.Add([59]int8{})
+
+	var array60Int8List list.Array60Int8List
+	array60Int8List.Add([60]int8{})
+
+	var array61Int8List list.Array61Int8List
+	array61Int8List.Add([61]int8{})
+
+	var array62Int8List list.Array62Int8List
+	array62Int8List.Add([62]int8{})
+
+	var array63Int8List list.Array63Int8List
+	array63Int8List.Add([63]int8{})
+
+	var array64Int8List list.Array64Int8List
+	array64Int8List.Add([64]int8{})
+
+	var array65Int8List list.Array65Int8List
+	array65Int8List.Add([65]int8{})
+
+	var array66Int8List list.Array66Int8List
+	array66Int8List.Add([66]int8{})
+
+	var array67Int8List list.Array67Int8List
+	array67Int8List.Add([67]int8{})
+
+	var array68Int8List list.Array68Int8List
+	array68Int8List.Add([68]int8{})
+
+	var array69Int8List list.Array69Int8List
+	array69Int8List.Add([69]int8{})
+
+	var array70Int8List list.Array70Int8List
+	array70Int8List.Add([70]int8{})
+
+	var array71Int8List list.Array71Int8List
+	array71Int8List.Add([71]int8{})
+
+	var array72Int8List list.Array72Int8List
+	array72Int8List.Add([72]int8{})
+
+	var array73Int8List list.Array73Int8List
+	array73Int8List.Add([73]int8{})
+
+	var array74Int8List list.Array74Int8List
+	array74Int8List.Add([74]int8{})
+
+	var array75Int8List list.Array75Int8List
+	array75Int8List.Add([75]int8{})
+
+	var array76Int8List list.Array76Int8List
+	array76Int8List.Add([76]int8{})
+
+	var array77Int8List list.Array77Int8List
+	array77Int8List.Add([77]int8{})
+
+	var array78Int8List list.Array78Int8List
+	array78Int8List.Add([78]int8{})
+
+	var array79Int8List list.Array79Int8List
+	array79Int8List.Add([79]int8{})
+
+	var array80Int8List list.Array80Int8List
+	array80Int8List.Add([80]int8{})
+
+	var array81Int8List list.Array81Int8List
+	array81Int8List.Add([81]int8{})
+
+	var array82Int8List list.Array82Int8List
+	array82Int8List.Add([82]int8{})
+
+	var array83Int8List list.Array83Int8List
+	array83Int8List.Add([83]int8{})
+}
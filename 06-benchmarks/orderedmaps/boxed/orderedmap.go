@@ -0,0 +1,131 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orderedmap
+
+const maxLevel = 32
+
+// LessFn reports whether a sorts before b. Both values are boxed, so an
+// implementation must use type assertions to compare them.
+type LessFn func(a, b interface{}) bool
+
+type node struct {
+	key  interface{}
+	val  interface{}
+	next []*node
+}
+
+// OrderedMap is a skip list with keys and values boxed in the empty
+// interface. It is the pre-generics equivalent of
+// orderedmap.OrderedMap[K, V].
+type OrderedMap struct {
+	less  LessFn
+	head  *node
+	level int
+	rnd   uint64
+}
+
+func New(less LessFn) *OrderedMap {
+	return &OrderedMap{
+		less:  less,
+		head:  &node{next: make([]*node, maxLevel)},
+		level: 1,
+		rnd:   0x9E3779B97F4A7C15,
+	}
+}
+
+func (m *OrderedMap) Get(key interface{}) (interface{}, bool) {
+	if n := m.lowerBound(key, nil); n != nil && !m.less(key, n.key) {
+		return n.val, true
+	}
+	return nil, false
+}
+
+// Put sets the value for key.
+//
+// Please note that key and val will be boxed in order to pass them into the
+// method using the empty interface.
+func (m *OrderedMap) Put(key, val interface{}) {
+	var update [maxLevel]*node
+	if n := m.lowerBound(key, &update); n != nil && !m.less(key, n.key) {
+		n.val = val
+		return
+	}
+
+	level := m.randomLevel()
+	if level > m.level {
+		for i := m.level; i < level; i++ {
+			update[i] = m.head
+		}
+		m.level = level
+	}
+
+	n := &node{key: key, val: val, next: make([]*node, level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+}
+
+func (m *OrderedMap) Delete(key interface{}) bool {
+	var update [maxLevel]*node
+	n := m.lowerBound(key, &update)
+	if n == nil || m.less(key, n.key) {
+		return false
+	}
+	for i := 0; i < len(n.next); i++ {
+		update[i].next[i] = n.next[i]
+	}
+	for m.level > 1 && m.head.next[m.level-1] == nil {
+		m.level--
+	}
+	return true
+}
+
+func (m *OrderedMap) Range(lo, hi interface{}, fn func(k, v interface{}) bool) {
+	for n := m.lowerBound(lo, nil); n != nil && m.less(n.key, hi); n = n.next[0] {
+		if !fn(n.key, n.val) {
+			return
+		}
+	}
+}
+
+func (m *OrderedMap) lowerBound(key interface{}, update *[maxLevel]*node) *node {
+	x := m.head
+	for i := m.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && m.less(x.next[i].key, key) {
+			x = x.next[i]
+		}
+		if update != nil {
+			update[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+func (m *OrderedMap) randomLevel() int {
+	m.rnd ^= m.rnd << 13
+	m.rnd ^= m.rnd >> 7
+	m.rnd ^= m.rnd << 17
+	r := m.rnd
+
+	level := 1
+	for level < maxLevel && r&3 == 0 {
+		level++
+		r >>= 2
+	}
+	return level
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"testing"
+
+	bomap "go-generics-the-hard-way/06-benchmarks/orderedmaps/boxed"
+	gomap "go-generics-the-hard-way/pkg/orderedmap"
+)
+
+// omapSize is the number of keys in the ordered maps used by the Get and
+// Range benchmarks.
+const omapSize = 1 << 14
+
+func lessInt(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+// omapKey spreads the keys out so consecutive iterations do not walk the
+// same part of the skip list.
+func omapKey(i int) int {
+	return (i * 7919) % (4 * omapSize)
+}
+
+func BenchmarkOrderedMapPut(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		m := bomap.New(lessInt)
+		for i := 0; i < b.N; i++ {
+			m.Put(omapKey(i), i)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		m := gomap.New[int, int]()
+		for i := 0; i < b.N; i++ {
+			m.Put(omapKey(i), i)
+		}
+	})
+}
+
+func BenchmarkOrderedMapGet(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		m := bomap.New(lessInt)
+		for i := 0; i < omapSize; i++ {
+			m.Put(omapKey(i), i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = m.Get(omapKey(i))
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		m := gomap.New[int, int]()
+		for i := 0; i < omapSize; i++ {
+			m.Put(omapKey(i), i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = m.Get(omapKey(i))
+		}
+	})
+}
+
+func BenchmarkOrderedMapRange(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		m := bomap.New(lessInt)
+		for i := 0; i < omapSize; i++ {
+			m.Put(i, i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var sum int
+			lo := i % (omapSize - 64)
+			m.Range(lo, lo+64, func(_, v interface{}) bool {
+				sum += v.(int)
+				return true
+			})
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		m := gomap.New[int, int]()
+		for i := 0; i < omapSize; i++ {
+			m.Put(i, i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var sum int
+			lo := i % (omapSize - 64)
+			m.Range(lo, lo+64, func(_, v int) bool {
+				sum += v
+				return true
+			})
+		}
+	})
+}
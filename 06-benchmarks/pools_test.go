@@ -0,0 +1,74 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/pool"
+)
+
+func BenchmarkObjectPool(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		p := sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get().(*bytes.Buffer)
+				buf.WriteByte('x')
+				buf.Reset()
+				p.Put(buf)
+			}
+		})
+	})
+	b.Run("generic", func(b *testing.B) {
+		p := pool.Pool[bytes.Buffer]{Reset: func(b *bytes.Buffer) { b.Reset() }}
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get()
+				buf.WriteByte('x')
+				p.Put(buf)
+			}
+		})
+	})
+}
+
+// BenchmarkSlicePool compares pooling []byte buffers directly in a sync.Pool,
+// which boxes the slice header on every Put, with pool.SlicePool.
+func BenchmarkSlicePool(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		p := sync.Pool{New: func() interface{} { return make([]byte, 0, 4096) }}
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get().([]byte)[:1024]
+				buf[0] = 'x'
+				p.Put(buf[:0])
+			}
+		})
+	})
+	b.Run("generic", func(b *testing.B) {
+		p := pool.NewSlicePool[byte](1024, 4096)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get(1024)
+				buf[0] = 'x'
+				p.Put(buf)
+			}
+		})
+	})
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+// Set is a new type definition for map[interface{}]struct{}.
+type Set map[interface{}]struct{}
+
+// Add a new element to the set.
+//
+// Please note that val will be boxed in order to pass it into the method using
+// the empty interface, and the boxed value must also be hashed as an interface,
+// which requires inspecting its dynamic type.
+func (s Set) Add(val interface{}) {
+	s[val] = struct{}{}
+}
+
+// Has returns true if the set contains val.
+func (s Set) Has(val interface{}) bool {
+	_, ok := s[val]
+	return ok
+}
+
+// Union returns a new set with the elements from both s and other.
+func (s Set) Union(other Set) Set {
+	u := make(Set, len(s)+len(other))
+	for k := range s {
+		u[k] = struct{}{}
+	}
+	for k := range other {
+		u[k] = struct{}{}
+	}
+	return u
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+type IntSet map[int]struct{}
+
+func (s IntSet) Add(val int) {
+	s[val] = struct{}{}
+}
+
+func (s IntSet) Has(val int) bool {
+	_, ok := s[val]
+	return ok
+}
+
+func (s IntSet) Union(other IntSet) IntSet {
+	u := make(IntSet, len(s)+len(other))
+	for k := range s {
+		u[k] = struct{}{}
+	}
+	for k := range other {
+		u[k] = struct{}{}
+	}
+	return u
+}
+
+type StringSet map[string]struct{}
+
+func (s StringSet) Add(val string) {
+	s[val] = struct{}{}
+}
+
+func (s StringSet) Has(val string) bool {
+	_, ok := s[val]
+	return ok
+}
+
+func (s StringSet) Union(other StringSet) StringSet {
+	u := make(StringSet, len(s)+len(other))
+	for k := range s {
+		u[k] = struct{}{}
+	}
+	for k := range other {
+		u[k] = struct{}{}
+	}
+	return u
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"strconv"
+	"testing"
+
+	bset "go-generics-the-hard-way/06-benchmarks/sets/boxed"
+	tset "go-generics-the-hard-way/06-benchmarks/sets/typed"
+	gset "go-generics-the-hard-way/pkg/set"
+)
+
+// setSize is the number of distinct keys used by the set benchmarks. Keys
+// wrap around once this many have been added so the maps stop growing and
+// the benchmarks measure hashing instead of map resizing.
+const setSize = 1 << 16
+
+// setStrKeys are precomputed so the string benchmarks do not measure
+// strconv.Itoa.
+var setStrKeys = func() []string {
+	keys := make([]string, setSize)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+	return keys
+}()
+
+func BenchmarkSetAdd(b *testing.B) {
+	b.Run("int", func(b *testing.B) {
+		b.Run("boxed", func(b *testing.B) {
+			set := bset.Set{}
+			for i := 0; i < b.N; i++ {
+				set.Add(i % setSize)
+			}
+		})
+		b.Run("generic", func(b *testing.B) {
+			set := gset.Set[int]{}
+			for i := 0; i < b.N; i++ {
+				set.Add(i % setSize)
+			}
+		})
+		b.Run("typed", func(b *testing.B) {
+			set := tset.IntSet{}
+			for i := 0; i < b.N; i++ {
+				set.Add(i % setSize)
+			}
+		})
+	})
+	b.Run("string", func(b *testing.B) {
+		b.Run("boxed", func(b *testing.B) {
+			set := bset.Set{}
+			for i := 0; i < b.N; i++ {
+				set.Add(setStrKeys[i%setSize])
+			}
+		})
+		b.Run("generic", func(b *testing.B) {
+			set := gset.Set[string]{}
+			for i := 0; i < b.N; i++ {
+				set.Add(setStrKeys[i%setSize])
+			}
+		})
+		b.Run("typed", func(b *testing.B) {
+			set := tset.StringSet{}
+			for i := 0; i < b.N; i++ {
+				set.Add(setStrKeys[i%setSize])
+			}
+		})
+	})
+}
+
+func BenchmarkSetHas(b *testing.B) {
+	b.Run("int", func(b *testing.B) {
+		b.Run("boxed", func(b *testing.B) {
+			set := bset.Set{}
+			for i := 0; i < setSize; i++ {
+				set.Add(i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				set.Has(i % (2 * setSize))
+			}
+		})
+		b.Run("generic", func(b *testing.B) {
+			set := gset.Set[int]{}
+			for i := 0; i < setSize; i++ {
+				set.Add(i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				set.Has(i % (2 * setSize))
+			}
+		})
+		b.Run("typed", func(b *testing.B) {
+			set := tset.IntSet{}
+			for i := 0; i < setSize; i++ {
+				set.Add(i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				set.Has(i % (2 * setSize))
+			}
+		})
+	})
+	b.Run("string", func(b *testing.B) {
+		b.Run("boxed", func(b *testing.B) {
+			set := bset.Set{}
+			for i := 0; i < setSize; i += 2 {
+				set.Add(setStrKeys[i])
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				set.Has(setStrKeys[i%setSize])
+			}
+		})
+		b.Run("generic", func(b *testing.B) {
+			set := gset.Set[string]{}
+			for i := 0; i < setSize; i += 2 {
+				set.Add(setStrKeys[i])
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				set.Has(setStrKeys[i%setSize])
+			}
+		})
+		b.Run("typed", func(b *testing.B) {
+			set := tset.StringSet{}
+			for i := 0; i < setSize; i += 2 {
+				set.Add(setStrKeys[i])
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				set.Has(setStrKeys[i%setSize])
+			}
+		})
+	})
+}
+
+func BenchmarkSetUnion(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		x, y := bset.Set{}, bset.Set{}
+		for i := 0; i < 1024; i++ {
+			x.Add(i)
+			y.Add(i + 512)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = x.Union(y)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		x, y := gset.Set[int]{}, gset.Set[int]{}
+		for i := 0; i < 1024; i++ {
+			x.Add(i)
+			y.Add(i + 512)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = x.Union(y)
+		}
+	})
+	b.Run("typed", func(b *testing.B) {
+		x, y := tset.IntSet{}, tset.IntSet{}
+		for i := 0; i < 1024; i++ {
+			x.Add(i)
+			y.Add(i + 512)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = x.Union(y)
+		}
+	})
+}
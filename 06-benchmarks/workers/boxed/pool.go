@@ -0,0 +1,81 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workers
+
+import (
+	"sync"
+)
+
+// Func is the function a pool runs for each job. Both the input and the
+// result are boxed in the empty interface.
+type Func func(in interface{}) (interface{}, error)
+
+// Future is the eventual result of a submitted job.
+type Future struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+// Wait blocks until the job completes. The result must be unboxed with a
+// type assertion.
+func (f *Future) Wait() (interface{}, error) {
+	<-f.done
+	return f.val, f.err
+}
+
+type job struct {
+	in     interface{}
+	future *Future
+}
+
+// Pool is a worker pool built the way it had to be before generics.
+type Pool struct {
+	fn   Func
+	jobs chan job
+	wg   sync.WaitGroup
+}
+
+func New(workers, queue int, fn Func) *Pool {
+	p := &Pool{fn: fn, jobs: make(chan job, queue)}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			for j := range p.jobs {
+				j.future.val, j.future.err = p.fn(j.in)
+				close(j.future.done)
+			}
+		}()
+	}
+	return p
+}
+
+// Submit queues a job.
+//
+// Please note that in will be boxed in order to pass it into the method using
+// the empty interface.
+func (p *Pool) Submit(in interface{}) *Future {
+	f := &Future{done: make(chan struct{})}
+	p.jobs <- job{in: in, future: f}
+	return f
+}
+
+func (p *Pool) Shutdown() {
+	close(p.jobs)
+	p.wg.Wait()
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks_test
+
+import (
+	"context"
+	"testing"
+
+	bworkers "go-generics-the-hard-way/06-benchmarks/workers/boxed"
+	gworkers "go-generics-the-hard-way/pkg/workers"
+)
+
+// workersBatch is the number of jobs submitted before waiting for their
+// results, so the workers are kept busy.
+const workersBatch = 256
+
+type point struct {
+	X, Y int64
+}
+
+func BenchmarkWorkerPool(b *testing.B) {
+	b.Run("boxed", func(b *testing.B) {
+		p := bworkers.New(4, workersBatch, func(in interface{}) (interface{}, error) {
+			pt := in.(point)
+			return pt.X + pt.Y, nil
+		})
+		defer p.Shutdown()
+		futures := make([]*bworkers.Future, 0, workersBatch)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			futures = append(futures, p.Submit(point{int64(i), int64(i)}))
+			if len(futures) == workersBatch || i == b.N-1 {
+				for _, f := range futures {
+					v, _ := f.Wait()
+					_ = v.(int64)
+				}
+				futures = futures[:0]
+			}
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		ctx := context.Background()
+		p := gworkers.New(4, workersBatch, func(_ context.Context, pt point) (int64, error) {
+			return pt.X + pt.Y, nil
+		})
+		defer p.Shutdown(ctx)
+		futures := make([]*gworkers.Future[int64], 0, workersBatch)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			f, _ := p.Submit(ctx, point{int64(i), int64(i)})
+			futures = append(futures, f)
+			if len(futures) == workersBatch || i == b.N-1 {
+				for _, f := range futures {
+					_, _ = f.Wait(ctx)
+				}
+				futures = futures[:0]
+			}
+		}
+	})
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command archsize attributes the size of Go package archives, the .a files
+// go build writes for packages that are not main packages, to the parts of
+// the archives.
+//
+//	archsize [-json] [-symbols N] [-bodies] ARCHIVE...
+//
+// archsize prints a table with a column per archive and a row per part: the
+// ar members, the sections of the export data in __.PKGDEF, the blocks of the
+// object file in _go_.o, and the object file's data by kind. The rows
+// "export generic bodies" and "object generic" show how much of an archive
+// is the export data for the bodies of generic functions and methods, and
+// how much is the compiled code and data of the instantiations, ex. for the
+// BenchmarkGoBuild artifacts:
+//
+//	archsize generic-5-types.a typed-5-types.a
+//
+// With -symbols, archsize also prints the N largest symbols of each object
+// file, and with -bodies, the function bodies in each archive's export data.
+//
+// The archives must be built with Go 1.24 or later, as the formats of their
+// members are internal to the toolchain and archsize only reads the recent
+// ones.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"go-generics-the-hard-way/internal/archsize"
+)
+
+var (
+	asJSON  = flag.Bool("json", false, "print the reports as JSON")
+	symbols = flag.Int("symbols", 0, "print the `N` largest symbols of each object file")
+	bodies  = flag.Bool("bodies", false, "print the function bodies in each archive's export data")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: archsize [flags] ARCHIVE...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "archsize:", err)
+		os.Exit(1)
+	}
+}
+
+func run(paths []string) error {
+	reports := make([]*archsize.Report, len(paths))
+	for i, p := range paths {
+		r, err := archsize.Open(p)
+		if err != nil {
+			return err
+		}
+		reports[i] = r
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		out := map[string]*archsize.Report{}
+		for i, r := range reports {
+			out[paths[i]] = r
+		}
+		return enc.Encode(out)
+	}
+
+	printTable(paths, reports)
+	for i, r := range reports {
+		if *bodies {
+			fmt.Printf("\n%s: export data bodies\n\n", paths[i])
+			printBodies(r.Export)
+		}
+		if *symbols > 0 {
+			fmt.Printf("\n%s: object file symbols\n\n", paths[i])
+			printSymbols(r.Object, *symbols)
+		}
+	}
+	return nil
+}
+
+// row is a row of the table, with a value per archive.
+type row struct {
+	name   string
+	values []*int64
+}
+
+// table is the rows of the table in the order they are first added.
+type table struct {
+	n     int
+	rows  []*row
+	index map[string]*row
+}
+
+func (t *table) add(col int, name string, v int64) {
+	r, ok := t.index[name]
+	if !ok {
+		r = &row{name: name, values: make([]*int64, t.n)}
+		t.index[name] = r
+		t.rows = append(t.rows, r)
+	}
+	r.values[col] = &v
+}
+
+func printTable(paths []string, reports []*archsize.Report) {
+	t := table{n: len(reports), index: map[string]*row{}}
+	for i, r := range reports {
+		t.add(i, "size", r.Size)
+		for _, m := range r.Members {
+			t.add(i, "member "+m.Name, m.Size)
+		}
+		for _, s := range r.Export.Sections {
+			t.add(i, "export "+s.Name, s.Size)
+		}
+		t.add(i, "export generic bodies", r.Export.GenericBodies)
+		t.add(i, "export inline bodies", r.Export.InlineBodies)
+		for _, s := range r.Object.Blocks {
+			t.add(i, "object "+s.Name, s.Size)
+		}
+		for _, s := range r.Object.Kinds {
+			t.add(i, "object data "+s.Name, s.Size)
+		}
+		t.add(i, "object generic", r.Object.Generic)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "\t")
+	for _, p := range paths {
+		fmt.Fprintf(w, "%s\t", filepath.Base(p))
+	}
+	fmt.Fprintln(w)
+	for _, r := range t.rows {
+		fmt.Fprintf(w, "%s\t", r.name)
+		for _, v := range r.values {
+			if v == nil {
+				fmt.Fprint(w, "-\t")
+			} else {
+				fmt.Fprintf(w, "%d\t", *v)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+}
+
+func printBodies(e *archsize.Export) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "SIZE\tCOUNT\t  KIND     NAME")
+	for _, b := range e.Bodies {
+		kind := "inline"
+		if b.Generic {
+			kind = "generic"
+		}
+		fmt.Fprintf(w, "%d\t%d\t  %-8s %s\n", b.Size, b.Count, kind, b.Name)
+	}
+	w.Flush()
+}
+
+func printSymbols(o *archsize.Object, n int) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "SIZE\tAUX\tRELOCS\t  KIND     NAME")
+	for i, s := range o.Symbols {
+		if i == n {
+			break
+		}
+		name := s.Name
+		if name == "" {
+			name = "(unnamed)"
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t  %-8s %s\n", s.Size, s.Aux, s.Relocs, s.Kind, strings.TrimSpace(name))
+	}
+	w.Flush()
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command benchhist records benchmark results and detects regressions
+// between them.
+//
+// Results are stored in flat files keyed by the Go version, GOARCH, and git
+// commit, so the benchmarks can be rerun after a toolchain upgrade and
+// compared with the results from before it:
+//
+//	go test -bench 'Boxing|GoBuild' -run XXX -benchmem -count 10 ./06-benchmarks | \
+//	  go run ./hack/benchhist save
+//	go run ./hack/benchhist list
+//	go run ./hack/benchhist compare go1.18 go1.19
+//
+// The arguments to compare are either files of go test -bench output or
+// references to saved results: one or more of a Go version, a GOARCH, and a
+// commit prefix separated by slashes, ex. go1.19/arm64. The newest results
+// that match a reference are used, and if the second argument is omitted the
+// newest results are compared with the first.
+//
+// compare uses a Mann-Whitney U test, as benchstat does, to decide whether a
+// change in ns/op, B/op, or filesize/op is significant, and exits with
+// status 1 if any significant increase is past the threshold. Other errors
+// exit with status 2.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-generics-the-hard-way/hack/internal/bench"
+)
+
+const usage = `usage:
+  benchhist save [flags] [FILE...]
+  benchhist list [flags]
+  benchhist compare [flags] OLD [NEW]
+
+Run benchhist COMMAND -h for the command's flags.
+`
+
+// errRegression is returned by compare when there is a regression.
+var errRegression = errors.New("regression detected")
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "save":
+		err = save(args)
+	case "list":
+		err = list(args)
+	case "compare":
+		err = compare(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch {
+	case err == errRegression:
+		fmt.Fprintln(os.Stderr, "benchhist:", err)
+		os.Exit(1)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "benchhist:", err)
+		os.Exit(2)
+	}
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	dir := fs.String("dir", ".benchhist", "the directory the results are stored in")
+	return fs, dir
+}
+
+func save(args []string) error {
+	fs, dir := newFlagSet("save")
+	var (
+		commit    = fs.String("commit", "", "the commit the results are for (default the current commit)")
+		goVersion = fs.String("go", "", "the Go version the results are for (default the output of go env GOVERSION)")
+		goarch    = fs.String("goarch", "", "the GOARCH the results are for (default the goarch in the results)")
+		echo      = fs.Bool("echo", false, "echo the input to stdout")
+	)
+	fs.Parse(args)
+
+	var output []byte
+	if fs.NArg() == 0 {
+		var r io.Reader = os.Stdin
+		if *echo {
+			r = io.TeeReader(r, os.Stdout)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		output = b
+	}
+	for _, name := range fs.Args() {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		output = append(output, b...)
+	}
+
+	results, err := bench.Parse(bytes.NewReader(output))
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no benchmark results")
+	}
+
+	k := bench.Key{GoVersion: *goVersion, GOARCH: *goarch, Commit: *commit}
+	if k.Commit == "" {
+		if k.Commit, err = currentCommit(); err != nil {
+			return err
+		}
+	}
+	if k.GoVersion == "" {
+		k.GoVersion = goEnv("GOVERSION", runtime.Version())
+	}
+	if k.GOARCH == "" {
+		k.GOARCH = results[0].Config["goarch"]
+	}
+	if k.GOARCH == "" {
+		k.GOARCH = runtime.GOARCH
+	}
+
+	e, err := bench.History{Dir: *dir}.Save(k, time.Now(), output)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "saved %d results to %s\n", len(results), e.Path)
+	return nil
+}
+
+// currentCommit returns the abbreviated hash of HEAD, with a -dirty suffix if
+// the working tree has changes.
+func currentCommit() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %w, please use -commit", err)
+	}
+	commit := strings.TrimSpace(string(out))
+	if out, err := exec.Command("git", "status", "--porcelain").Output(); err == nil && len(out) > 0 {
+		commit += "-dirty"
+	}
+	return commit, nil
+}
+
+func goEnv(key, fallback string) string {
+	out, err := exec.Command("go", "env", key).Output()
+	if v := strings.TrimSpace(string(out)); err == nil && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func list(args []string) error {
+	fs, dir := newFlagSet("list")
+	fs.Parse(args)
+
+	entries, err := bench.History{Dir: *dir}.Entries()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tGO\tGOARCH\tCOMMIT")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Date.Local().Format(time.RFC3339), e.GoVersion, e.GOARCH, e.Commit)
+	}
+	return w.Flush()
+}
+
+func compare(args []string) error {
+	fs, dir := newFlagSet("compare")
+	var (
+		threshold = fs.Float64("threshold", 5, "the increase, in percent, past which a significant change is a regression")
+		alpha     = fs.Float64("alpha", 0.05, "the significance level")
+		metrics   = fs.String("metrics", strings.Join(bench.DefaultCompareMetrics, ","), "comma-separated units of the metrics to compare")
+		filter    = fs.String("filter", "", "only compare the benchmarks whose names contain this string")
+	)
+	fs.Parse(args)
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return fmt.Errorf("compare requires one or two arguments")
+	}
+
+	h := bench.History{Dir: *dir}
+	oldName, old, err := load(h, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	newArg := fs.Arg(1)
+	if newArg == "" {
+		entries, err := h.Entries()
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no results in %s", *dir)
+		}
+		newArg = entries[len(entries)-1].Key.String()
+	}
+	newName, new, err := load(h, newArg)
+	if err != nil {
+		return err
+	}
+
+	cmps := bench.Compare(summarize(old, *filter), summarize(new, *filter), bench.CompareOptions{
+		Metrics:   strings.Split(*metrics, ","),
+		Threshold: *threshold,
+		Alpha:     *alpha,
+	})
+	if len(cmps) == 0 {
+		return fmt.Errorf("%s and %s have no benchmarks in common", oldName, newName)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "NAME\tUNIT\t%s\t%s\tDELTA\t\n", oldName, newName)
+	regressions := 0
+	for _, c := range cmps {
+		delta := "~"
+		if c.Significant {
+			delta = fmt.Sprintf("%+.2f%%", c.Delta)
+		}
+		note := fmt.Sprintf("(p=%.3f n=%d+%d)", c.P, len(c.Old), len(c.New))
+		if c.Regression {
+			note += " regression"
+			regressions++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", c.Name, c.Unit,
+			bench.FormatValue(&c.OldMean), bench.FormatValue(&c.NewMean), delta, note)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if regressions > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d metrics regressed by more than %g%%\n", regressions, len(cmps), *threshold)
+		return errRegression
+	}
+	return nil
+}
+
+// load reads the results from the named file, if it exists, or otherwise
+// from the newest entry in the history that matches it.
+func load(h bench.History, arg string) (string, []bench.Result, error) {
+	if f, err := os.Open(arg); err == nil {
+		defer f.Close()
+		results, err := bench.Parse(f)
+		return arg, results, err
+	}
+	e, err := h.Find(arg)
+	if err != nil {
+		return "", nil, err
+	}
+	results, err := e.Load()
+	return e.Key.String(), results, err
+}
+
+func summarize(results []bench.Result, filter string) []*bench.Summary {
+	var filtered []bench.Result
+	for _, r := range results {
+		if strings.Contains(r.Name, filter) {
+			filtered = append(filtered, r)
+		}
+	}
+	return bench.Summarize(filtered)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command benchreport turns the output of go test -bench into tables.
+//
+// The results of each benchmark are averaged across its runs, ex. from
+// -count 5, and laid out in a table per top-level benchmark with a row per
+// sub-benchmark and a column per metric, including custom metrics such as
+// filesize/op. The tables are written as Markdown, CSV, JSON, or HTML:
+//
+//	go test -bench Boxing -run Boxing -benchmem -count 5 ./06-benchmarks | \
+//	  go run ./hack/benchreport -keys "List type,Number of types"
+//
+// The -pivot flag turns the values of a path element into columns, and
+// -baseline adds columns for the increase over one of those values, ex. the
+// table in 06-benchmarks/02-build-times.md:
+//
+//	go test -bench GoBuild -run GoBuild -count 1 ./06-benchmarks | \
+//	  go run ./hack/benchreport \
+//	    -filter 'GoBuild/(generic|typed)/' -pivot 1 -baseline typed \
+//	    -keys "Artifact type,Number of types" -metrics iters,ns/op
+//
+// Results are read from the named files, or from stdin if there are none.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"go-generics-the-hard-way/hack/internal/bench"
+)
+
+var (
+	format   = flag.String("format", "md", "the output format: "+strings.Join(bench.Formats, ", "))
+	filter   = flag.String("filter", "", "only report the benchmarks whose names match this regular expression")
+	metrics  = flag.String("metrics", "", "comma-separated units of the metrics to report, ex. ns/op,B/op (default all)")
+	group    = flag.Int("group", 1, "the number of leading path elements that name a table")
+	pivot    = flag.Int("pivot", 0, "the index of the path element whose values become columns (default none)")
+	baseline = flag.String("baseline", "", "the pivot value to compare the other values with")
+	keys     = flag.String("keys", "", "comma-separated names of the columns the rest of a benchmark's path is split into")
+	echo     = flag.Bool("echo", false, "echo the input to stderr")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: benchreport [flags] [FILE...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "benchreport:", err)
+		os.Exit(1)
+	}
+}
+
+func run(files []string) error {
+	var re *regexp.Regexp
+	if *filter != "" {
+		var err error
+		if re, err = regexp.Compile(*filter); err != nil {
+			return err
+		}
+	}
+
+	var results []bench.Result
+	read := func(r io.Reader) error {
+		if *echo {
+			r = io.TeeReader(r, os.Stderr)
+		}
+		res, err := bench.Parse(r)
+		if err != nil {
+			return err
+		}
+		for _, r := range res {
+			if re == nil || re.MatchString(r.Name) {
+				results = append(results, r)
+			}
+		}
+		return nil
+	}
+	if len(files) == 0 {
+		if err := read(os.Stdin); err != nil {
+			return err
+		}
+	}
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = read(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no benchmark results")
+	}
+
+	tables, err := bench.Tables(bench.Summarize(results), bench.TableOptions{
+		Group:    *group,
+		Pivot:    *pivot,
+		Baseline: *baseline,
+		Metrics:  splitList(*metrics),
+		Keys:     splitList(*keys),
+	})
+	if err != nil {
+		return err
+	}
+	return bench.WriteTables(os.Stdout, *format, tables)
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	fields := strings.Split(s, ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
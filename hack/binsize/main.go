@@ -0,0 +1,198 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command binsize attributes the text and read-only data of Go binaries to
+// generic instantiations.
+//
+//	binsize [-json] [-symbols] [-nopkg] [-filter REGEXP] BINARY...
+//	binsize [-nopkg] -base OLD NEW
+//
+// For each binary, binsize prints the instantiations from the largest to the
+// smallest, ex. the stenciled code for pkg.List[go.shape.int] and the
+// dictionary for pkg.List[int]. With -base, it prints the instantiations
+// whose sizes differ between two binaries, which shows what an additional
+// type argument adds, ex. between the BenchmarkGoBuild artifacts
+// generic-4-types.bin and generic-5-types.bin. Since those lists are in
+// different packages, use -nopkg to match instantiations by their names
+// without package qualifiers.
+//
+// Please note that the compiler may inline a generic method and then discard
+// the instantiation entirely, as it does for the Add methods of the
+// BenchmarkGoBuild lists. Build with -gcflags=-l to disable inlining and
+// keep them.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"go-generics-the-hard-way/internal/binsize"
+)
+
+var (
+	asJSON  = flag.Bool("json", false, "print the reports as JSON")
+	symbols = flag.Bool("symbols", false, "print the symbols of each instantiation")
+	filter  = flag.String("filter", "", "only print the instantiations whose names match this regular expression")
+	base    = flag.String("base", "", "print the differences from this binary")
+	noPkg   = flag.Bool("nopkg", false, "remove package qualifiers from the names of instantiations, merging those with the same name")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: binsize [flags] BINARY...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "binsize:", err)
+		os.Exit(1)
+	}
+}
+
+func run(paths []string) error {
+	var re *regexp.Regexp
+	if *filter != "" {
+		var err error
+		if re, err = regexp.Compile(*filter); err != nil {
+			return err
+		}
+	}
+
+	var reports []*binsize.Report
+	for _, p := range paths {
+		r, err := open(p)
+		if err != nil {
+			return err
+		}
+		if re != nil {
+			var ins []*binsize.Instantiation
+			for _, in := range r.Instantiations {
+				if re.MatchString(in.Name) {
+					ins = append(ins, in)
+				}
+			}
+			r.Instantiations = ins
+		}
+		reports = append(reports, r)
+	}
+
+	if *base != "" {
+		b, err := open(*base)
+		if err != nil {
+			return err
+		}
+		for i, r := range reports {
+			printDiff(*base, b, paths[i], r)
+		}
+		return nil
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		out := map[string]*binsize.Report{}
+		for i, r := range reports {
+			out[paths[i]] = r
+		}
+		return enc.Encode(out)
+	}
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		printReport(paths[i], r)
+	}
+	return nil
+}
+
+func open(path string) (*binsize.Report, error) {
+	r, err := binsize.Open(path)
+	if err != nil || !*noPkg {
+		return r, err
+	}
+	var (
+		ins    []*binsize.Instantiation
+		byName = map[string]*binsize.Instantiation{}
+	)
+	for _, in := range r.Instantiations {
+		name := binsize.TrimPackages(in.Name)
+		m, ok := byName[name]
+		if !ok {
+			m = &binsize.Instantiation{Name: name, Generic: binsize.TrimPackages(in.Generic), Shaped: in.Shaped}
+			byName[name] = m
+			ins = append(ins, m)
+		}
+		m.Text += in.Text
+		m.RoData += in.RoData
+		m.Symbols = append(m.Symbols, in.Symbols...)
+	}
+	r.Instantiations = ins
+	return r, nil
+}
+
+func printReport(path string, r *binsize.Report) {
+	fmt.Printf("%s: text %d, rodata %d, generic text %d, generic rodata %d\n\n",
+		path, r.Text, r.RoData, r.GenericText, r.GenericRoData)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TEXT\tRODATA\t  INSTANTIATION")
+	for _, in := range r.Instantiations {
+		fmt.Fprintf(w, "%d\t%d\t  %s\n", in.Text, in.RoData, in.Name)
+		if *symbols {
+			for _, s := range in.Symbols {
+				fmt.Fprintf(w, "\t%d\t    %s %s\n", s.Size, s.Kind, s.Name)
+			}
+		}
+	}
+	w.Flush()
+}
+
+// printDiff prints the instantiations whose sizes differ between the base
+// binary and another.
+func printDiff(basePath string, base *binsize.Report, path string, r *binsize.Report) {
+	fmt.Printf("%s -> %s: generic text %+d, generic rodata %+d\n\n", basePath, path,
+		int64(r.GenericText)-int64(base.GenericText), int64(r.GenericRoData)-int64(base.GenericRoData))
+
+	old := map[string]*binsize.Instantiation{}
+	for _, in := range base.Instantiations {
+		old[in.Name] = in
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TEXT\tRODATA\t  INSTANTIATION")
+	for _, in := range r.Instantiations {
+		o, ok := old[in.Name]
+		delete(old, in.Name)
+		if !ok {
+			o = &binsize.Instantiation{}
+		}
+		if in.Text != o.Text || in.RoData != o.RoData {
+			fmt.Fprintf(w, "%+d\t%+d\t  %s\n", int64(in.Text)-int64(o.Text), int64(in.RoData)-int64(o.RoData), in.Name)
+		}
+	}
+	for _, o := range base.Instantiations {
+		if _, ok := old[o.Name]; ok {
+			fmt.Fprintf(w, "%+d\t%+d\t  %s\n", -int64(o.Text), -int64(o.RoData), o.Name)
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command erasure builds and runs the internals chapter's sample programs
+// with each toolchain that is installed, and prints one table that compares
+// what the Go, Java, and .NET runtimes know about the instantiated list
+// types.
+//
+//	erasure [-json]
+//
+// The programs in ./probes are 05-internals/golang, java, and dotnet,
+// changed to print their runtime's view of the lists rather than stop in
+// the debugger:
+//
+//   - the runtime types of the list of ints and the list of strings, which
+//     are the same type if the type arguments are erased
+//   - whether adding a string to the list of ints through an untyped
+//     reference fails, i.e. whether the runtime is type-safe
+//   - the type of a list of strings built at runtime through reflection,
+//     if the runtime knows it as the instantiated type
+//
+// which reproduces the table in 05-internals/04-summary.md. A language is
+// skipped if its toolchain is not installed. The probes must build the lists
+// the way the chapter's programs do, which TestProbes checks.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+//go:embed probes/golang/main.go probes/java/Main.java probes/dotnet/main.cs
+var probes embed.FS
+
+var asJSON = flag.Bool("json", false, "print the results as JSON")
+
+// Result is a runtime's view of the instantiated list types.
+type Result struct {
+	Language string `json:"language"`
+
+	// Skipped is why the language was skipped, if it was.
+	Skipped string `json:"skipped,omitempty"`
+
+	// Err is why the sample program could not be built or run, if it
+	// could not.
+	Err string `json:"error,omitempty"`
+
+	// Runtime is the version of the runtime.
+	Runtime string `json:"runtime,omitempty"`
+
+	// Ints and Strs are the runtime types of the list of ints and the list
+	// of strings.
+	Ints string `json:"ints,omitempty"`
+	Strs string `json:"strs,omitempty"`
+
+	// Erased is whether the lists have the same runtime type.
+	Erased bool `json:"erased"`
+
+	// TypeSafe is whether the runtime refused to add a string to the list
+	// of ints.
+	TypeSafe bool `json:"typeSafe"`
+
+	// Instantiated is the type of the list of strings the program
+	// instantiated at runtime, if it could.
+	Instantiated string `json:"instantiated,omitempty"`
+}
+
+// language is how to build and run a sample program.
+type language struct {
+	name  string
+	tools []string
+
+	// run builds the program in dir and runs it.
+	run func(ctx context.Context, dir string) ([]byte, error)
+}
+
+var languages = []language{
+	{name: "Go", tools: []string{"go"}, run: runGo},
+	{name: "Java", tools: []string{"javac", "java"}, run: runJava},
+	{name: ".NET", tools: []string{"dotnet"}, run: runDotnet},
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: erasure [flags]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "erasure:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		results []*Result
+		failed  int
+	)
+	for _, l := range languages {
+		r := compare(l)
+		if r.Err != "" {
+			failed++
+		}
+		results = append(results, r)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printTable(results)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sample programs failed", failed, len(results))
+	}
+	return nil
+}
+
+// compare builds and runs a language's sample program in a temporary
+// directory and parses what it prints.
+func compare(l language) *Result {
+	r := &Result{Language: l.name}
+	for _, t := range l.tools {
+		if _, err := exec.LookPath(t); err != nil {
+			r.Skipped = t + " is not installed"
+			return r
+		}
+	}
+	dir, err := os.MkdirTemp("", "erasure-")
+	if err != nil {
+		r.Err = err.Error()
+		return r
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	out, err := l.run(ctx, dir)
+	if err != nil {
+		r.Err = err.Error()
+		return r
+	}
+	if err := parse(r, out); err != nil {
+		r.Err = err.Error()
+	}
+	return r
+}
+
+// parse parses the key=value lines the sample programs print.
+func parse(r *Result, out []byte) error {
+	seen := map[string]bool{}
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		key, val, ok := strings.Cut(strings.TrimSpace(s.Text()), "=")
+		if !ok {
+			continue
+		}
+		seen[key] = true
+		switch key {
+		case "runtime":
+			r.Runtime = val
+		case "ints":
+			r.Ints = val
+		case "strs":
+			r.Strs = val
+		case "safety":
+			safe, err := strconv.ParseBool(val)
+			if err != nil {
+				return fmt.Errorf("invalid safety %q", val)
+			}
+			r.TypeSafe = safe
+		case "instantiate":
+			r.Instantiated = val
+		}
+	}
+	for _, key := range []string{"runtime", "ints", "strs", "safety", "instantiate"} {
+		if !seen[key] {
+			return fmt.Errorf("the sample program did not print %s:\n%s", key, out)
+		}
+	}
+	r.Erased = r.Ints == r.Strs
+	return nil
+}
+
+// writeProbe copies a sample program from probes to dir.
+func writeProbe(dir, name, to string) error {
+	data, err := probes.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, to), data, 0o644)
+}
+
+// command runs a command in dir and returns its output, which includes its
+// standard error if it fails.
+func command(ctx context.Context, dir string, env []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %v\n%s%s", name, strings.Join(args, " "), err, out, stderr.Bytes())
+	}
+	return out, nil
+}
+
+func runGo(ctx context.Context, dir string) ([]byte, error) {
+	if err := writeProbe(dir, "probes/golang/main.go", "main.go"); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module probe\n\ngo 1.18\n"), 0o644); err != nil {
+		return nil, err
+	}
+	return command(ctx, dir, []string{"GOWORK=off", "GOFLAGS="}, "go", "run", ".")
+}
+
+func runJava(ctx context.Context, dir string) ([]byte, error) {
+	if err := writeProbe(dir, "probes/java/Main.java", "Main.java"); err != nil {
+		return nil, err
+	}
+	if _, err := command(ctx, dir, nil, "javac", "Main.java"); err != nil {
+		return nil, err
+	}
+	return command(ctx, dir, nil, "java", "-cp", ".", "Main")
+}
+
+func runDotnet(ctx context.Context, dir string) ([]byte, error) {
+	env := []string{"DOTNET_CLI_TELEMETRY_OPTOUT=1", "DOTNET_NOLOGO=1"}
+
+	// The sample project targets .NET 6, which newer SDKs may not be able
+	// to build, so target the framework of the installed SDK instead.
+	version, err := command(ctx, dir, env, "dotnet", "--version")
+	if err != nil {
+		return nil, err
+	}
+	major, _, _ := strings.Cut(strings.TrimSpace(string(version)), ".")
+	if _, err := strconv.Atoi(major); err != nil {
+		return nil, fmt.Errorf("invalid .NET SDK version %q", version)
+	}
+	proj := fmt.Sprintf(`<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <OutputType>Exe</OutputType>
+    <TargetFramework>net%s.0</TargetFramework>
+    <ImplicitUsings>enable</ImplicitUsings>
+    <Nullable>enable</Nullable>
+  </PropertyGroup>
+</Project>
+`, major)
+	if err := os.WriteFile(filepath.Join(dir, "probe.csproj"), []byte(proj), 0o644); err != nil {
+		return nil, err
+	}
+	if err := writeProbe(dir, "probes/dotnet/main.cs", "main.cs"); err != nil {
+		return nil, err
+	}
+	return command(ctx, dir, env, "dotnet", "run")
+}
+
+func printTable(results []*Result) {
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tRUNTIME\tLIST OF INTS\tLIST OF STRINGS\tERASED\tRUNTIME TYPE SAFETY\tRUNTIME INSTANTIATION")
+	var notes []string
+	for _, r := range results {
+		switch {
+		case r.Skipped != "":
+			notes = append(notes, fmt.Sprintf("%s: skipped, %s", r.Language, r.Skipped))
+			continue
+		case r.Err != "":
+			notes = append(notes, fmt.Sprintf("%s: failed, %s", r.Language, r.Err))
+			continue
+		}
+		inst := "no"
+		if r.Instantiated != "" {
+			inst = "yes, " + r.Instantiated
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Language, r.Runtime, r.Ints, r.Strs, yesNo(r.Erased), yesNo(r.TypeSafe), inst)
+	}
+	w.Flush()
+	if len(notes) > 0 {
+		fmt.Println()
+		for _, n := range notes {
+			fmt.Println(n)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This is 05-internals/dotnet/main.cs, changed to print what the runtime
+// knows about the instantiated lists rather than break into the debugger.
+
+using System.Collections;
+
+namespace console
+{
+    class Program
+    {
+        static void Main(string[] args)
+        {
+            var ints = new List<Int32>();
+            ints.Add(1);
+            ints.Add(2);
+            ints.Add(3);
+
+            var strs = new List<String>();
+            strs.Add("Hello");
+            strs.Add("world");
+
+            Console.Out.WriteLine("runtime=" + Environment.Version);
+            Console.Out.WriteLine("ints=" + ints.GetType());
+            Console.Out.WriteLine("strs=" + strs.GetType());
+
+            // Add a String to the list of Int32 values through the
+            // non-generic IList interface, which checks the type of the
+            // value against the instantiated type.
+            bool safe;
+            try
+            {
+                ((IList)ints).Add("Hello");
+                safe = false;
+            }
+            catch (ArgumentException)
+            {
+                safe = true;
+            }
+            Console.Out.WriteLine("safety=" + safe);
+
+            // Build a List<String> at runtime from the generic List<T>.
+            var t = typeof(List<>).MakeGenericType(typeof(String));
+            Console.Out.WriteLine("instantiate=" + Activator.CreateInstance(t)!.GetType());
+        }
+    }
+}
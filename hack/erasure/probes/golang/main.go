@@ -0,0 +1,67 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This is 05-internals/golang/main.go, changed to print what the runtime
+// knows about the instantiated lists rather than stop at a breakpoint.
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+type List[T any] []T
+
+func (a *List[T]) add(val T) {
+	*a = append(*a, val)
+}
+
+func main() {
+	var ints List[int]
+	ints.add(1)
+	ints.add(2)
+	ints.add(3)
+
+	var strs List[string]
+	strs.add("Hello")
+	strs.add("world")
+
+	fmt.Println("runtime=" + runtime.Version())
+	fmt.Printf("ints=%T\n", ints)
+	fmt.Printf("strs=%T\n", strs)
+
+	// Append a string to the list of ints through reflection, which
+	// checks the type of the value against the instantiated type.
+	safe := func() (safe bool) {
+		defer func() { safe = recover() != nil }()
+		v := reflect.ValueOf(&ints).Elem()
+		v.Set(reflect.Append(v, reflect.ValueOf("Hello")))
+		return false
+	}()
+	fmt.Printf("safety=%t\n", safe)
+
+	// reflect.TypeOf(List) does not compile, see
+	// 05-internals/03-runtime-instantiation, so the closest reflection gets
+	// to building a List[string] at runtime is its underlying []string,
+	// which is a different type.
+	typ := reflect.New(reflect.SliceOf(reflect.TypeOf(""))).Elem().Type()
+	instantiated := ""
+	if typ == reflect.TypeOf(strs) {
+		instantiated = typ.String()
+	}
+	fmt.Println("instantiate=" + instantiated)
+}
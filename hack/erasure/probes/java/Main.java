@@ -0,0 +1,65 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This is 05-internals/java/main.java, changed to print what the runtime
+// knows about the instantiated lists rather than their lengths.
+
+import java.lang.reflect.ParameterizedType;
+import java.lang.reflect.Type;
+import java.util.ArrayList;
+import java.util.List;
+
+class Main {
+    @SuppressWarnings("unchecked")
+    public static void main(String[] args) throws Exception {
+        ArrayList<Integer>  ints = new ArrayList<Integer>();
+        ints.add(1);
+        ints.add(2);
+        ints.add(3);
+
+        ArrayList<String>   strs = new ArrayList<String>();
+        strs.add("Hello");
+        strs.add("world");
+
+        System.out.println("runtime=" + System.getProperty("java.version"));
+        System.out.println("ints=" + ints.getClass().getName());
+        System.out.println("strs=" + strs.getClass().getName());
+
+        // Add a String to the list of Integers through a raw reference. The
+        // list does not know its type argument, so nothing stops it.
+        boolean safe;
+        try {
+            ((List<Object>) (List<?>) ints).add("Hello");
+            safe = false;
+        } catch (ClassCastException e) {
+            safe = true;
+        }
+        System.out.println("safety=" + safe);
+
+        // Build a list at runtime through reflection, as in
+        // 05-internals/03-runtime-instantiation. There is no way to bind the
+        // String class to the type parameter, so the list is a raw ArrayList
+        // whose type parameter is still unbound.
+        Object list = ArrayList.class.getDeclaredConstructor().newInstance();
+        Type type = list.getClass().getGenericSuperclass();
+        String instantiated = "";
+        if (type instanceof ParameterizedType
+                && ((ParameterizedType) type).getActualTypeArguments()[0] == String.class) {
+            instantiated = type.getTypeName();
+        }
+        System.out.println("instantiate=" + instantiated);
+    }
+}
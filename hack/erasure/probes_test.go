@@ -0,0 +1,82 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main_test
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// sampleLine matches the lines of a sample program that declare the list
+// type and build the lists, which the probes must keep as they are.
+var sampleLine = regexp.MustCompile(`\b(ints|strs)\b|^type List\b|^func \(a \*List\b|^\*a = append\(`)
+
+// TestProbes checks that each probe still builds the lists the way the
+// chapter's sample program does, so the table it prints is about the same
+// code as the chapter.
+func TestProbes(t *testing.T) {
+	for _, tc := range []struct{ sample, probe string }{
+		{sample: "../../05-internals/golang/main.go", probe: "probes/golang/main.go"},
+		{sample: "../../05-internals/java/main.java", probe: "probes/java/Main.java"},
+		{sample: "../../05-internals/dotnet/main.cs", probe: "probes/dotnet/main.cs"},
+	} {
+		sample, err := lines(tc.sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		probe, err := lines(tc.probe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want []string
+		for _, l := range sample {
+			// The probes print what the runtime knows about the lists
+			// instead of their lengths.
+			if sampleLine.MatchString(l) && !strings.Contains(l, "printLen") {
+				want = append(want, l)
+			}
+		}
+		if len(want) == 0 {
+			t.Fatalf("found no lines that build the lists in %s", tc.sample)
+		}
+		i := 0
+		for _, l := range probe {
+			if i < len(want) && l == want[i] {
+				i++
+			}
+		}
+		if i < len(want) {
+			t.Errorf("%s does not have the line %q of %s", tc.probe, want[i], tc.sample)
+		}
+	}
+}
+
+// lines returns the lines of a file without their indentation and with runs
+// of spaces collapsed.
+func lines(name string) ([]string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, l := range strings.Split(string(data), "\n") {
+		lines = append(lines, strings.Join(strings.Fields(l), " "))
+	}
+	return lines, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command gcshapes compiles Go packages and prints which of their generic
+// instantiations share stenciled code.
+//
+//	gcshapes [-json] [-inline] [-filter REGEXP] PACKAGE...
+//
+// For each list of GC shapes a generic type or function is stenciled for,
+// ex. main.List[go.shape.int], gcshapes prints the size of the stenciled
+// functions and whether each is passed a runtime dictionary, followed by the
+// concrete instantiations that share the code, ex. main.List[int] and
+// main.List[main.MyInt], with the sizes of their dictionaries and of their
+// wrapper functions. An instantiation with a new shape adds a copy of the
+// code, while one that shares a shape only adds a dictionary and wrappers.
+// For example, for the program in the internals chapter:
+//
+//	gcshapes ./05-internals/golang
+//
+// The packages are compiled with inlining disabled, since inlining copies
+// stenciled code into its callers and hides which shape an instantiation
+// uses. Use -inline to compile them as go build does.
+//
+// The packages are compiled with the go command on the PATH, which must be
+// Go 1.24 or later, as gcshapes reads the compiler's object files and their
+// format is internal to the toolchain.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"go-generics-the-hard-way/internal/gcshape"
+)
+
+var (
+	asJSON = flag.Bool("json", false, "print the reports as JSON")
+	inline = flag.Bool("inline", false, "compile the packages with inlining enabled")
+	filter = flag.String("filter", "", "only print the generic types and functions whose names match this regular expression")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: gcshapes [flags] PACKAGE...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "gcshapes:", err)
+		os.Exit(1)
+	}
+}
+
+func run(patterns []string) error {
+	var re *regexp.Regexp
+	if *filter != "" {
+		var err error
+		if re, err = regexp.Compile(*filter); err != nil {
+			return err
+		}
+	}
+
+	var reports []*gcshape.Report
+	for _, p := range patterns {
+		rs, err := gcshape.Build(p, *inline)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, rs...)
+	}
+	if re != nil {
+		for _, r := range reports {
+			var shapes []*gcshape.Shape
+			for _, sh := range r.Shapes {
+				if re.MatchString(sh.Generic) {
+					shapes = append(shapes, sh)
+				}
+			}
+			r.Shapes = shapes
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	}
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		printReport(r)
+	}
+	return nil
+}
+
+// printReport prints a row per shape, followed by a row per stenciled
+// function with its size and whether it is passed a dictionary, and a row
+// per instantiation with the sizes of its wrappers and dictionary.
+func printReport(r *gcshape.Report) {
+	var shared int
+	for _, sh := range r.Shapes {
+		if n := len(sh.Instantiations); n > 1 {
+			shared += n - 1
+		}
+	}
+	fmt.Printf("%s: %d shapes, %d instantiations share the code of another\n\n", r.Package, len(r.Shapes), shared)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CODE\tDICT\t  NAME")
+	for _, sh := range r.Shapes {
+		fmt.Fprintf(w, "%d\t\t  %s\n", sh.Text, sh.Name)
+		for _, fn := range sh.Funcs {
+			dict := "no"
+			if fn.Dictionary {
+				dict = "yes"
+			}
+			fmt.Fprintf(w, "%d\t%s\t    func %s\n", fn.Size, dict, fn.Name)
+		}
+		for _, in := range sh.Instantiations {
+			fmt.Fprintf(w, "%d\t%d\t    inst %s\n", in.Wrappers, in.Dictionary, in.Name)
+		}
+	}
+	for _, in := range r.Unknown {
+		fmt.Fprintf(w, "%d\t%d\t  unknown shape %s\n", in.Wrappers, in.Dictionary, in.Name)
+	}
+	w.Flush()
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command instances prints the instantiations of generic types and
+// functions in a compiled Go binary as JSON.
+//
+//	instances [-shapes] BINARY [GENERIC...]
+//
+// Generic "templates" do not exist at runtime, so reflection cannot list
+// the instantiations of a generic type. instances reads them from the
+// binary instead: the concrete instantiations of types, ex. main.List[int]
+// and main.List[string], from the runtime type descriptors reachable from
+// the type links, and the code stenciled for each GC shape, ex.
+// main.List[go.shape.int], from the pclntab. For example, for the program in
+// the internals chapter:
+//
+//	go build -gcflags=-l -o /tmp/lists ./05-internals/golang
+//	instances /tmp/lists main.List
+//
+// Without GENERIC, instances prints the instantiations of every generic
+// type and function, and a GENERIC without a package qualifier, ex. List,
+// matches the generics of every package. Only the concrete instantiations
+// are printed unless -shapes is given.
+//
+// Please note that the compiler may inline the methods of an instantiation
+// and then discard its type descriptors, as it does for the program above.
+// Build with -gcflags=-l to disable inlining and keep them. instances exits
+// with an error if it finds no instantiations of a GENERIC. The binary must
+// be built with Go 1.27 or later, as the layout of the runtime's module data
+// is internal to the toolchain.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-generics-the-hard-way/internal/instances"
+)
+
+var shapes = flag.Bool("shapes", false, "also print the instantiations stenciled for GC shapes")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: instances [flags] BINARY [GENERIC...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "instances:", err)
+		os.Exit(1)
+	}
+}
+
+func run(path string, generics []string) error {
+	r, err := instances.Open(path)
+	if err != nil {
+		return err
+	}
+	var (
+		ins     []*instances.Instantiation
+		missing []string
+	)
+	if len(generics) == 0 {
+		ins = filter(r.Instantiations)
+	}
+	for _, g := range generics {
+		found := filter(r.Lookup(g))
+		if len(found) == 0 {
+			missing = append(missing, g)
+		}
+		ins = append(ins, found...)
+	}
+	r.Instantiations = append([]*instances.Instantiation{}, ins...)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		// The compiler discards the type descriptors of instantiations
+		// whose methods it inlined, which is the usual reason.
+		return fmt.Errorf("no instantiations of %s found; if the program uses them, rebuild it with -gcflags=-l", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// filter drops the instantiations stenciled for GC shapes unless -shapes is
+// given.
+func filter(ins []*instances.Instantiation) []*instances.Instantiation {
+	var out []*instances.Instantiation
+	for _, in := range ins {
+		if *shapes || !in.Shaped {
+			out = append(out, in)
+		}
+	}
+	return out
+}
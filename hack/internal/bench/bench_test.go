@@ -0,0 +1,220 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-generics-the-hard-way/hack/internal/bench"
+)
+
+const output = `goos: linux
+goarch: amd64
+pkg: go-generics-the-hard-way/06-benchmarks
+=== RUN   BenchmarkGoBuild
+BenchmarkGoBuild
+BenchmarkGoBuild/generic/pkg/5-types-8         	      30	  42252446 ns/op	     46278 filesize/op
+BenchmarkGoBuild/typed/pkg/5-types-8           	      28	  39945665 ns/op	     24098 filesize/op
+BenchmarkGoBuild/generic/pkg/5-types-8         	      30	  42252448 ns/op	     46278 filesize/op
+BenchmarkGoBuild/typed/pkg/5-types-8           	      28	  39945667 ns/op	     24098 filesize/op
+BenchmarkGoBuild/typed/bin/5-types-8           	       1	1631208265 ns/op	   1125280 filesize/op
+    benchmarks_test.go:42: a log line: with a colon
+goarch: arm64
+BenchmarkBoxing/boxed         	28639768	        49.42 ns/op	     100 B/op	       0 allocs/op
+PASS
+ok  	go-generics-the-hard-way/06-benchmarks	20.128s
+`
+
+func parse(t *testing.T) []bench.Result {
+	t.Helper()
+	results, err := bench.Parse(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+func TestParse(t *testing.T) {
+	results := parse(t)
+	if len(results) != 6 {
+		t.Fatalf("got %d results, want 6", len(results))
+	}
+
+	r := results[0]
+	if r.Name != "BenchmarkGoBuild/generic/pkg/5-types" || r.Procs != 8 || r.Iters != 30 {
+		t.Errorf("got %s, %d procs, %d iterations", r.Name, r.Procs, r.Iters)
+	}
+	if v, ok := r.Value("filesize/op"); !ok || v != 46278 {
+		t.Errorf("filesize/op = %v, %v, want 46278", v, ok)
+	}
+	if r.Config["goarch"] != "amd64" || r.Config["pkg"] != "go-generics-the-hard-way/06-benchmarks" {
+		t.Errorf("Config = %v", r.Config)
+	}
+
+	r = results[5]
+	if r.Name != "BenchmarkBoxing/boxed" || r.Procs != 1 || r.Config["goarch"] != "arm64" {
+		t.Errorf("got %s, %d procs, config %v", r.Name, r.Procs, r.Config)
+	}
+	want := []bench.Metric{{49.42, "ns/op"}, {100, "B/op"}, {0, "allocs/op"}}
+	if !reflect.DeepEqual(r.Metrics, want) {
+		t.Errorf("Metrics = %v, want %v", r.Metrics, want)
+	}
+
+	if _, err := bench.Parse(strings.NewReader("BenchmarkX 1 fast ns/op\n")); err == nil {
+		t.Error("got no error for an invalid value")
+	}
+}
+
+func TestParseProcs(t *testing.T) {
+	testCases := []struct {
+		name   string
+		output string
+		names  []string
+		procs  []int
+	}{
+		{
+			name:   "GOMAXPROCS=8",
+			output: "BenchmarkX/size-8-8 1 1 ns/op\nBenchmarkX/size-16-8 1 1 ns/op\nBenchmarkY-8 1 1 ns/op\n",
+			names:  []string{"BenchmarkX/size-8", "BenchmarkX/size-16", "BenchmarkY"},
+			procs:  []int{8, 8, 8},
+		},
+		{
+			name:   "GOMAXPROCS=1",
+			output: "BenchmarkX/size-8 1 1 ns/op\nBenchmarkX/size-16 1 1 ns/op\nBenchmarkY 1 1 ns/op\n",
+			names:  []string{"BenchmarkX/size-8", "BenchmarkX/size-16", "BenchmarkY"},
+			procs:  []int{1, 1, 1},
+		},
+		{
+			name:   "cpu list",
+			output: "BenchmarkX 1 1 ns/op\nBenchmarkX-4 1 1 ns/op\nBenchmarkY 1 1 ns/op\nBenchmarkY-4 1 1 ns/op\n",
+			names:  []string{"BenchmarkX", "BenchmarkX", "BenchmarkY", "BenchmarkY"},
+			procs:  []int{1, 4, 1, 4},
+		},
+		{
+			name:   "runs",
+			output: "pkg: a\nBenchmarkX-8 1 1 ns/op\npkg: b\nBenchmarkX/size-8 1 1 ns/op\nBenchmarkY 1 1 ns/op\n",
+			names:  []string{"BenchmarkX", "BenchmarkX/size-8", "BenchmarkY"},
+			procs:  []int{8, 1, 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := bench.Parse(strings.NewReader(tc.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				names []string
+				procs []int
+			)
+			for _, r := range results {
+				names = append(names, r.Name)
+				procs = append(procs, r.Procs)
+			}
+			if !reflect.DeepEqual(names, tc.names) || !reflect.DeepEqual(procs, tc.procs) {
+				t.Errorf("got %v %v, want %v %v", names, procs, tc.names, tc.procs)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	sums := bench.Summarize(parse(t))
+	if len(sums) != 4 {
+		t.Fatalf("got %d summaries, want 4", len(sums))
+	}
+	s := sums[0]
+	if s.Runs() != 2 {
+		t.Errorf("Runs() = %d, want 2", s.Runs())
+	}
+	if m, _ := s.Mean("ns/op"); m != 42252447 {
+		t.Errorf("Mean(ns/op) = %v, want 42252447", m)
+	}
+	if want := []string{bench.Iters, "ns/op", "filesize/op"}; !reflect.DeepEqual(s.Units, want) {
+		t.Errorf("Units = %v, want %v", s.Units, want)
+	}
+}
+
+func TestTables(t *testing.T) {
+	sums := bench.Summarize(parse(t))
+	tables, err := bench.Tables(sums[:3], bench.TableOptions{
+		Pivot:    1,
+		Baseline: "typed",
+		Metrics:  []string{"filesize/op"},
+		Keys:     []string{"Artifact type", "Number of types"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := bench.WriteTables(&got, "md", tables); err != nil {
+		t.Fatal(err)
+	}
+	const want = `| Artifact type | Number of types | filesize/op - typed | filesize/op - generic | Increase (filesize/op) - generic | Increase (%) - generic |
+|:---:|:---:|:---:|:---:|:---:|:---:|
+| pkg | 5-types | 24098 | 46278 | 22180 | 92.04 |
+| bin | 5-types | 1125280 |  |  |  |
+`
+	if got.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", got.String(), want)
+	}
+
+	got.Reset()
+	if err := bench.WriteTables(&got, "json", tables); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []bench.Table
+	if err := json.Unmarshal(got.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if vals := decoded[0].Rows[1].Values; len(vals) != 4 || *vals[0] != 1125280 || vals[1] != nil {
+		t.Errorf("got values %v, want 1125280 and no generic value", vals)
+	}
+
+	if _, err := bench.Tables(sums, bench.TableOptions{Pivot: 2}); err == nil {
+		t.Error("got no error for a benchmark without a pivot element")
+	}
+
+	got.Reset()
+	if err := bench.WriteTables(&got, "csv", tables); err != nil {
+		t.Fatal(err)
+	}
+	if line := "BenchmarkGoBuild,pkg,5-types,24098,46278,22180,92.04\n"; !strings.Contains(got.String(), line) {
+		t.Errorf("got\n%s\nwant a line %q", got.String(), line)
+	}
+	if err := bench.WriteTables(&got, "yaml", tables); err == nil {
+		t.Error("got no error for an unknown format")
+	}
+
+	// The mean number of iterations is rounded, as it is a count.
+	tables, err = bench.Tables([]*bench.Summary{{
+		Name:    "BenchmarkBoxing/boxed/1-types",
+		Path:    []string{"BenchmarkBoxing", "boxed", "1-types"},
+		Units:   []string{bench.Iters, "ns/op"},
+		Samples: map[string][]float64{bench.Iters: {28, 29}, "ns/op": {49.25, 49.5}},
+	}}, bench.TableOptions{Keys: []string{"List type", "Number of types"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals := tables[0].Rows[0].Values; len(vals) != 2 || *vals[0] != 29 || *vals[1] != 49.375 {
+		t.Errorf("got values %v, want 29 iterations and 49.375 ns/op", vals)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import "math"
+
+// DefaultCompareMetrics are the metrics Compare checks if none are given.
+// Lower is better for all of them.
+var DefaultCompareMetrics = []string{"ns/op", "B/op", "filesize/op"}
+
+// deterministic are the units of the metrics that do not vary between runs,
+// so that a single sample of each is enough to compare them.
+var deterministic = map[string]bool{"filesize/op": true}
+
+// CompareOptions configure Compare.
+type CompareOptions struct {
+
+	// Metrics are the units of the metrics to compare, for which lower
+	// values must be better. If empty, DefaultCompareMetrics are used.
+	Metrics []string
+
+	// Threshold is the increase, in percent, past which a significant
+	// change is a regression.
+	Threshold float64
+
+	// Alpha is the significance level, ex. 0.05.
+	Alpha float64
+}
+
+// Comparison is the change in a benchmark's metric between two sets of
+// results.
+type Comparison struct {
+	Name string
+	Unit string
+
+	// Old and New are the samples of the metric.
+	Old, New []float64
+
+	// OldMean and NewMean are the means of the samples.
+	OldMean, NewMean float64
+
+	// Delta is the change from OldMean to NewMean in percent. If OldMean is
+	// 0 it is +Inf if NewMean is greater than 0, ex. a benchmark that did
+	// not allocate and now does, and otherwise 0.
+	Delta float64
+
+	// P is the p-value of a Mann-Whitney U test of the samples.
+	P float64
+
+	// Significant is whether the change is statistically significant. A
+	// change in a metric that does not vary between runs, such as
+	// filesize/op, is always significant, as is a change between at least
+	// two samples of each that are all the same. A single sample of another
+	// metric, ex. ns/op with -count 1, is never enough.
+	Significant bool
+
+	// Regression is whether the change is significant and the increase is
+	// past the threshold.
+	Regression bool
+}
+
+// Compare compares the metrics of the benchmarks in both old and new, in the
+// order of new.
+func Compare(old, new []*Summary, opts CompareOptions) []Comparison {
+	metrics := opts.Metrics
+	if len(metrics) == 0 {
+		metrics = DefaultCompareMetrics
+	}
+	byName := map[string]*Summary{}
+	for _, s := range old {
+		byName[s.Name] = s
+	}
+
+	var cmps []Comparison
+	for _, newSum := range new {
+		oldSum, ok := byName[newSum.Name]
+		if !ok {
+			continue
+		}
+		for _, unit := range metrics {
+			x, y := oldSum.Samples[unit], newSum.Samples[unit]
+			if len(x) == 0 || len(y) == 0 {
+				continue
+			}
+			c := Comparison{Name: newSum.Name, Unit: unit, Old: x, New: y}
+			c.OldMean, _ = oldSum.Mean(unit)
+			c.NewMean, _ = newSum.Mean(unit)
+			switch {
+			case c.OldMean != 0:
+				c.Delta = (c.NewMean - c.OldMean) / c.OldMean * 100
+			case c.NewMean > 0:
+				c.Delta = math.Inf(1)
+			}
+			enough := deterministic[unit] || len(x) > 1 && len(y) > 1
+			if enough && constant(x) && constant(y) {
+				c.P = 1
+				if x[0] != y[0] {
+					c.P = 0
+				}
+			} else {
+				_, c.P = MannWhitneyU(x, y)
+			}
+			c.Significant = c.P < opts.Alpha
+			c.Regression = c.Significant && c.Delta > opts.Threshold
+			cmps = append(cmps, c)
+		}
+	}
+	return cmps
+}
+
+// constant reports whether every value in vals is the same.
+func constant(vals []float64) bool {
+	for _, v := range vals[1:] {
+		if v != vals[0] {
+			return false
+		}
+	}
+	return true
+}
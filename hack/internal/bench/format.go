@@ -0,0 +1,156 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Formats are the formats tables can be written in.
+var Formats = []string{"md", "csv", "json", "html"}
+
+// WriteTables writes the tables to w in the given format, which is one of
+// Formats.
+func WriteTables(w io.Writer, format string, tables []*Table) error {
+	switch format {
+	case "md":
+		return writeMarkdown(w, tables)
+	case "csv":
+		return writeCSV(w, tables)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tables)
+	case "html":
+		return htmlTemplate.Execute(w, tables)
+	}
+	return fmt.Errorf("unknown format %q, must be one of %s", format, strings.Join(Formats, ", "))
+}
+
+// FormatValue formats v the way the tables in 06-benchmarks do: integers
+// without a fraction and everything else rounded to two decimal places.
+func FormatValue(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	f := *v
+	if f != math.Trunc(f) {
+		f = math.Round(f*100) / 100
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func (t *Table) header() []string {
+	return append(append([]string(nil), t.KeyColumns...), t.ValueColumns...)
+}
+
+func (r Row) cells() []string {
+	cells := append([]string(nil), r.Key...)
+	for _, v := range r.Values {
+		cells = append(cells, FormatValue(v))
+	}
+	return cells
+}
+
+// writeMarkdown writes each table as a Markdown table, preceded by a heading
+// if there is more than one. A key that repeats the previous row's is left
+// blank so the rows read as groups.
+func writeMarkdown(w io.Writer, tables []*Table) error {
+	var b strings.Builder
+	for i, t := range tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if len(tables) > 1 {
+			fmt.Fprintf(&b, "### %s\n\n", t.Name)
+		}
+		header := t.header()
+		writeMarkdownRow(&b, header)
+		for range header {
+			b.WriteString("|:---:")
+		}
+		b.WriteString("|\n")
+
+		var prev []string
+		for _, r := range t.Rows {
+			cells := r.cells()
+			for j := 0; j < len(r.Key)-1 && j < len(prev) && prev[j] == r.Key[j]; j++ {
+				cells[j] = ""
+			}
+			prev = r.Key
+			writeMarkdownRow(&b, cells)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	for _, c := range cells {
+		b.WriteString("| ")
+		b.WriteString(strings.ReplaceAll(c, "|", `\|`))
+		b.WriteString(" ")
+	}
+	b.WriteString("|\n")
+}
+
+// writeCSV writes the tables as CSV with a Benchmark column for the name of
+// the table. Each table has its own header and tables are separated by an
+// empty line.
+func writeCSV(w io.Writer, tables []*Table) error {
+	cw := csv.NewWriter(w)
+	for i, t := range tables {
+		if i > 0 {
+			cw.Flush()
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := cw.Write(append([]string{"Benchmark"}, t.header()...)); err != nil {
+			return err
+		}
+		for _, r := range t.Rows {
+			if err := cw.Write(append([]string{t.Name}, r.cells()...)); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{
+	"value": FormatValue,
+}).Parse(`{{range .}}<table>
+  <caption>{{.Name}}</caption>
+  <thead>
+    <tr>{{range .KeyColumns}}<th>{{.}}</th>{{end}}{{range .ValueColumns}}<th>{{.}}</th>{{end}}</tr>
+  </thead>
+  <tbody>
+{{- range .Rows}}
+    <tr>{{range .Key}}<td>{{.}}</td>{{end}}{{range .Values}}<td>{{value .}}</td>{{end}}</tr>
+{{- end}}
+  </tbody>
+</table>
+{{end}}`))
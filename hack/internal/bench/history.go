@@ -0,0 +1,196 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Key identifies a set of results in a History.
+type Key struct {
+	GoVersion string // ex. go1.18
+	GOARCH    string // ex. amd64
+	Commit    string // ex. a638dbc
+}
+
+func (k Key) String() string {
+	return k.GoVersion + "/" + k.GOARCH + "/" + k.Commit
+}
+
+func (k Key) validate() error {
+	for _, s := range []string{k.GoVersion, k.GOARCH, k.Commit} {
+		if s == "" || s == "." || s == ".." || strings.ContainsAny(s, `/\`) {
+			return fmt.Errorf("invalid key %q", k)
+		}
+	}
+	return nil
+}
+
+// Entry is a set of results in a History.
+type Entry struct {
+	Key
+
+	// Date is when the results were saved.
+	Date time.Time
+
+	// Path is the file the results are stored in.
+	Path string
+}
+
+// ErrNotFound is returned by History.Find when no entry matches.
+var ErrNotFound = errors.New("no matching results")
+
+// History is a store of benchmark results in flat files, keyed by the Go
+// version, GOARCH, and git commit they were recorded with.
+//
+// Each set of results is stored at Dir/GOVERSION/GOARCH/COMMIT.txt as the
+// output of go test -bench, preceded by configuration lines for the key and
+// the date, so the files can be read by any tool that reads that format.
+type History struct {
+	Dir string
+}
+
+// Save stores the output of go test -bench under the key, replacing any
+// results already stored under it.
+func (h History) Save(k Key, date time.Time, output []byte) (Entry, error) {
+	if err := k.validate(); err != nil {
+		return Entry{}, err
+	}
+	e := Entry{Key: k, Date: date.UTC().Truncate(time.Second), Path: h.path(k)}
+	if err := os.MkdirAll(filepath.Dir(e.Path), 0o755); err != nil {
+		return Entry{}, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "commit: %s\n", k.Commit)
+	fmt.Fprintf(&b, "goversion: %s\n", k.GoVersion)
+	fmt.Fprintf(&b, "date: %s\n", e.Date.Format(time.RFC3339))
+	b.Write(output)
+	return e, os.WriteFile(e.Path, b.Bytes(), 0o644)
+}
+
+func (h History) path(k Key) string {
+	return filepath.Join(h.Dir, k.GoVersion, k.GOARCH, k.Commit+".txt")
+}
+
+// Entries returns the entries in the history, from the oldest to the newest.
+func (h History) Entries() ([]Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(h.Dir, "*", "*", "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, p := range paths {
+		rel, err := filepath.Rel(h.Dir, p)
+		if err != nil {
+			return nil, err
+		}
+		elems := strings.Split(filepath.ToSlash(rel), "/")
+		e := Entry{
+			Key: Key{
+				GoVersion: elems[0],
+				GOARCH:    elems[1],
+				Commit:    strings.TrimSuffix(elems[2], ".txt"),
+			},
+			Path: p,
+		}
+		if e.Date, err = readDate(p); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+	return entries, nil
+}
+
+// readDate reads the date from the configuration lines at the start of a
+// file written by Save.
+func readDate(path string) (time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, val, ok := parseConfig(scanner.Text())
+		if !ok {
+			break
+		}
+		if key == "date" {
+			return time.Parse(time.RFC3339, val)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, fmt.Errorf("%s: no date", path)
+}
+
+// Find returns the newest entry that matches ref, which is one or more
+// elements separated by slashes, ex. go1.18, a638dbc, or go1.18/arm64. Each
+// element must equal the entry's Go version or GOARCH, or be a prefix of its
+// commit.
+func (h History) Find(ref string) (Entry, error) {
+	entries, err := h.Entries()
+	if err != nil {
+		return Entry{}, err
+	}
+	elems := strings.Split(ref, "/")
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].matches(elems) {
+			return entries[i], nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w for %q in %s", ErrNotFound, ref, h.Dir)
+}
+
+func (e Entry) matches(elems []string) bool {
+	for _, el := range elems {
+		if el == "" {
+			return false
+		}
+		if el != e.GoVersion && el != e.GOARCH && !strings.HasPrefix(e.Commit, el) {
+			return false
+		}
+	}
+	return true
+}
+
+// Load reads the results of the entry.
+func (e Entry) Load() ([]Result, error) {
+	f, err := os.Open(e.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	results, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.Path, err)
+	}
+	return results, nil
+}
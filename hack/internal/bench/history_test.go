@@ -0,0 +1,88 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-generics-the-hard-way/hack/internal/bench"
+)
+
+func TestHistory(t *testing.T) {
+	h := bench.History{Dir: t.TempDir()}
+	date := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	keys := []bench.Key{
+		{GoVersion: "go1.18", GOARCH: "amd64", Commit: "a638dbc"},
+		{GoVersion: "go1.18", GOARCH: "arm64", Commit: "a638dbc"},
+		{GoVersion: "go1.19", GOARCH: "amd64", Commit: "a638dbc"},
+		{GoVersion: "go1.19", GOARCH: "amd64", Commit: "d6d17f2"},
+	}
+	for i, k := range keys {
+		if _, err := h.Save(k, date.Add(time.Duration(i)*time.Hour), []byte(output)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := h.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(keys) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(keys))
+	}
+	for i, e := range entries {
+		if e.Key != keys[i] || !e.Date.Equal(date.Add(time.Duration(i)*time.Hour)) {
+			t.Errorf("entry %d is %v at %v", i, e.Key, e.Date)
+		}
+	}
+
+	testCases := []struct {
+		ref  string
+		want bench.Key
+	}{
+		{ref: "go1.18", want: keys[1]},
+		{ref: "go1.18/amd64", want: keys[0]},
+		{ref: "a638", want: keys[2]},
+		{ref: "amd64/d6d", want: keys[3]},
+	}
+	for _, tc := range testCases {
+		e, err := h.Find(tc.ref)
+		if err != nil || e.Key != tc.want {
+			t.Errorf("Find(%q) = %v, %v, want %v", tc.ref, e.Key, err, tc.want)
+		}
+	}
+	for _, ref := range []string{"go1.20", "go1.18/d6d", "", "a638//"} {
+		if _, err := h.Find(ref); !errors.Is(err, bench.ErrNotFound) {
+			t.Errorf("Find(%q) = %v, want ErrNotFound", ref, err)
+		}
+	}
+
+	e, _ := h.Find("d6d")
+	results, err := e.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 6 || results[0].Config["commit"] != "d6d17f2" {
+		t.Errorf("got %d results with config %v", len(results), results[0].Config)
+	}
+
+	if _, err := h.Save(bench.Key{GoVersion: "../go", GOARCH: "amd64", Commit: "x"}, date, nil); err == nil {
+		t.Error("got no error for an invalid key")
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bench parses the output of go test -bench and summarizes it.
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Iters is the name of the pseudo-metric for the number of iterations a
+// benchmark ran for.
+const Iters = "iters"
+
+// Metric is a value reported by a benchmark, ex. 8.31 ns/op or 44808
+// filesize/op.
+type Metric struct {
+	Value float64
+	Unit  string
+}
+
+// Result is the result of one run of a benchmark.
+type Result struct {
+
+	// Name is the benchmark's name without the GOMAXPROCS suffix, ex.
+	// BenchmarkGoBuild/generic/bin/5-types.
+	Name string
+
+	// Procs is the value of GOMAXPROCS the benchmark ran with, or 1 if the
+	// name had no suffix. Please see Parse for when a suffix is recognized.
+	Procs int
+
+	// Iters is the number of iterations the benchmark ran for.
+	Iters int64
+
+	// Metrics are the benchmark's metrics in the order they were reported.
+	Metrics []Metric
+
+	// Config is the configuration printed by go test before the benchmark,
+	// ex. goos, goarch, pkg, and cpu. It is shared by results and must not
+	// be modified.
+	Config map[string]string
+}
+
+// Path returns the elements of the benchmark's name, ex. BenchmarkGoBuild,
+// generic, bin, and 5-types.
+func (r *Result) Path() []string {
+	return strings.Split(r.Name, "/")
+}
+
+// Value returns the value of the metric with the given unit.
+func (r *Result) Value(unit string) (float64, bool) {
+	if unit == Iters {
+		return float64(r.Iters), true
+	}
+	for _, m := range r.Metrics {
+		if m.Unit == unit {
+			return m.Value, true
+		}
+	}
+	return 0, false
+}
+
+// Parse reads the output of go test -bench from r and returns the results of
+// the benchmarks in the order they ran. Lines that are not benchmark results
+// or configuration, such as the output of -v, are ignored.
+//
+// A benchmark's name ends in a -N GOMAXPROCS suffix unless GOMAXPROCS is 1,
+// but a sub-benchmark's own name may also end in -N, ex. size-8. So a
+// trailing -N is only treated as a GOMAXPROCS suffix if it is consistent
+// across a run, the results that share a configuration: every benchmark in
+// the run must have been reported with the same set of suffixes, where no
+// suffix counts as 1, ex. all with -8, or each with -2 and -4 from -cpu 2,4.
+func Parse(r io.Reader) ([]Result, error) {
+	var (
+		results []Result
+		config  = map[string]string{}
+
+		// run is the index of the first result of the current run.
+		run int
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if key, val, ok := parseConfig(text); ok {
+			if config[key] != val {
+				// Copy the configuration so the results already
+				// parsed keep theirs.
+				c := make(map[string]string, len(config)+1)
+				for k, v := range config {
+					c[k] = v
+				}
+				c[key] = val
+				config = c
+				splitProcs(results[run:])
+				run = len(results)
+			}
+			continue
+		}
+		res, ok, err := parseResult(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if ok {
+			res.Config = config
+			results = append(results, res)
+		}
+	}
+	splitProcs(results[run:])
+	return results, scanner.Err()
+}
+
+// parseConfig parses a configuration line, ex. "goarch: amd64".
+func parseConfig(line string) (key, val string, ok bool) {
+	i := strings.Index(line, ": ")
+	if i <= 0 {
+		return "", "", false
+	}
+	key = line[:i]
+	for _, r := range key {
+		if !unicode.IsLower(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", "", false
+		}
+	}
+	return key, strings.TrimSpace(line[i+2:]), true
+}
+
+// parseResult parses a benchmark result line, ex.
+// "BenchmarkBoxing/typed-8   300006311   8.49 ns/op   43 B/op".
+func parseResult(line string) (Result, bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return Result{}, false, nil
+	}
+	iters, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		// A benchmark that logged output, or a line such as
+		// "BenchmarkFoo failed", is not a result.
+		return Result{}, false, nil
+	}
+
+	res := Result{Name: fields[0], Iters: iters, Procs: 1}
+	for i := 2; i < len(fields); i += 2 {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Result{}, false, fmt.Errorf("%s: invalid value %q for %s", res.Name, fields[i], fields[i+1])
+		}
+		res.Metrics = append(res.Metrics, Metric{Value: v, Unit: fields[i+1]})
+	}
+	return res, true, nil
+}
+
+// splitProcs splits the GOMAXPROCS suffix from the names of the results of a
+// run if the suffixes are consistent across the run. Please see Parse.
+func splitProcs(run []Result) {
+	var (
+		names = make([]string, len(run))
+		procs = make([]int, len(run))
+		sets  = map[string]map[int]bool{}
+		found bool
+	)
+	for i := range run {
+		name, n, ok := cutProcs(run[i].Name)
+		found = found || ok
+		names[i], procs[i] = name, n
+		if sets[name] == nil {
+			sets[name] = map[int]bool{}
+		}
+		sets[name][n] = true
+	}
+	if !found {
+		return
+	}
+	var want map[int]bool
+	for _, set := range sets {
+		if want == nil {
+			want = set
+			continue
+		}
+		if len(set) != len(want) {
+			return
+		}
+		for n := range set {
+			if !want[n] {
+				return
+			}
+		}
+	}
+	for i := range run {
+		run[i].Name, run[i].Procs = names[i], procs[i]
+	}
+}
+
+// cutProcs splits a trailing -N from the last element of a benchmark's name.
+// If there is no such suffix the name is returned with 1 and false.
+func cutProcs(name string) (string, int, bool) {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 || strings.LastIndexByte(name, '/') > i {
+		return name, 1, false
+	}
+	procs, err := strconv.Atoi(name[i+1:])
+	if err != nil || procs <= 0 {
+		return name, 1, false
+	}
+	return name[:i], procs, true
+}
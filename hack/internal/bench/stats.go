@@ -0,0 +1,133 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"math"
+	"sort"
+)
+
+// exactLimit is the largest sample size for which MannWhitneyU computes the
+// exact distribution of U rather than the normal approximation.
+const exactLimit = 20
+
+// MannWhitneyU performs a two-sided Mann-Whitney U test of whether the
+// samples x and y come from the same distribution, which, unlike a t-test,
+// does not assume the samples are normally distributed. It returns the U
+// statistic for x and the p-value.
+//
+// The p-value is exact if neither sample is larger than 20 and there are no
+// ties, and otherwise uses the normal approximation with a correction for
+// ties. If either sample is empty, or every value is the same, the p-value
+// is 1.
+func MannWhitneyU(x, y []float64) (u, p float64) {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+
+	type obs struct {
+		v float64
+		x bool
+	}
+	all := make([]obs, 0, n1+n2)
+	for _, v := range x {
+		all = append(all, obs{v, true})
+	}
+	for _, v := range y {
+		all = append(all, obs{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// Rank the values, giving tied values the mean of their ranks.
+	var rx, ties float64
+	for i := 0; i < len(all); {
+		j := i + 1
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].x {
+				rx += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties += t*t*t - t
+		}
+		i = j
+	}
+	u = rx - float64(n1*(n1+1))/2
+
+	if ties == 0 && n1 <= exactLimit && n2 <= exactLimit {
+		return u, exactP(n1, n2, u)
+	}
+
+	n := float64(n1 + n2)
+	mu := float64(n1*n2) / 2
+	sigma := math.Sqrt(float64(n1*n2) / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return u, 1
+	}
+	z := (math.Abs(u-mu) - 0.5) / sigma
+	if z < 0 {
+		z = 0
+	}
+	return u, math.Erfc(z / math.Sqrt2)
+}
+
+// exactP returns the two-sided p-value of the statistic u for samples of
+// sizes n1 and n2 without ties.
+func exactP(n1, n2 int, u float64) float64 {
+	// counts[i][j][k] is the number of orderings of i values from x and j
+	// values from y for which U is k. Only the previous i is kept.
+	max := n1 * n2
+	prev := make([][]float64, n2+1)
+	for j := range prev {
+		prev[j] = make([]float64, max+1)
+		prev[j][0] = 1
+	}
+	for i := 1; i <= n1; i++ {
+		cur := make([][]float64, n2+1)
+		cur[0] = make([]float64, max+1)
+		cur[0][0] = 1
+		for j := 1; j <= n2; j++ {
+			cur[j] = make([]float64, max+1)
+			for k := 0; k <= i*j; k++ {
+				// The largest value is from x, which then exceeds the
+				// j values from y, or from y.
+				if k >= j {
+					cur[j][k] += prev[j][k-j]
+				}
+				cur[j][k] += cur[j-1][k]
+			}
+		}
+		prev = cur
+	}
+	dist := prev[n2]
+
+	// Sum the tail on the side of u that is closer to the edge.
+	k := math.Min(u, float64(max)-u)
+	var tail, total float64
+	for i, c := range dist {
+		total += c
+		if float64(i) <= k {
+			tail += c
+		}
+	}
+	return math.Min(1, 2*tail/total)
+}
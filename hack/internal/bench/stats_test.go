@@ -0,0 +1,152 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench_test
+
+import (
+	"math"
+	"testing"
+
+	"go-generics-the-hard-way/hack/internal/bench"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	testCases := []struct {
+		name string
+		x, y []float64
+		u, p float64
+	}{
+		{
+			name: "separated",
+			x:    []float64{1, 2, 3, 4, 5},
+			y:    []float64{6, 7, 8, 9, 10},
+			u:    0,
+			p:    2.0 / 252,
+		},
+		{
+			name: "separated reversed",
+			x:    []float64{6, 7, 8},
+			y:    []float64{1, 2, 3},
+			u:    9,
+			p:    2.0 / 20,
+		},
+		{
+			name: "interleaved",
+			x:    []float64{1, 4, 5, 8},
+			y:    []float64{2, 3, 6, 7},
+			u:    8,
+			p:    1,
+		},
+		{
+			name: "one each",
+			x:    []float64{1},
+			y:    []float64{2},
+			u:    0,
+			p:    1,
+		},
+		{
+			name: "identical",
+			x:    []float64{3, 3, 3},
+			y:    []float64{3, 3, 3},
+			u:    4.5,
+			p:    1,
+		},
+		{
+			name: "empty",
+			y:    []float64{1},
+			p:    1,
+		},
+	}
+	for _, tc := range testCases {
+		u, p := bench.MannWhitneyU(tc.x, tc.y)
+		if u != tc.u || math.Abs(p-tc.p) > 1e-9 {
+			t.Errorf("%s: got U=%v p=%v, want U=%v p=%v", tc.name, u, p, tc.u, tc.p)
+		}
+	}
+
+	// With ties the normal approximation is used: 10 vs 10 separated
+	// samples with ties are still significant.
+	x := []float64{1, 1, 2, 2, 3, 3, 4, 4, 5, 5}
+	y := []float64{6, 6, 7, 7, 8, 8, 9, 9, 10, 10}
+	if _, p := bench.MannWhitneyU(x, y); p > 0.001 {
+		t.Errorf("got p=%v for separated samples with ties", p)
+	}
+}
+
+func summary(name, unit string, vals ...float64) *bench.Summary {
+	return &bench.Summary{
+		Name:    name,
+		Units:   []string{unit},
+		Samples: map[string][]float64{unit: vals},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := []*bench.Summary{
+		summary("BenchmarkSlow", "ns/op", 10, 11, 10, 12, 11),
+		summary("BenchmarkNoisy", "ns/op", 10, 11, 10, 12, 11),
+		summary("BenchmarkSize", "filesize/op", 1000),
+		summary("BenchmarkGone", "ns/op", 1),
+		summary("BenchmarkAllocs", "B/op", 0, 0, 0),
+	}
+	new := []*bench.Summary{
+		summary("BenchmarkSize", "filesize/op", 1100),
+		summary("BenchmarkSlow", "ns/op", 20, 21, 22, 20, 21),
+		summary("BenchmarkNoisy", "ns/op", 9, 12, 10, 11, 13),
+		summary("BenchmarkNew", "ns/op", 1),
+		summary("BenchmarkAllocs", "B/op", 16, 16, 16),
+	}
+	cmps := bench.Compare(old, new, bench.CompareOptions{Threshold: 5, Alpha: 0.05})
+	if len(cmps) != 4 {
+		t.Fatalf("got %d comparisons, want 4", len(cmps))
+	}
+
+	// filesize/op does not vary between runs, so a single sample of each is
+	// enough for the change to be significant.
+	if c := cmps[0]; c.Name != "BenchmarkSize" || !c.Regression || c.Delta != 10 {
+		t.Errorf("got %+v, want a 10%% regression", c)
+	}
+	if c := cmps[1]; c.Name != "BenchmarkSlow" || !c.Regression {
+		t.Errorf("got %+v, want a regression", c)
+	}
+	if c := cmps[2]; c.Name != "BenchmarkNoisy" || c.Significant || c.Regression {
+		t.Errorf("got %+v, want an insignificant change", c)
+	}
+
+	// Any increase from zero, ex. a benchmark that starts to allocate, is a
+	// regression.
+	if c := cmps[3]; c.Name != "BenchmarkAllocs" || !c.Regression || !math.IsInf(c.Delta, 1) {
+		t.Errorf("got %+v, want a regression from zero", c)
+	}
+
+	// A single sample of a metric that varies between runs, ex. with
+	// -count 1, is not enough for any change to be significant.
+	cmps = bench.Compare(
+		[]*bench.Summary{summary("BenchmarkOnce", "ns/op", 100)},
+		[]*bench.Summary{summary("BenchmarkOnce", "ns/op", 108)},
+		bench.CompareOptions{Threshold: 5, Alpha: 0.05})
+	if len(cmps) != 1 || cmps[0].Significant || cmps[0].Regression || cmps[0].P != 1 {
+		t.Errorf("got %+v, want an insignificant change", cmps)
+	}
+
+	// An increase from zero is past any threshold.
+	cmps = bench.Compare(old, new, bench.CompareOptions{Threshold: 200, Alpha: 0.05})
+	for _, c := range cmps {
+		if c.Regression != (c.Name == "BenchmarkAllocs") {
+			t.Errorf("Regression = %v for %s with a threshold of 200%%", c.Regression, c.Name)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+// Summary is the results of the runs of a benchmark, ex. from -count 5.
+type Summary struct {
+
+	// Name is the benchmark's name.
+	Name string
+
+	// Path is the elements of the benchmark's name.
+	Path []string
+
+	// Units are the units of the benchmark's metrics, starting with Iters,
+	// in the order they were first reported.
+	Units []string
+
+	// Samples are the values of each metric, one per run that reported it.
+	Samples map[string][]float64
+}
+
+// Runs returns the number of runs of the benchmark.
+func (s *Summary) Runs() int {
+	return len(s.Samples[Iters])
+}
+
+// Mean returns the mean of the metric with the given unit across the runs
+// that reported it.
+func (s *Summary) Mean(unit string) (float64, bool) {
+	vals := s.Samples[unit]
+	if len(vals) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals)), true
+}
+
+// Summarize groups the results by the name of the benchmark. The summaries
+// are in the order in which each benchmark first ran.
+func Summarize(results []Result) []*Summary {
+	var (
+		sums   []*Summary
+		byName = map[string]*Summary{}
+	)
+	for i := range results {
+		r := &results[i]
+		s, ok := byName[r.Name]
+		if !ok {
+			s = &Summary{
+				Name:    r.Name,
+				Path:    r.Path(),
+				Units:   []string{Iters},
+				Samples: map[string][]float64{},
+			}
+			byName[r.Name] = s
+			sums = append(sums, s)
+		}
+		s.Samples[Iters] = append(s.Samples[Iters], float64(r.Iters))
+		for _, m := range r.Metrics {
+			if _, ok := s.Samples[m.Unit]; !ok {
+				s.Units = append(s.Units, m.Unit)
+			}
+			s.Samples[m.Unit] = append(s.Samples[m.Unit], m.Value)
+		}
+	}
+	return sums
+}
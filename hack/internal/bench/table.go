@@ -0,0 +1,255 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// TableOptions configure how summaries are laid out in tables.
+type TableOptions struct {
+
+	// Group is the number of leading path elements that name a table. The
+	// default, 1, puts each top-level benchmark in its own table.
+	Group int
+
+	// Pivot, if greater than zero, is the index of the path element whose
+	// values become columns, ex. 1 for the list type in
+	// BenchmarkGoBuild/generic/bin/5-types. Pivot must be at least Group.
+	Pivot int
+
+	// Baseline, if not empty, is the value of the pivot element the other
+	// values are compared with. The baseline is the first column for each
+	// metric and every other value gets columns for the increase over it.
+	Baseline string
+
+	// Metrics are the units of the metrics in the table. If empty, every
+	// metric is included in the order it was first reported.
+	Metrics []string
+
+	// Keys name the columns the remaining path elements of a row are split
+	// into. If empty, the elements are joined into a single Name column.
+	// The last column gets any elements left over.
+	Keys []string
+}
+
+// Table is a table of the mean values of benchmarks' metrics.
+type Table struct {
+	Name         string   `json:"name"`
+	KeyColumns   []string `json:"keyColumns"`
+	ValueColumns []string `json:"valueColumns"`
+	Rows         []Row    `json:"rows"`
+}
+
+// Row is a row of a table. A value is nil if there is no benchmark for it.
+type Row struct {
+	Key    []string   `json:"key"`
+	Values []*float64 `json:"values"`
+}
+
+// table is a table under construction.
+type table struct {
+	*Table
+	metrics []string
+	pivots  []string
+	rows    map[string]*row
+}
+
+type row struct {
+	key   []string
+	means map[string]map[string]float64 // pivot -> unit -> mean
+}
+
+// Tables lays the summaries out in tables.
+func Tables(sums []*Summary, opts TableOptions) ([]*Table, error) {
+	if opts.Group <= 0 {
+		opts.Group = 1
+	}
+	if opts.Pivot > 0 && opts.Pivot < opts.Group {
+		return nil, fmt.Errorf("pivot %d is part of the table name", opts.Pivot)
+	}
+	if opts.Baseline != "" && opts.Pivot <= 0 {
+		return nil, fmt.Errorf("a baseline requires a pivot")
+	}
+
+	var (
+		tables []*table
+		byName = map[string]*table{}
+	)
+	for _, s := range sums {
+		need := opts.Group
+		if opts.Pivot >= need {
+			need = opts.Pivot + 1
+		}
+		if len(s.Path) < need {
+			return nil, fmt.Errorf("%s has fewer than %d path elements", s.Name, need)
+		}
+
+		name := strings.Join(s.Path[:opts.Group], "/")
+		t, ok := byName[name]
+		if !ok {
+			t = &table{Table: &Table{Name: name}, rows: map[string]*row{}}
+			byName[name] = t
+			tables = append(tables, t)
+		}
+
+		var pivot string
+		key := append([]string(nil), s.Path[opts.Group:]...)
+		if opts.Pivot > 0 {
+			i := opts.Pivot - opts.Group
+			pivot = key[i]
+			key = append(key[:i], key[i+1:]...)
+		}
+		t.add(s, splitKey(key, len(opts.Keys)), pivot)
+	}
+
+	out := make([]*Table, len(tables))
+	for i, t := range tables {
+		t.finish(opts)
+		out[i] = t.Table
+	}
+	return out, nil
+}
+
+// splitKey splits the elements of a row's key into n columns.
+func splitKey(elems []string, n int) []string {
+	if n <= 1 {
+		return []string{strings.Join(elems, "/")}
+	}
+	key := make([]string, n)
+	for i := 0; i < n && i < len(elems); i++ {
+		key[i] = elems[i]
+	}
+	if len(elems) > n {
+		key[n-1] = strings.Join(elems[n-1:], "/")
+	}
+	return key
+}
+
+func (t *table) add(s *Summary, key []string, pivot string) {
+	k := strings.Join(key, "\x00")
+	r, ok := t.rows[k]
+	if !ok {
+		r = &row{key: key, means: map[string]map[string]float64{}}
+		t.rows[k] = r
+		t.Rows = append(t.Rows, Row{Key: key})
+	}
+	if r.means[pivot] == nil {
+		r.means[pivot] = map[string]float64{}
+	}
+	for _, unit := range s.Units {
+		r.means[pivot][unit], _ = s.Mean(unit)
+		if !contains(t.metrics, unit) {
+			t.metrics = append(t.metrics, unit)
+		}
+	}
+	if !contains(t.pivots, pivot) {
+		t.pivots = append(t.pivots, pivot)
+	}
+}
+
+// finish computes the columns and the values of the rows.
+func (t *table) finish(opts TableOptions) {
+	t.KeyColumns = opts.Keys
+	if len(t.KeyColumns) == 0 {
+		t.KeyColumns = []string{"Name"}
+	}
+	metrics := opts.Metrics
+	if len(metrics) == 0 {
+		metrics = t.metrics
+	}
+	pivots := t.pivots
+	if opts.Baseline != "" {
+		pivots = []string{opts.Baseline}
+		for _, p := range t.pivots {
+			if p != opts.Baseline {
+				pivots = append(pivots, p)
+			}
+		}
+	}
+
+	for _, m := range metrics {
+		for _, p := range pivots {
+			t.ValueColumns = append(t.ValueColumns, column(m, p))
+		}
+		if opts.Baseline == "" {
+			continue
+		}
+		for _, p := range pivots[1:] {
+			t.ValueColumns = append(t.ValueColumns,
+				column("Increase ("+m+")", p), column("Increase (%)", p))
+		}
+	}
+
+	for i := range t.Rows {
+		r := t.rows[strings.Join(t.Rows[i].Key, "\x00")]
+		var vals []*float64
+		for _, m := range metrics {
+			for _, p := range pivots {
+				vals = append(vals, r.mean(p, m))
+			}
+			if opts.Baseline == "" {
+				continue
+			}
+			base := r.mean(opts.Baseline, m)
+			for _, p := range pivots[1:] {
+				v := r.mean(p, m)
+				var inc, pct *float64
+				if base != nil && v != nil {
+					d := *v - *base
+					inc = &d
+					if *base != 0 {
+						pc := d / *base * 100
+						pct = &pc
+					}
+				}
+				vals = append(vals, inc, pct)
+			}
+		}
+		t.Rows[i].Values = vals
+	}
+}
+
+func (r *row) mean(pivot, unit string) *float64 {
+	v, ok := r.means[pivot][unit]
+	if !ok {
+		return nil
+	}
+	// The number of iterations is a count, even when averaged across runs.
+	if unit == Iters {
+		v = math.Round(v)
+	}
+	return &v
+}
+
+func column(metric, pivot string) string {
+	if pivot == "" {
+		return metric
+	}
+	return metric + " - " + pivot
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package archsize attributes the size of a Go package archive, the .a file
+// written by go build for a package that is not a main package, to the parts
+// of the archive.
+//
+// A package archive is an ar archive with two members:
+//
+//   - __.PKGDEF is the export data the compiler reads when it compiles a
+//     package that imports this one. It describes the package's exported
+//     API and includes the bodies of the generic functions and methods, so
+//     they may be instantiated by importers, and of the functions that may
+//     be inlined.
+//
+//   - _go_.o is the object file the linker reads. It has the package's
+//     compiled code and data, including the code stenciled for the GC shapes
+//     the package instantiates its own generic types and functions with,
+//     and their dictionaries.
+//
+// The formats of both members are internal to the Go toolchain and change
+// between Go versions. This package reads the unified export data and the
+// go120ld object file format, with the symbol kinds of Go 1.24 and later,
+// and Open returns an error for an archive built by an older release, ex.
+// Go 1.18, which wrote indexed export data and go118ld object files.
+package archsize
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-generics-the-hard-way/internal/goobj"
+)
+
+// Section is a contiguous part of an archive, or the total of several
+// parts of the same kind.
+type Section struct {
+	Name string `json:"name"`
+
+	// Count is the number of elements or symbols in the section, if it
+	// has any.
+	Count int `json:"count,omitempty"`
+
+	Size int64 `json:"size"`
+}
+
+// Report is the attribution of a package archive's size.
+type Report struct {
+	Size int64 `json:"size"`
+
+	// Header is the first line of the object header, which records the
+	// version of Go, the platform, and the experiments the archive was
+	// built with.
+	Header string `json:"header"`
+
+	// Members are the members of the ar archive.
+	Members []Section `json:"members"`
+
+	Export *Export `json:"export"`
+	Object *Object `json:"object"`
+}
+
+// Open reads the package archive at path and attributes its size.
+func Open(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Read(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ErrFormat is wrapped by the errors for data that is not a package archive
+// this package can read.
+var ErrFormat = goobj.ErrFormat
+
+// formatError returns an error that wraps ErrFormat.
+func formatError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrFormat}, args...)...)
+}
+
+// Read attributes the size of the package archive in data.
+func Read(data []byte) (*Report, error) {
+	members, err := goobj.ReadArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	r := Report{Size: int64(len(data))}
+	for _, m := range members {
+		r.Members = append(r.Members, Section{Name: m.Name, Size: m.Size})
+	}
+
+	pkgdef := goobj.FindMember(members, goobj.ExportMember)
+	if pkgdef == nil {
+		return nil, formatError("no %s member", goobj.ExportMember)
+	}
+	if i := bytes.IndexByte(pkgdef, '\n'); i >= 0 {
+		r.Header = string(pkgdef[:i])
+	}
+	if err := checkVersion(r.Header); err != nil {
+		return nil, err
+	}
+
+	if r.Export, err = readExport(pkgdef); err != nil {
+		return nil, fmt.Errorf("%s: %w", goobj.ExportMember, err)
+	}
+	obj := goobj.FindMember(members, goobj.ObjectMember)
+	if obj == nil {
+		return nil, formatError("no %s member", goobj.ObjectMember)
+	}
+	if r.Object, err = readObject(obj); err != nil {
+		return nil, fmt.Errorf("%s: %w", goobj.ObjectMember, err)
+	}
+	return &r, nil
+}
+
+var goVersion = regexp.MustCompile(` go1\.(\d+)`)
+
+// checkVersion returns an error if the object header is for a release of
+// Go older than the formats this package reads. Development versions are
+// assumed to be recent.
+func checkVersion(header string) error {
+	if !strings.HasPrefix(header, "go object ") {
+		return formatError("invalid object header %q", header)
+	}
+	m := goVersion.FindStringSubmatch(header)
+	if m == nil {
+		return nil
+	}
+	if minor, _ := strconv.Atoi(m[1]); minor < 24 {
+		return formatError("built with go1.%s, need go1.24 or later", m[1])
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archsize_test
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-generics-the-hard-way/internal/archsize"
+	"go-generics-the-hard-way/internal/goobj"
+	"go-generics-the-hard-way/internal/testenv"
+)
+
+func sum(sections []archsize.Section) int64 {
+	var n int64
+	for _, s := range sections {
+		n += s.Size
+	}
+	return n
+}
+
+func TestOpen(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a package archive")
+	}
+	// The archive is built by the same toolchain as the test, and Open
+	// rejects archives older than the formats it reads.
+	testenv.NeedGo(t, 24)
+
+	a := filepath.Join(t.TempDir(), "lists.a")
+	build := exec.Command("go", "build", "-o", a, "./testdata/lists")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s", err, out)
+	}
+
+	r, err := archsize.Open(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(r.Header, "go object ") {
+		t.Errorf("Header = %q", r.Header)
+	}
+	if got := int64(len("!<arch>\n")) + sum(r.Members); got != r.Size {
+		t.Errorf("the members add up to %d bytes, want %d", got, r.Size)
+	}
+	if got := sum(r.Export.Sections); got != r.Export.Size {
+		t.Errorf("the export data sections add up to %d bytes, want %d", got, r.Export.Size)
+	}
+	if got := sum(r.Object.Blocks); got != r.Object.Size {
+		t.Errorf("the object file blocks add up to %d bytes, want %d", got, r.Object.Size)
+	}
+	for _, b := range r.Object.Blocks {
+		if b.Name == "data" && b.Size != sum(r.Object.Kinds) {
+			t.Errorf("the object data kinds add up to %d bytes, want %d", sum(r.Object.Kinds), b.Size)
+		}
+	}
+
+	bodies := map[string]archsize.Body{}
+	for _, b := range r.Export.Bodies {
+		bodies[b.Name] = b
+	}
+	// The bodies of both of List's methods are attributed to List.
+	if b := bodies["List"]; !b.Generic || b.Count != 2 || b.Size == 0 {
+		t.Errorf("List = %+v, want the generic bodies of its 2 methods", b)
+	}
+	if b := bodies["Sum"]; b.Generic || b.Count != 1 || b.Size == 0 {
+		t.Errorf("Sum = %+v, want an inline body", b)
+	}
+	if r.Export.GenericBodies != bodies["List"].Size || r.Export.InlineBodies < bodies["Sum"].Size {
+		t.Errorf("got generic bodies %d and inline bodies %d", r.Export.GenericBodies, r.Export.InlineBodies)
+	}
+
+	var shaped, wrapper bool
+	for _, s := range r.Object.Symbols {
+		switch {
+		case strings.HasSuffix(s.Name, "lists.(*List[go.shape.int]).Add"):
+			shaped = s.Kind == goobj.Text && s.Aux > 0 && strings.HasSuffix(s.Instantiation, "lists.List[go.shape.int]")
+		case strings.HasSuffix(s.Name, "lists.(*List[int]).Add"):
+			wrapper = s.Kind == goobj.Text && strings.HasSuffix(s.Instantiation, "lists.List[int]")
+		}
+	}
+	if !shaped || !wrapper {
+		t.Errorf("got stenciled code %v and a method wrapper %v, want both", shaped, wrapper)
+	}
+	if r.Object.Generic == 0 || r.Object.Generic > r.Object.Size {
+		t.Errorf("got generic object data %d of %d", r.Object.Generic, r.Object.Size)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	const header = "__.PKGDEF       0           0     0     644     "
+	testCases := map[string]string{
+		"not an archive": "\x7fELF",
+		"truncated":      "!<arch>\n__.PKGDEF",
+		"invalid size":   "!<arch>\n" + header + "99999     `\n",
+		"no export data": "!<arch>\n" + header + "26        `\ngo object linux amd64 go1\n",
+		"invalid header": "!<arch>\n" + header + "8         `\n__.PKGDEF",
+	}
+	for name, data := range testCases {
+		if _, err := archsize.Read([]byte(data)); !errors.Is(err, archsize.ErrFormat) {
+			t.Errorf("%s: got %v, want an error that wraps ErrFormat", name, err)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archsize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+)
+
+// Export is the attribution of the size of the export data in __.PKGDEF.
+type Export struct {
+
+	// Size is the size of the member, excluding its ar header.
+	Size int64 `json:"size"`
+
+	// Version is the version of the unified export data format.
+	Version int `json:"version"`
+
+	// Sections are the object header and the markers around the export
+	// data, the index of the elements, the sections of elements, and the
+	// fingerprint, in the order they appear.
+	Sections []Section `json:"sections"`
+
+	// GenericBodies is the size of the bodies of generic functions and
+	// methods. InlineBodies is the size of the bodies of the other
+	// functions and methods, which are exported so importers may inline
+	// them.
+	GenericBodies int64 `json:"genericBodies"`
+	InlineBodies  int64 `json:"inlineBodies"`
+
+	// Bodies are the exported function bodies, from the largest to the
+	// smallest.
+	Bodies []Body `json:"bodies"`
+}
+
+// Body is the exported bodies of a function, or of the methods of a type.
+type Body struct {
+
+	// Name is the function or method, or for the bodies of a generic
+	// type's methods, the type. The package path is omitted from the
+	// names of the package's own declarations.
+	Name string `json:"name"`
+
+	Generic bool `json:"generic"`
+
+	// Count is the number of bodies, including the bodies of function
+	// literals.
+	Count int `json:"count"`
+
+	Size int64 `json:"size"`
+}
+
+// The sections of unified export data, see internal/pkgbits.
+const (
+	sectionString = iota
+	sectionMeta
+	sectionPosBase
+	sectionPkg
+	sectionName
+	sectionType
+	sectionObj
+	sectionObjExt
+	sectionObjDict
+	sectionBody
+	numSections
+)
+
+var sectionNames = [numSections]string{
+	"string", "meta", "posbase", "pkg", "name", "type", "obj", "objext", "objdict", "body",
+}
+
+const (
+	publicRootIdx  = 0
+	privateRootIdx = 1
+
+	objStub = 5
+
+	flagSyncMarkers = 1
+
+	fingerprintSize = 8
+)
+
+// readExport reads the export data in the __.PKGDEF member.
+func readExport(data []byte) (*Export, error) {
+	const begin, end = "\n$$B\n", "\n$$\n"
+	i := bytes.Index(data, []byte(begin))
+	j := bytes.LastIndex(data, []byte(end))
+	if i < 0 || j < i+len(begin) {
+		return nil, formatError("no export data")
+	}
+	u := data[i+len(begin) : j]
+	if len(u) == 0 || u[0] != 'u' {
+		return nil, formatError("export data is not in the unified format")
+	}
+	p, err := newPkgData(u[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	e := Export{
+		Size:    int64(len(data)),
+		Version: int(p.version),
+	}
+	e.Sections = append(e.Sections,
+		Section{Name: "header", Size: int64(len(data) - len(u) + 1)},
+		Section{Name: "index", Count: len(p.elemEnds), Size: int64(len(u) - 1 - len(p.elemData))},
+	)
+	for k := 0; k < numSections; k++ {
+		e.Sections = append(e.Sections, Section{Name: sectionNames[k], Count: p.numElems(k), Size: p.sectionSize(k)})
+	}
+	e.Sections = append(e.Sections, Section{Name: "fingerprint", Size: fingerprintSize})
+
+	if err := p.readBodies(&e); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(e.Bodies, func(i, j int) bool {
+		return e.Bodies[i].Size > e.Bodies[j].Size
+	})
+	return &e, nil
+}
+
+// readBodies attributes the bodies in the export data to the declarations
+// they belong to. The bodies of generic functions and methods are referenced
+// by the extension elements of their declarations, while the bodies of
+// functions that may be inlined are listed by the private root.
+func (p *pkgData) readBodies(e *Export) error {
+	r := p.reader(sectionMeta, publicRootIdx)
+	self := p.pkgPath(r.reloc(sectionPkg))
+
+	seen := map[int]bool{}
+	for i, n := 0, p.numElems(sectionName); i < n; i++ {
+		r := p.reader(sectionName, i)
+		path := p.pkgPath(r.reloc(sectionPkg))
+		name := r.string()
+		code := r.uvarint()
+		if r.err != nil {
+			return r.err
+		}
+		if code == objStub {
+			continue
+		}
+
+		d := p.reader(sectionObjDict, i)
+		implicits := d.uvarint()
+		var rtparams int
+		if p.version >= 4 {
+			rtparams = d.uvarint()
+		}
+		tparams := d.uvarint()
+		if d.err != nil {
+			return d.err
+		}
+		if implicits+rtparams+tparams == 0 {
+			continue
+		}
+
+		ext := p.reader(sectionObjExt, i)
+		if ext.err != nil {
+			return ext.err
+		}
+		b := Body{Name: qualify(self, path, name), Generic: true}
+		for _, rel := range ext.relocs {
+			if rel.k == sectionBody {
+				p.walkBody(rel.idx, seen, &b)
+			}
+		}
+		if b.Count > 0 {
+			e.GenericBodies += b.Size
+			e.Bodies = append(e.Bodies, b)
+		}
+	}
+
+	r = p.reader(sectionMeta, privateRootIdx)
+	r.bool() // whether the package has an init task
+	for i, n := 0, r.uvarint(); i < n && r.err == nil; i++ {
+		path := r.string()
+		name := r.string()
+		b := Body{Name: qualify(self, path, name)}
+		if idx := r.reloc(sectionBody); r.err == nil {
+			p.walkBody(idx, seen, &b)
+		}
+		if b.Count > 0 {
+			e.InlineBodies += b.Size
+			e.Bodies = append(e.Bodies, b)
+		}
+	}
+	return r.err
+}
+
+// walkBody adds the size of a body, and of the bodies it references, to b.
+// A body that has already been seen is only counted once.
+func (p *pkgData) walkBody(idx int, seen map[int]bool, b *Body) {
+	if seen[idx] || idx < 0 || idx >= p.numElems(sectionBody) {
+		return
+	}
+	seen[idx] = true
+	b.Count++
+	b.Size += int64(len(p.elem(sectionBody, idx)))
+	for _, rel := range p.reader(sectionBody, idx).relocs {
+		if rel.k == sectionBody {
+			p.walkBody(rel.idx, seen, b)
+		}
+	}
+}
+
+// qualify returns the name of a declaration in the package with path.
+func qualify(self, path, name string) string {
+	if path == self || path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// pkgData is unified export data, a table of elements in sections. Each
+// element starts with a table of the elements it references, followed by a
+// stream of varints, and references to strings and other elements.
+type pkgData struct {
+	version      uint32
+	elemEndsEnds [numSections]uint32
+	elemEnds     []uint32
+	elemData     []byte
+}
+
+func newPkgData(u []byte) (*pkgData, error) {
+	p := pkgData{}
+	rd := bytes.NewReader(u)
+	if err := binary.Read(rd, binary.LittleEndian, &p.version); err != nil {
+		return nil, formatError("truncated export data")
+	}
+	if p.version >= 1 {
+		var flags uint32
+		if err := binary.Read(rd, binary.LittleEndian, &flags); err != nil {
+			return nil, formatError("truncated export data")
+		}
+		if flags&flagSyncMarkers != 0 {
+			return nil, formatError("export data with sync markers is not supported")
+		}
+	}
+	if err := binary.Read(rd, binary.LittleEndian, p.elemEndsEnds[:]); err != nil {
+		return nil, formatError("truncated export data")
+	}
+	n := p.elemEndsEnds[numSections-1]
+	if int64(n)*4 > int64(rd.Len()) {
+		return nil, formatError("truncated export data")
+	}
+	p.elemEnds = make([]uint32, n)
+	if err := binary.Read(rd, binary.LittleEndian, p.elemEnds); err != nil {
+		return nil, formatError("truncated export data")
+	}
+	p.elemData = u[len(u)-rd.Len():]
+
+	var prevEnds, prevEnd uint32
+	for _, ends := range p.elemEndsEnds {
+		if ends < prevEnds {
+			return nil, formatError("invalid export data index")
+		}
+		prevEnds = ends
+	}
+	for _, end := range p.elemEnds {
+		if end < prevEnd {
+			return nil, formatError("invalid export data index")
+		}
+		prevEnd = end
+	}
+	if int64(prevEnd)+fingerprintSize != int64(len(p.elemData)) {
+		return nil, formatError("invalid export data size")
+	}
+	return &p, nil
+}
+
+// absIdx returns the index of the i'th element of section k in elemEnds.
+func (p *pkgData) absIdx(k, i int) int {
+	if k > 0 {
+		i += int(p.elemEndsEnds[k-1])
+	}
+	return i
+}
+
+func (p *pkgData) numElems(k int) int {
+	return p.absIdx(k+1, 0) - p.absIdx(k, 0)
+}
+
+// end returns the offset in elemData of the end of the element at absolute
+// index i, or of the start of the first element if i is -1.
+func (p *pkgData) end(i int) uint32 {
+	if i < 0 {
+		return 0
+	}
+	return p.elemEnds[i]
+}
+
+func (p *pkgData) sectionSize(k int) int64 {
+	return int64(p.end(p.absIdx(k+1, 0)-1) - p.end(p.absIdx(k, 0)-1))
+}
+
+// elem returns the data of the i'th element of section k, or nil if there
+// is no such element.
+func (p *pkgData) elem(k, i int) []byte {
+	if i < 0 || i >= p.numElems(k) {
+		return nil
+	}
+	abs := p.absIdx(k, i)
+	return p.elemData[p.end(abs-1):p.end(abs)]
+}
+
+// pkgPath returns the path of the package at index i of the pkg section.
+func (p *pkgData) pkgPath(i int) string {
+	return p.reader(sectionPkg, i).string()
+}
+
+type reloc struct {
+	k, idx int
+}
+
+// elemReader reads an element. Errors are sticky: after the first error,
+// reads return zero values and the error is in err.
+type elemReader struct {
+	p      *pkgData
+	data   []byte
+	relocs []reloc
+	err    error
+}
+
+// reader returns a reader for the i'th element of section k, positioned
+// after its table of references.
+func (p *pkgData) reader(k, i int) *elemReader {
+	r := elemReader{p: p}
+	if i < 0 || i >= p.numElems(k) {
+		r.err = formatError("no element %d in section %s", i, sectionNames[k])
+		return &r
+	}
+	r.data = p.elem(k, i)
+	n := r.uvarint()
+	for j := 0; j < n && r.err == nil; j++ {
+		r.relocs = append(r.relocs, reloc{k: r.uvarint(), idx: r.uvarint()})
+	}
+	return &r
+}
+
+func (r *elemReader) uvarint() int {
+	if r.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(r.data)
+	if n <= 0 || x > 1<<31 {
+		r.err = formatError("invalid varint in export data")
+		return 0
+	}
+	r.data = r.data[n:]
+	return int(x)
+}
+
+func (r *elemReader) bool() bool {
+	if r.err != nil {
+		return false
+	}
+	if len(r.data) == 0 || r.data[0] > 1 {
+		r.err = formatError("invalid bool in export data")
+		return false
+	}
+	b := r.data[0] == 1
+	r.data = r.data[1:]
+	return b
+}
+
+// reloc reads a reference to an element of section k and returns the index
+// of the element in the section.
+func (r *elemReader) reloc(k int) int {
+	i := r.uvarint()
+	if r.err != nil {
+		return -1
+	}
+	if i >= len(r.relocs) || r.relocs[i].k != k {
+		r.err = formatError("invalid reference in export data")
+		return -1
+	}
+	return r.relocs[i].idx
+}
+
+func (r *elemReader) string() string {
+	i := r.reloc(sectionString)
+	if r.err != nil {
+		return ""
+	}
+	s := r.p.elem(sectionString, i)
+	if s == nil {
+		r.err = formatError("no element %d in section string", i)
+	}
+	return string(s)
+}
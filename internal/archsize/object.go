@@ -0,0 +1,143 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archsize
+
+import (
+	"sort"
+	"strings"
+
+	"go-generics-the-hard-way/internal/binsize"
+	"go-generics-the-hard-way/internal/goobj"
+)
+
+// Symbol is a symbol in an object file.
+type Symbol struct {
+	Name string     `json:"name"`
+	Kind goobj.Kind `json:"kind"`
+
+	// Size is the size of the symbol's data.
+	Size int64 `json:"size"`
+
+	// Aux is the size of the data of the symbol's auxiliary symbols, such
+	// as the pc-value tables and DWARF of a function.
+	Aux int64 `json:"aux"`
+
+	// Relocs is the size of the relocations of the symbol and of its
+	// auxiliary symbols.
+	Relocs int64 `json:"relocs"`
+
+	// Instantiation is the generic type or function the symbol belongs to,
+	// if any, ex. pkg.List[go.shape.int] or pkg.List[int].
+	Instantiation string `json:"instantiation,omitempty"`
+}
+
+// Object is the attribution of the size of the object file in _go_.o.
+type Object struct {
+
+	// Size is the size of the member, excluding its ar header.
+	Size int64 `json:"size"`
+
+	// Blocks are the object header, the string table, and the blocks of
+	// the object file, in the order they appear.
+	Blocks []Section `json:"blocks"`
+
+	// Kinds are the sizes of the symbols' data by kind.
+	Kinds []Section `json:"kinds"`
+
+	// Generic is the size of the data and relocations of the symbols that
+	// belong to generic instantiations, including their auxiliary symbols.
+	Generic int64 `json:"generic"`
+
+	// Symbols are the symbols that are not auxiliary symbols of another
+	// symbol, from the largest to the smallest.
+	Symbols []Symbol `json:"-"`
+}
+
+// readObject reads the object file in the _go_.o member.
+func readObject(data []byte) (*Object, error) {
+	f, err := goobj.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	o := Object{Size: int64(len(data))}
+	for _, b := range f.Blocks {
+		o.Blocks = append(o.Blocks, Section{Name: b.Name, Count: b.Count, Size: b.Size})
+	}
+
+	kinds := map[goobj.Kind]*Section{}
+	index := map[int]int{}
+	for i, s := range f.Syms[:f.NDef] {
+		ks, ok := kinds[s.Kind]
+		if !ok {
+			ks = &Section{Name: string(s.Kind)}
+			kinds[s.Kind] = ks
+		}
+		ks.Count++
+		ks.Size += s.Size
+
+		if s.Owner >= 0 {
+			continue
+		}
+		sym := Symbol{
+			Name:   s.Name,
+			Kind:   s.Kind,
+			Size:   s.Size,
+			Relocs: int64(len(s.Relocs)) * goobj.RelocSize,
+		}
+		sym.Instantiation = instantiation(sym)
+		index[i] = len(o.Symbols)
+		o.Symbols = append(o.Symbols, sym)
+	}
+	for i, s := range f.Syms[:f.NDef] {
+		if s.Owner < 0 {
+			continue
+		}
+		if j, ok := index[f.Root(i)]; ok {
+			o.Symbols[j].Aux += s.Size
+			o.Symbols[j].Relocs += int64(len(s.Relocs)) * goobj.RelocSize
+		}
+	}
+	for _, s := range o.Symbols {
+		if s.Instantiation != "" {
+			o.Generic += s.Size + s.Aux + s.Relocs
+		}
+	}
+
+	for _, k := range goobj.Kinds {
+		if s, ok := kinds[k]; ok {
+			o.Kinds = append(o.Kinds, *s)
+		}
+	}
+	sort.SliceStable(o.Symbols, func(i, j int) bool {
+		return o.Symbols[i].Size+o.Symbols[i].Aux > o.Symbols[j].Size+o.Symbols[j].Aux
+	})
+	return &o, nil
+}
+
+// instantiation returns the generic instantiation a symbol belongs to, if
+// any. The names of DWARF symbols are the names of the symbols they describe
+// with a prefix, ex. go:info.pkg.(*List[go.shape.int]).Add$abstract.
+func instantiation(s Symbol) string {
+	name := s.Name
+	if s.Kind == goobj.DWARF && strings.HasPrefix(name, "go:") {
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			name = name[i+1:]
+		}
+	}
+	in, _, _ := binsize.ParseInstantiation(name)
+	return in
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lists has a generic type that it instantiates itself, and a
+// function that importers may inline.
+package lists
+
+type List[T any] []T
+
+func (l *List[T]) Add(val T) {
+	*l = append(*l, val)
+}
+
+func (l List[T]) Each(fn func(T)) {
+	for _, v := range l {
+		fn(v)
+	}
+}
+
+var Ints List[int]
+
+func Sum(vals []int) int {
+	var sum int
+	for _, v := range vals {
+		sum += v
+	}
+	return sum
+}
@@ -0,0 +1,407 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package binsize attributes the size of a Go binary's text and read-only
+// data to its symbols, and groups the symbols of generic instantiations by
+// the type or function they instantiate.
+//
+// The Go compiler stencils one copy of a generic function's code per GC
+// shape, ex. pkg.(*List[go.shape.int]).Add, and passes the code a dictionary
+// for each concrete instantiation, ex. pkg..dict.List[int]. Grouping the
+// symbols by instantiation shows what each additional type argument adds to
+// a binary: a new shape adds code, while a type argument that shares a shape
+// with an existing one only adds a dictionary and type descriptors.
+package binsize
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of bytes a symbol occupies.
+type Kind string
+
+const (
+	// Text is executable code.
+	Text Kind = "text"
+
+	// RoData is read-only data, such as dictionaries and type descriptors.
+	RoData Kind = "rodata"
+)
+
+// Symbol is a symbol in a binary.
+type Symbol struct {
+	Name    string `json:"name"`
+	Section string `json:"section"`
+	Kind    Kind   `json:"kind"`
+	Size    uint64 `json:"size"`
+
+	// Instantiation is the generic type or function the symbol belongs to,
+	// if any, ex. pkg.List[go.shape.int] or pkg.List[int].
+	Instantiation string `json:"instantiation,omitempty"`
+}
+
+// Instantiation is the symbols of a generic type or function instantiated
+// with a list of type arguments.
+type Instantiation struct {
+
+	// Name is the instantiated type or function, ex. pkg.List[go.shape.int].
+	Name string `json:"name"`
+
+	// Generic is the generic type or function, ex. pkg.List.
+	Generic string `json:"generic"`
+
+	// Shaped is whether the type arguments are GC shapes, i.e. whether
+	// this is stenciled code rather than a dictionary for a concrete
+	// instantiation.
+	Shaped bool `json:"shaped"`
+
+	// Text and RoData are the total sizes of the symbols by kind.
+	Text   uint64 `json:"text"`
+	RoData uint64 `json:"rodata"`
+
+	Symbols []Symbol `json:"symbols"`
+}
+
+// Size returns the total size of the instantiation's symbols.
+func (in *Instantiation) Size() uint64 {
+	return in.Text + in.RoData
+}
+
+// Report is the attribution of a binary's size to its symbols.
+type Report struct {
+
+	// Text and RoData are the sizes of the binary's executable and
+	// read-only data sections.
+	Text   uint64 `json:"text"`
+	RoData uint64 `json:"rodata"`
+
+	// GenericText and GenericRoData are the sizes of the symbols that
+	// belong to generic instantiations.
+	GenericText   uint64 `json:"genericText"`
+	GenericRoData uint64 `json:"genericRodata"`
+
+	// Symbols are the binary's text and read-only data symbols, from the
+	// largest to the smallest.
+	Symbols []Symbol `json:"-"`
+
+	// Instantiations are the generic instantiations, from the largest to
+	// the smallest.
+	Instantiations []*Instantiation `json:"instantiations"`
+}
+
+// Open reads the ELF binary at path and attributes its size.
+func Open(path string) (*Report, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Read attributes the size of an ELF binary.
+//
+// The sizes of functions are read from the pclntab with debug/gosym, so they
+// are available even if the binary was stripped, and the sizes of read-only
+// data are read from the symbol table, if there is one.
+func Read(f *elf.File) (*Report, error) {
+	var r Report
+	for _, s := range f.Sections {
+		if s.Flags&elf.SHF_ALLOC == 0 || s.Type == elf.SHT_NOBITS {
+			continue
+		}
+		switch kind := sectionKind(s); kind {
+		case Text:
+			r.Text += s.Size
+		case RoData:
+			r.RoData += s.Size
+		}
+	}
+
+	funcs, err := readFuncs(f)
+	if err != nil {
+		return nil, err
+	}
+	r.Symbols = append(r.Symbols, funcs...)
+
+	syms, err := f.Symbols()
+	if err != nil && err != elf.ErrNoSymbols {
+		return nil, err
+	}
+	for _, s := range syms {
+		if int(s.Section) <= 0 || int(s.Section) >= len(f.Sections) || s.Size == 0 {
+			continue
+		}
+		sect := f.Sections[s.Section]
+		if sectionKind(sect) != RoData {
+			continue
+		}
+		r.Symbols = append(r.Symbols, Symbol{Name: s.Name, Section: sect.Name, Kind: RoData, Size: s.Size})
+	}
+
+	byName := map[string]*Instantiation{}
+	for i := range r.Symbols {
+		s := &r.Symbols[i]
+		name, generic, ok := ParseInstantiation(s.Name)
+		if !ok {
+			continue
+		}
+		s.Instantiation = name
+		in, ok := byName[name]
+		if !ok {
+			in = &Instantiation{
+				Name:    name,
+				Generic: generic,
+				Shaped:  strings.Contains(name, "go.shape."),
+			}
+			byName[name] = in
+			r.Instantiations = append(r.Instantiations, in)
+		}
+		in.Symbols = append(in.Symbols, *s)
+		if s.Kind == Text {
+			in.Text += s.Size
+			r.GenericText += s.Size
+		} else {
+			in.RoData += s.Size
+			r.GenericRoData += s.Size
+		}
+	}
+
+	sort.SliceStable(r.Symbols, func(i, j int) bool {
+		return r.Symbols[i].Size > r.Symbols[j].Size
+	})
+	sort.SliceStable(r.Instantiations, func(i, j int) bool {
+		a, b := r.Instantiations[i], r.Instantiations[j]
+		if a.Size() != b.Size() {
+			return a.Size() > b.Size()
+		}
+		return a.Name < b.Name
+	})
+	for _, in := range r.Instantiations {
+		sort.SliceStable(in.Symbols, func(i, j int) bool {
+			return in.Symbols[i].Size > in.Symbols[j].Size
+		})
+	}
+	return &r, nil
+}
+
+// sectionKind returns the kind of bytes in an allocated section, or an empty
+// Kind if the section is writable.
+func sectionKind(s *elf.Section) Kind {
+	switch {
+	case s.Flags&elf.SHF_EXECINSTR != 0:
+		return Text
+	case s.Flags&elf.SHF_WRITE == 0:
+		return RoData
+	}
+	return ""
+}
+
+// readFuncs reads the functions from the binary's pclntab.
+func readFuncs(f *elf.File) ([]Symbol, error) {
+	text := f.Section(".text")
+	pclntab := f.Section(".gopclntab")
+	if text == nil || pclntab == nil {
+		return nil, fmt.Errorf("not a Go binary, there is no .text or .gopclntab section")
+	}
+	data, err := pclntab.Data()
+	if err != nil {
+		return nil, err
+	}
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, text.Addr))
+	if err != nil {
+		return nil, err
+	}
+	funcs := make([]Symbol, 0, len(table.Funcs))
+	for _, fn := range table.Funcs {
+		funcs = append(funcs, Symbol{Name: fn.Name, Section: text.Name, Kind: Text, Size: fn.End - fn.Entry})
+	}
+	return funcs, nil
+}
+
+// ParseInstantiation parses the name of a symbol and, if the symbol belongs
+// to a generic instantiation, returns the instantiation and the generic type
+// or function, ex:
+//
+//	pkg.(*List[go.shape.int]).Add -> pkg.List[go.shape.int], pkg.List
+//	pkg..dict.List[int]           -> pkg.List[int], pkg.List
+//	type:*pkg.List[int]           -> pkg.List[int], pkg.List
+//	pkg.Map[go.shape.int,string]  -> pkg.Map[go.shape.int,string], pkg.Map
+//
+// It also accepts the names written by Go 1.18 and 1.19, which number the
+// shapes by the index of their type parameter and name the dictionaries of
+// methods after the method, ex:
+//
+//	pkg.(*List[go.shape.int_0]).Add -> pkg.List[go.shape.int], pkg.List
+//	pkg..dict.(*List[int]).Add      -> pkg.List[int], pkg.List
+func ParseInstantiation(sym string) (name, generic string, ok bool) {
+	// The type arguments start at the first bracket that follows an
+	// identifier, which skips array and slice types such as [2]pkg.T.
+	open := -1
+	for i := 1; i < len(sym); i++ {
+		if sym[i] == '[' && isIdent(sym[i-1]) {
+			open = i
+			break
+		}
+	}
+	if open < 0 {
+		return "", "", false
+	}
+	depth, end := 0, -1
+	for i := open; i < len(sym) && end < 0; i++ {
+		switch sym[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return "", "", false
+	}
+
+	start := open
+	for start > 0 && isIdent(sym[start-1]) {
+		start--
+	}
+	qual := sym[:start]
+	for _, p := range []string{"type:", "type.", "go:", "go."} {
+		qual = strings.TrimPrefix(qual, p)
+	}
+	for _, s := range []string{".(*", ".("} {
+		if strings.HasSuffix(qual, s) {
+			qual = qual[:len(qual)-len(s)] + "."
+			break
+		}
+	}
+	if strings.HasSuffix(qual, "..dict.") {
+		qual = qual[:len(qual)-len("..dict.")] + "."
+	}
+	// Drop the pointer, slice, and array types of type descriptors, ex.
+	// type:[]*pkg.List[int].
+	if i := strings.LastIndexAny(qual, "]*"); i >= 0 {
+		qual = qual[i+1:]
+	}
+	generic = qual + sym[start:open]
+	return generic + trimShapeIndexes(sym[open:end+1]), generic, true
+}
+
+// trimShapeIndexes removes the type parameter index Go 1.18 and 1.19 append
+// to the name of a shape, ex. go.shape.int for go.shape.int_0, from a list of
+// type arguments.
+func trimShapeIndexes(targs string) string {
+	const prefix = "go.shape."
+	if !strings.Contains(targs, prefix) {
+		return targs
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(targs, prefix)
+		if i < 0 {
+			b.WriteString(targs)
+			return b.String()
+		}
+		// The shape ends at the comma or bracket that ends its type
+		// argument, ex. go.shape.[]int_0 or go.shape.map[string]int_1.
+		depth, end := 0, len(targs)
+		for j := i; j < len(targs) && end == len(targs); j++ {
+			switch targs[j] {
+			case '[':
+				depth++
+			case ']':
+				if depth--; depth < 0 {
+					end = j
+				}
+			case ',':
+				if depth == 0 {
+					end = j
+				}
+			}
+		}
+		shape := targs[:end]
+		if u := strings.LastIndexByte(shape, '_'); u > i+len(prefix) && isDigits(shape[u+1:]) {
+			shape = shape[:u]
+		}
+		b.WriteString(shape)
+		targs = targs[end:]
+	}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// TrimPackages removes the package qualifiers from the identifiers in a
+// name, except for go.shape, ex. List[go.shape.int] for
+// example.com/pkg.List[go.shape.int]. It is used to compare instantiations
+// across packages.
+func TrimPackages(name string) string {
+	var b strings.Builder
+	for len(name) > 0 {
+		i := strings.IndexAny(name, "[]*(), ;{}")
+		if i < 0 {
+			i = len(name)
+		}
+		b.WriteString(trimPackage(name[:i]))
+		if i < len(name) {
+			b.WriteByte(name[i])
+			i++
+		}
+		name = name[i:]
+	}
+	return b.String()
+}
+
+func trimPackage(tok string) string {
+	const shape = "go.shape."
+	if strings.HasPrefix(tok, shape) {
+		return shape + trimPackage(tok[len(shape):])
+	}
+	// The package name is the last element of the path, and may itself
+	// contain dots, ex. gopkg.in/yaml.v3.Node.
+	slash := strings.LastIndexByte(tok, '/')
+	dot := strings.LastIndexByte(tok, '.')
+	if dot <= 0 || dot < slash {
+		return tok
+	}
+	if slash < 0 {
+		// A single element path such as pkg, or a method such as .Add.
+		return tok[strings.IndexByte(tok, '.')+1:]
+	}
+	return tok[dot+1:]
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binsize_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"go-generics-the-hard-way/internal/binsize"
+)
+
+func TestParseInstantiation(t *testing.T) {
+	testCases := []struct {
+		sym     string
+		name    string
+		generic string
+	}{
+		{sym: "pkg.(*List[go.shape.int]).Add", name: "pkg.List[go.shape.int]", generic: "pkg.List"},
+		{sym: "pkg.List[go.shape.int].Len", name: "pkg.List[go.shape.int]", generic: "pkg.List"},
+		{sym: "example.com/a/pkg..dict.List[int]", name: "example.com/a/pkg.List[int]", generic: "example.com/a/pkg.List"},
+		{sym: "type:*pkg.List[int]", name: "pkg.List[int]", generic: "pkg.List"},
+		{sym: "pkg.Map[go.shape.int,[2]string].func1", name: "pkg.Map[go.shape.int,[2]string]", generic: "pkg.Map"},
+		{sym: "type:[]pkg.Set[map[string][]int]", name: "pkg.Set[map[string][]int]", generic: "pkg.Set"},
+		{sym: "pkg.(*List[go.shape.int_0]).Add", name: "pkg.List[go.shape.int]", generic: "pkg.List"},
+		{sym: "pkg.Map[go.shape.[]int_0,go.shape.string_1]", name: "pkg.Map[go.shape.[]int,go.shape.string]", generic: "pkg.Map"},
+		{sym: "pkg.Set[go.shape.struct { F_1 int }_0]", name: "pkg.Set[go.shape.struct { F_1 int }]", generic: "pkg.Set"},
+		{sym: "pkg..dict.(*List[int]).Add", name: "pkg.List[int]", generic: "pkg.List"},
+		{sym: "pkg..dict.List[int].Len", name: "pkg.List[int]", generic: "pkg.List"},
+		{sym: "runtime.main"},
+		{sym: "type:[4]uint8"},
+		{sym: "pkg.Broken[int"},
+	}
+	for _, tc := range testCases {
+		name, generic, ok := binsize.ParseInstantiation(tc.sym)
+		if name != tc.name || generic != tc.generic || ok != (tc.name != "") {
+			t.Errorf("ParseInstantiation(%q) = %q, %q, %v, want %q, %q", tc.sym, name, generic, ok, tc.name, tc.generic)
+		}
+	}
+}
+
+func TestTrimPackages(t *testing.T) {
+	testCases := map[string]string{
+		"example.com/a/pkg.List[go.shape.int]":     "List[go.shape.int]",
+		"pkg.Map[example.com/b.ID,*pkg.T]":         "Map[ID,*T]",
+		"gopkg.in/yaml.v3.Decoder[go.shape.[]int]": "Decoder[go.shape.[]int]",
+		"List[int]": "List[int]",
+	}
+	for in, want := range testCases {
+		if got := binsize.TrimPackages(in); got != want {
+			t.Errorf("TrimPackages(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("binaries are only ELF on Linux")
+	}
+	if testing.Short() {
+		t.Skip("builds a binary")
+	}
+	bin := filepath.Join(t.TempDir(), "lists")
+	build := exec.Command("go", "build", "-gcflags=-l", "-o", bin, "./testdata/lists")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s", err, out)
+	}
+
+	r, err := binsize.Open(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]*binsize.Instantiation{}
+	for _, in := range r.Instantiations {
+		byName[in.Name] = in
+	}
+
+	// ID shares the code stenciled for string, and only adds a dictionary.
+	for _, name := range []string{"main.List[go.shape.int]", "main.List[go.shape.string]"} {
+		in := byName[name]
+		if in == nil || !in.Shaped || in.Text == 0 || in.Generic != "main.List" {
+			t.Errorf("%s = %+v, want stenciled code", name, in)
+		}
+	}
+	if in := byName["main.List[go.shape.main.ID]"]; in != nil {
+		t.Errorf("got code stenciled for ID: %+v", in)
+	}
+	for _, name := range []string{"main.List[int]", "main.List[string]", "main.List[main.ID]"} {
+		in := byName[name]
+		if in == nil || in.Shaped || in.RoData == 0 || in.Text != 0 {
+			t.Errorf("%s = %+v, want a dictionary", name, in)
+		}
+	}
+	if r.GenericText == 0 || r.GenericText > r.Text || r.GenericRoData > r.RoData {
+		t.Errorf("got generic text %d of %d and rodata %d of %d",
+			r.GenericText, r.Text, r.GenericRoData, r.RoData)
+	}
+}
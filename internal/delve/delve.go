@@ -0,0 +1,361 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package delve drives the Go debugger, Delve, through the JSON-RPC API of a
+// headless dlv server, so tests can inspect a program the way the
+// "Internals" chapter does by hand.
+//
+// The package speaks the API with net/rpc/jsonrpc rather than importing
+// Delve's client, and only mirrors the fields of Delve's types that the
+// tests use. The dlv command must be installed, ex. with:
+//
+//	go install github.com/go-delve/delve/cmd/dlv@latest
+package delve
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotInstalled is returned by Exec if the dlv command is not installed.
+var ErrNotInstalled = errors.New("delve: dlv is not installed")
+
+// State is the state of the debugged process after a command.
+type State struct {
+	Running       bool
+	Exited        bool    `json:"exited"`
+	ExitStatus    int     `json:"exitStatus"`
+	CurrentThread *Thread `json:"currentThread,omitempty"`
+}
+
+// Thread is a thread of the debugged process.
+type Thread struct {
+	PC         uint64      `json:"pc"`
+	File       string      `json:"file"`
+	Line       int         `json:"line"`
+	Function   *Function   `json:"function,omitempty"`
+	Breakpoint *Breakpoint `json:"breakPoint,omitempty"`
+}
+
+// Function is a function of the debugged program.
+type Function struct {
+	Name string `json:"name"`
+}
+
+// Breakpoint is a breakpoint, which may stop the process at more than one
+// address, ex. at each instantiation of a generic function.
+type Breakpoint struct {
+	ID           int      `json:"id"`
+	FunctionName string   `json:"functionName,omitempty"`
+	File         string   `json:"file"`
+	Line         int      `json:"line"`
+	Addrs        []uint64 `json:"addrs"`
+}
+
+// Variable is a variable, or an element or field of one, in the scope of a
+// stack frame.
+type Variable struct {
+	Name string `json:"name"`
+
+	// Type is the variable's type. Delve resolves the GC shapes of the
+	// type parameters in stenciled code to the concrete type arguments
+	// with the dictionary the code is passed, ex. main.List[int] rather
+	// than main.List[go.shape.int].
+	Type     string `json:"type"`
+	RealType string `json:"realType"`
+
+	Kind       reflect.Kind `json:"kind"`
+	Value      string       `json:"value"`
+	Len        int64        `json:"len"`
+	Cap        int64        `json:"cap"`
+	Children   []Variable   `json:"children"`
+	Unreadable string       `json:"unreadable"`
+}
+
+// loadConfig is how much of a variable Delve reads.
+type loadConfig struct {
+	FollowPointers     bool
+	MaxVariableRecurse int
+	MaxStringLen       int
+	MaxArrayValues     int
+	MaxStructFields    int
+}
+
+var defaultLoadConfig = loadConfig{
+	FollowPointers:     true,
+	MaxVariableRecurse: 1,
+	MaxStringLen:       64,
+	MaxArrayValues:     64,
+	MaxStructFields:    -1,
+}
+
+// evalScope is the stack frame of the current goroutine, counting from the
+// innermost frame, in which to read variables.
+type evalScope struct {
+	GoroutineID  int64
+	Frame        int
+	DeferredCall int
+}
+
+// Client is a client for a headless dlv server that debugs one process.
+type Client struct {
+	cmd *exec.Cmd
+	rpc *rpc.Client
+
+	mu     sync.Mutex
+	output bytes.Buffer
+	done   chan struct{}
+}
+
+// Exec starts a headless dlv server that debugs the binary at path with the
+// arguments args, and connects to it. The process is stopped before its
+// first instruction.
+func Exec(path string, args ...string) (*Client, error) {
+	dlv, err := exec.LookPath("dlv")
+	if err != nil {
+		return nil, ErrNotInstalled
+	}
+	cmd := exec.Command(dlv, append([]string{
+		"exec", "--headless", "--api-version=2", "--listen=127.0.0.1:0",
+		// The tests are run with each Go release, which may be newer than
+		// the releases the installed Delve knows about.
+		"--check-go-version=false",
+		path, "--",
+	}, args...)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	// The server prints the address it listens at, followed by the output
+	// of the debugged process.
+	c := &Client{cmd: cmd, done: make(chan struct{})}
+	addrc := make(chan string, 1)
+	go func() {
+		defer close(c.done)
+		r := bufio.NewReader(stdout)
+		for {
+			line, err := r.ReadString('\n')
+			if addr, ok := listenAddr(line); ok {
+				addrc <- addr
+				break
+			}
+			if err != nil {
+				close(addrc)
+				return
+			}
+		}
+		io.Copy(outputWriter{c}, r)
+	}()
+
+	var addr string
+	select {
+	case a, ok := <-addrc:
+		if !ok {
+			cmd.Wait()
+			return nil, fmt.Errorf("delve: dlv exited: %s", strings.TrimSpace(stderr.String()))
+		}
+		addr = a
+	case <-time.After(time.Minute):
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, errors.New("delve: timed out waiting for dlv to listen")
+	}
+	if c.rpc, err = jsonrpc.Dial("tcp", addr); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+	return c, nil
+}
+
+// outputWriter appends to the client's output.
+type outputWriter struct {
+	c *Client
+}
+
+func (w outputWriter) Write(p []byte) (int, error) {
+	w.c.mu.Lock()
+	defer w.c.mu.Unlock()
+	return w.c.output.Write(p)
+}
+
+// listenAddr parses the message in which the server prints its address.
+func listenAddr(line string) (string, bool) {
+	const msg = "API server listening at: "
+	i := strings.Index(line, msg)
+	if i < 0 {
+		return "", false
+	}
+	return strings.TrimSpace(line[i+len(msg):]), true
+}
+
+// Output returns what the debugged process has written to its standard
+// output so far.
+func (c *Client) Output() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.output.String()
+}
+
+// Close kills the debugged process and stops the server.
+func (c *Client) Close() error {
+	err := c.call("Detach", struct{ Kill bool }{true}, &struct{}{})
+	if cerr := c.rpc.Close(); err == nil {
+		err = cerr
+	}
+	select {
+	case <-c.done:
+	case <-time.After(10 * time.Second):
+		c.cmd.Process.Kill()
+	}
+	c.cmd.Wait()
+	return err
+}
+
+func (c *Client) call(method string, args, reply interface{}) error {
+	if err := c.rpc.Call("RPCServer."+method, args, reply); err != nil {
+		return fmt.Errorf("delve: %s: %w", method, err)
+	}
+	return nil
+}
+
+// Continue resumes the process until it hits a breakpoint or exits.
+func (c *Client) Continue() (*State, error) {
+	var out struct{ State State }
+	if err := c.call("Command", struct {
+		Name string `json:"name"`
+	}{"continue"}, &out); err != nil {
+		return nil, err
+	}
+	return &out.State, nil
+}
+
+// CreateBreakpoint sets a breakpoint at the start of a function. For a
+// generic function, ex. main.printLen, Delve sets it at every function
+// stenciled for the generic function.
+func (c *Client) CreateBreakpoint(function string) (*Breakpoint, error) {
+	var out struct{ Breakpoint Breakpoint }
+	if err := c.call("CreateBreakpoint", struct{ Breakpoint Breakpoint }{Breakpoint{FunctionName: function}}, &out); err != nil {
+		return nil, err
+	}
+	return &out.Breakpoint, nil
+}
+
+// CreateBreakpointAt sets a breakpoint at each of the provided addresses,
+// ex. at the entry of a function, before its prologue has run.
+func (c *Client) CreateBreakpointAt(addrs ...uint64) (*Breakpoint, error) {
+	var out struct{ Breakpoint Breakpoint }
+	if err := c.call("CreateBreakpoint", struct{ Breakpoint Breakpoint }{Breakpoint{Addrs: addrs}}, &out); err != nil {
+		return nil, err
+	}
+	return &out.Breakpoint, nil
+}
+
+// LocalVars returns the local variables of a frame of the current
+// goroutine's stack, with 0 for the innermost frame.
+func (c *Client) LocalVars(frame int) ([]Variable, error) {
+	var out struct{ Variables []Variable }
+	err := c.call("ListLocalVars", struct {
+		Scope evalScope
+		Cfg   loadConfig
+	}{evalScope{GoroutineID: -1, Frame: frame}, defaultLoadConfig}, &out)
+	return out.Variables, err
+}
+
+// FunctionArgs returns the arguments of a frame of the current goroutine's
+// stack. Delve does not list the dictionary argument of stenciled code,
+// .dict, but reads it to resolve the types of the other arguments.
+//
+// Nor can .dict be evaluated, as it is not a Go identifier. To read the
+// dictionary, stop at the entry of the stenciled function, where it is
+// passed in the register of the first integer argument, ex. RAX on amd64,
+// and read the register with Registers and the dictionary with ReadMemory.
+func (c *Client) FunctionArgs(frame int) ([]Variable, error) {
+	var out struct{ Args []Variable }
+	err := c.call("ListFunctionArgs", struct {
+		Scope evalScope
+		Cfg   loadConfig
+	}{evalScope{GoroutineID: -1, Frame: frame}, defaultLoadConfig}, &out)
+	return out.Args, err
+}
+
+// Registers returns the values of the integer registers of a frame of the
+// current goroutine's stack, with 0 for the innermost frame, by their names
+// in lower case, ex. rax on amd64 or x0 on arm64.
+func (c *Client) Registers(frame int) (map[string]uint64, error) {
+	var out struct {
+		Regs []struct{ Name, Value string }
+	}
+	err := c.call("ListRegisters", struct{ Scope *evalScope }{&evalScope{GoroutineID: -1, Frame: frame}}, &out)
+	if err != nil {
+		return nil, err
+	}
+	regs := map[string]uint64{}
+	for _, r := range out.Regs {
+		// Delve formats the flags and the floating point registers as
+		// descriptions rather than as numbers, which are skipped.
+		if v, err := strconv.ParseUint(r.Value, 0, 64); err == nil {
+			regs[strings.ToLower(r.Name)] = v
+		}
+	}
+	return regs, nil
+}
+
+// ReadMemory returns n bytes of the process's memory at addr.
+func (c *Client) ReadMemory(addr uint64, n int) ([]byte, error) {
+	var out struct{ Mem []byte }
+	err := c.call("ExamineMemory", struct {
+		Address uint64
+		Length  int
+	}{addr, n}, &out)
+	return out.Mem, err
+}
+
+// Types returns the names of the program's types that match the regular
+// expression filter.
+func (c *Client) Types(filter string) ([]string, error) {
+	var out struct{ Types []string }
+	err := c.call("ListTypes", struct{ Filter string }{filter}, &out)
+	return out.Types, err
+}
+
+// Functions returns the names of the program's functions that match the
+// regular expression filter.
+func (c *Client) Functions(filter string) ([]string, error) {
+	var out struct{ Funcs []string }
+	err := c.call("ListFunctions", struct {
+		Filter      string
+		FollowCalls int
+	}{Filter: filter}, &out)
+	return out.Funcs, err
+}
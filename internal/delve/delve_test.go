@@ -0,0 +1,102 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delve_test
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-generics-the-hard-way/internal/delve"
+)
+
+func TestExecNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if _, err := delve.Exec("bin"); !errors.Is(err, delve.ErrNotInstalled) {
+		t.Errorf("Exec() = %v, want ErrNotInstalled", err)
+	}
+}
+
+// TestClient runs a session against a fake dlv that answers with canned
+// replies, which checks how the client encodes its calls and decodes the
+// replies.
+func TestClient(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a binary")
+	}
+	dir := t.TempDir()
+	build := exec.Command("go", "build", "-o", filepath.Join(dir, "dlv"), "./testdata/fakedlv")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s", err, out)
+	}
+	t.Setenv("PATH", dir)
+
+	c, err := delve.Exec("bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bp, err := c.CreateBreakpoint("main.printLen")
+	if err != nil || bp.FunctionName != "main.printLen" || len(bp.Addrs) != 2 {
+		t.Errorf("CreateBreakpoint() = %+v, %v, want a breakpoint at 2 addresses", bp, err)
+	}
+	st, err := c.Continue()
+	if err != nil || st.CurrentThread == nil || st.CurrentThread.Function.Name != "main.printLen[go.shape.int]" {
+		t.Fatalf("Continue() = %+v, %v, want to stop in main.printLen[go.shape.int]", st, err)
+	}
+	args, err := c.FunctionArgs(0)
+	want := []delve.Variable{{Name: "list", Type: "main.List[int]", Len: 3}}
+	if err != nil || !reflect.DeepEqual(args, want) {
+		t.Errorf("FunctionArgs() = %+v, %v, want %+v", args, err, want)
+	}
+
+	if st, err = c.Continue(); err != nil || st.CurrentThread.Function.Name != "main.main" {
+		t.Fatalf("Continue() = %+v, %v, want to stop in main.main", st, err)
+	}
+	locals, err := c.LocalVars(0)
+	if err != nil || len(locals) != 1 || len(locals[0].Children) != 1 || locals[0].Children[0].Value != "1" {
+		t.Errorf("LocalVars() = %+v, %v, want ints", locals, err)
+	}
+	types, err := c.Types(`main\.List`)
+	if err != nil || !reflect.DeepEqual(types, []string{`main\.List main.List[int]`}) {
+		t.Errorf("Types() = %q, %v", types, err)
+	}
+	if bp, err := c.CreateBreakpointAt(0x1000); err != nil || !reflect.DeepEqual(bp.Addrs, []uint64{0x1000}) {
+		t.Errorf("CreateBreakpointAt() = %+v, %v, want a breakpoint at 0x1000", bp, err)
+	}
+	regs, err := c.Registers(0)
+	if err != nil || !reflect.DeepEqual(regs, map[string]uint64{"rax": 0x4c2a60}) {
+		t.Errorf("Registers() = %v, %v, want rax without the flags", regs, err)
+	}
+	mem, err := c.ReadMemory(0x10, 3)
+	if err != nil || !reflect.DeepEqual(mem, []byte{0x10, 0x11, 0x12}) {
+		t.Errorf("ReadMemory() = %v, %v", mem, err)
+	}
+	funcs, err := c.Functions(`go\.shape`)
+	if err != nil || !reflect.DeepEqual(funcs, []string{`go\.shape main.printLen[go.shape.int]`}) {
+		t.Errorf("Functions() = %q, %v", funcs, err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+	if out := c.Output(); out != "3\n" {
+		t.Errorf("Output() = %q, want the output of the process", out)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command fakedlv serves a canned session over the JSON-RPC API of a
+// headless dlv server, so the client can be tested without Delve.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+)
+
+type variable struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Len      int64      `json:"len"`
+	Value    string     `json:"value"`
+	Children []variable `json:"children"`
+}
+
+type scope struct {
+	GoroutineID int64
+	Frame       int
+}
+
+type RPCServer struct {
+	listener net.Listener
+	hits     int
+}
+
+func (s *RPCServer) Command(cmd struct {
+	Name string `json:"name"`
+}, out *struct{ State map[string]interface{} }) error {
+	if cmd.Name != "continue" {
+		return fmt.Errorf("unknown command %q", cmd.Name)
+	}
+	s.hits++
+	fn := "main.printLen[go.shape.int]"
+	if s.hits > 1 {
+		fn = "main.main"
+		fmt.Println("3")
+	}
+	out.State = map[string]interface{}{
+		"Running": false,
+		"currentThread": map[string]interface{}{
+			"function": map[string]interface{}{"name": fn},
+		},
+	}
+	return nil
+}
+
+func (s *RPCServer) CreateBreakpoint(in struct {
+	Breakpoint struct {
+		FunctionName string
+		Addrs        []uint64
+	}
+}, out *struct{ Breakpoint map[string]interface{} }) error {
+	switch {
+	case in.Breakpoint.FunctionName != "":
+		out.Breakpoint = map[string]interface{}{"id": 1, "functionName": in.Breakpoint.FunctionName, "addrs": []uint64{1, 2}}
+	case len(in.Breakpoint.Addrs) > 0:
+		out.Breakpoint = map[string]interface{}{"id": 2, "addrs": in.Breakpoint.Addrs}
+	default:
+		return errors.New("no function or addresses")
+	}
+	return nil
+}
+
+func (s *RPCServer) ListRegisters(in struct{ Scope *scope }, out *struct {
+	Regs []map[string]interface{}
+}) error {
+	if in.Scope == nil || in.Scope.GoroutineID != -1 {
+		return fmt.Errorf("unexpected scope %+v", in.Scope)
+	}
+	out.Regs = []map[string]interface{}{
+		{"Name": "Rax", "Value": "0x00000000004c2a60", "DwarfNumber": 0},
+		{"Name": "Rflags", "Value": "[IF ZF]", "DwarfNumber": 49},
+	}
+	return nil
+}
+
+func (s *RPCServer) ExamineMemory(in struct {
+	Address uint64
+	Length  int
+}, out *struct{ Mem []byte }) error {
+	out.Mem = make([]byte, in.Length)
+	for i := range out.Mem {
+		out.Mem[i] = byte(in.Address) + byte(i)
+	}
+	return nil
+}
+
+func (s *RPCServer) ListFunctionArgs(in struct{ Scope scope }, out *struct{ Args []variable }) error {
+	if in.Scope.GoroutineID != -1 || in.Scope.Frame != 0 {
+		return fmt.Errorf("unexpected scope %+v", in.Scope)
+	}
+	out.Args = []variable{{Name: "list", Type: "main.List[int]", Len: 3}}
+	return nil
+}
+
+func (s *RPCServer) ListLocalVars(in struct{ Scope scope }, out *struct{ Variables []variable }) error {
+	out.Variables = []variable{{Name: "ints", Type: "main.List[int]", Len: 1, Children: []variable{{Value: "1"}}}}
+	return nil
+}
+
+func (s *RPCServer) ListTypes(in struct{ Filter string }, out *struct{ Types []string }) error {
+	out.Types = []string{in.Filter + " main.List[int]"}
+	return nil
+}
+
+func (s *RPCServer) ListFunctions(in struct{ Filter string }, out *struct{ Funcs []string }) error {
+	out.Funcs = []string{in.Filter + " main.printLen[go.shape.int]"}
+	return nil
+}
+
+func (s *RPCServer) Detach(in struct{ Kill bool }, out *struct{}) error {
+	if !in.Kill {
+		return errors.New("not killed")
+	}
+	return s.listener.Close()
+}
+
+func main() {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	srv := rpc.NewServer()
+	if err := srv.Register(&RPCServer{listener: l}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("API server listening at: %s\n", l.Addr())
+	conn, err := l.Accept()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcshape reads which generic instantiations in a compiled package
+// share code.
+//
+// The Go compiler stencils the code of a generic type or function once per
+// list of GC shapes of its type arguments, rather than once per list of type
+// arguments. Type arguments with the same underlying type share a shape, ex.
+// List[int] and List[MyInt] share the code for List[go.shape.int], and all
+// pointer types share the shape go.shape.*uint8. The stenciled code is
+// passed a dictionary for the concrete instantiation at runtime, with the
+// type descriptors and other information the shape does not determine.
+//
+// The package reads the object file the compiler writes for a package, and
+// pairs each call site that loads the dictionary of a concrete instantiation
+// with the call to the stenciled code that follows it. Please note that
+// inlining hides those call sites, so packages should be compiled with
+// -gcflags=-l, as Build does by default.
+//
+// The object files are read with package goobj, so the packages must be
+// compiled with Go 1.24 or later.
+package gcshape
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"go-generics-the-hard-way/internal/binsize"
+	"go-generics-the-hard-way/internal/goobj"
+)
+
+// Func is a function stenciled for a list of GC shapes.
+type Func struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+
+	// Dictionary is whether the function is passed a dictionary at any of
+	// its call sites in the package.
+	Dictionary bool `json:"dictionary"`
+}
+
+// Instantiation is a generic type or function instantiated with a list of
+// concrete type arguments.
+type Instantiation struct {
+	Name string `json:"name"`
+
+	// Dictionary is the size of the instantiation's dictionary.
+	Dictionary int64 `json:"dictionary"`
+
+	// Wrappers is the size of the code of the instantiation's own
+	// functions, such as the methods that call the stenciled code for the
+	// instantiation's method set.
+	Wrappers int64 `json:"wrappers"`
+}
+
+// Shape is the code stenciled for a generic type or function and a list of
+// GC shapes, and the instantiations that share it.
+type Shape struct {
+
+	// Name is the generic type or function instantiated with the shapes,
+	// ex. pkg.List[go.shape.int].
+	Name string `json:"name"`
+
+	// Generic is the generic type or function, ex. pkg.List.
+	Generic string `json:"generic"`
+
+	// Text is the total size of Funcs.
+	Text int64 `json:"text"`
+
+	Funcs []Func `json:"funcs"`
+
+	// Instantiations are the concrete instantiations that were found to
+	// call the stenciled code, ex. pkg.List[int] and pkg.List[pkg.MyInt].
+	Instantiations []Instantiation `json:"instantiations"`
+}
+
+// Report is the stenciled code of a package and the instantiations that
+// share it.
+type Report struct {
+	Package string `json:"package"`
+
+	// Shapes are sorted by Generic, and then by Name.
+	Shapes []*Shape `json:"shapes"`
+
+	// Unknown are the concrete instantiations whose shapes are not known,
+	// because no call from the package to their stenciled code was found.
+	Unknown []Instantiation `json:"unknown,omitempty"`
+}
+
+// Build compiles the packages that match a pattern, with inlining disabled
+// unless inline is true, and reads their stenciled code.
+func Build(pattern string, inline bool) ([]*Report, error) {
+	args := []string{"list", "-export", "-f", "{{.ImportPath}}\t{{.Export}}"}
+	if !inline {
+		args = append(args, "-gcflags=-l")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", append(args, pattern)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list %s: %v\n%s", pattern, err, stderr.Bytes())
+	}
+	var reports []*Report
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		pkg, export, _ := strings.Cut(line, "\t")
+		if export == "" {
+			return nil, fmt.Errorf("%s: no package archive", pkg)
+		}
+		r, err := Open(export)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pkg, err)
+		}
+		r.Package = pkg
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// Open reads the stenciled code of the package archive at path.
+func Open(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	members, err := goobj.ReadArchive(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	f, err := goobj.Parse(goobj.FindMember(members, goobj.ObjectMember))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return Read(f), nil
+}
+
+// Read reads the stenciled code of an object file.
+func Read(f *goobj.File) *Report {
+	var (
+		shapes  = map[string]*Shape{}
+		insts   = map[string]*Instantiation{}
+		funcs   = map[int]funcRef{}
+		shapeOf = map[string]string{}
+	)
+	instantiation := func(name string) *Instantiation {
+		in, ok := insts[name]
+		if !ok {
+			in = &Instantiation{Name: name}
+			insts[name] = in
+		}
+		return in
+	}
+
+	for i, s := range f.Syms[:f.NDef] {
+		name, generic, ok := binsize.ParseInstantiation(s.Name)
+		switch {
+		case !ok || s.Owner >= 0:
+		case s.Dict:
+			instantiation(name).Dictionary += s.Size
+		case s.Kind != goobj.Text:
+		case strings.Contains(name, "go.shape."):
+			sh, ok := shapes[name]
+			if !ok {
+				sh = &Shape{Name: name, Generic: generic}
+				shapes[name] = sh
+			}
+			funcs[i] = funcRef{sh, len(sh.Funcs)}
+			sh.Funcs = append(sh.Funcs, Func{Name: s.Name, Size: s.Size})
+		default:
+			instantiation(name).Wrappers += s.Size
+		}
+	}
+
+	// A call to stenciled code loads the address of the dictionary and
+	// then calls the code, ex.
+	//
+	//	LEAQ pkg..dict.List[int](SB), AX
+	//	CALL pkg.(*List[go.shape.int]).Add(SB)
+	for _, s := range f.Syms[:f.NDef] {
+		if s.Kind != goobj.Text {
+			continue
+		}
+		relocs := append([]goobj.Reloc(nil), s.Relocs...)
+		sort.SliceStable(relocs, func(i, j int) bool { return relocs[i].Off < relocs[j].Off })
+		var dict, dictGeneric string
+		for _, r := range relocs {
+			if r.Sym < 0 {
+				continue
+			}
+			t := &f.Syms[r.Sym]
+			name, generic, ok := binsize.ParseInstantiation(t.Name)
+			switch {
+			case !ok:
+			case t.Dict:
+				dict, dictGeneric = name, generic
+			case dict != "" && generic == dictGeneric:
+				if fn, ok := funcs[r.Sym]; ok {
+					shapeOf[dict] = name
+					fn.shape.Funcs[fn.i].Dictionary = true
+					dict = ""
+				}
+			}
+		}
+	}
+
+	r := Report{}
+	for _, sh := range shapes {
+		for _, fn := range sh.Funcs {
+			sh.Text += fn.Size
+		}
+		r.Shapes = append(r.Shapes, sh)
+	}
+	for name, in := range insts {
+		if sh, ok := shapes[shapeOf[name]]; ok {
+			sh.Instantiations = append(sh.Instantiations, *in)
+		} else {
+			r.Unknown = append(r.Unknown, *in)
+		}
+	}
+
+	sort.Slice(r.Shapes, func(i, j int) bool {
+		a, b := r.Shapes[i], r.Shapes[j]
+		if a.Generic != b.Generic {
+			return a.Generic < b.Generic
+		}
+		return a.Name < b.Name
+	})
+	for _, sh := range r.Shapes {
+		sort.Slice(sh.Funcs, func(i, j int) bool { return sh.Funcs[i].Name < sh.Funcs[j].Name })
+		sortInstantiations(sh.Instantiations)
+	}
+	sortInstantiations(r.Unknown)
+	return &r
+}
+
+// funcRef is a reference to a function of a shape.
+type funcRef struct {
+	shape *Shape
+	i     int
+}
+
+func sortInstantiations(ins []Instantiation) {
+	sort.Slice(ins, func(i, j int) bool { return ins[i].Name < ins[j].Name })
+}
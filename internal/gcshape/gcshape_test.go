@@ -0,0 +1,72 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcshape_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go-generics-the-hard-way/internal/gcshape"
+	"go-generics-the-hard-way/internal/testenv"
+)
+
+func TestBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles a package")
+	}
+	// The package is compiled by the same toolchain as the test, and goobj
+	// only reads the object files of recent releases.
+	testenv.NeedGo(t, 24)
+
+	reports, err := gcshape.Build("./testdata/shapes", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1", len(reports))
+	}
+	r := reports[0]
+	if len(r.Unknown) != 0 {
+		t.Errorf("got instantiations with unknown shapes: %+v", r.Unknown)
+	}
+
+	shared := map[string][]string{}
+	for _, sh := range r.Shapes {
+		for _, fn := range sh.Funcs {
+			if !fn.Dictionary || fn.Size == 0 {
+				t.Errorf("%s = %+v, want stenciled code that is passed a dictionary", sh.Name, fn)
+			}
+		}
+		for _, in := range sh.Instantiations {
+			if in.Dictionary == 0 {
+				t.Errorf("%s has no dictionary", in.Name)
+			}
+			shared[sh.Name] = append(shared[sh.Name], in.Name)
+		}
+	}
+	want := map[string][]string{
+		"main.List[go.shape.int]":    {"main.List[int]", "main.List[main.MyInt]"},
+		"main.List[go.shape.string]": {"main.List[main.ID]", "main.List[string]"},
+		"main.List[go.shape.*uint8]": {"main.List[*int]", "main.List[*string]"},
+		"main.Len[go.shape.int]":     {"main.Len[int]", "main.Len[main.MyInt]"},
+		"main.Len[go.shape.string]":  {"main.Len[main.ID]", "main.Len[string]"},
+		"main.Len[go.shape.*uint8]":  {"main.Len[*int]", "main.Len[*string]"},
+	}
+	if !reflect.DeepEqual(shared, want) {
+		t.Errorf("got shapes\n%v\nwant\n%v", shared, want)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+type List[T any] []T
+
+func (l *List[T]) Add(val T) {
+	*l = append(*l, val)
+}
+
+func Len[T any](l List[T]) int {
+	return len(l)
+}
+
+// MyInt has the same GC shape as int.
+type MyInt int
+
+// ID has the same GC shape as string.
+type ID string
+
+func main() {
+	var a List[int]
+	a.Add(1)
+	var b List[MyInt]
+	b.Add(1)
+	var c List[string]
+	c.Add("")
+	var d List[ID]
+	d.Add("")
+	var p List[*int]
+	p.Add(nil)
+	var q List[*string]
+	q.Add(nil)
+	println(Len(a), Len(b), Len(c), Len(d), Len(p), Len(q))
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package goobj reads the members of Go package archives, and the symbols
+// of the object files in them.
+//
+// The object file format is internal to the Go toolchain and changes between
+// Go versions. This package reads the go120ld format, with the symbol kinds
+// of Go 1.24 and later, see cmd/internal/goobj and cmd/internal/objabi. The
+// go118ld format of Go 1.18 and 1.19 is not supported.
+package goobj
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrFormat is wrapped by the errors for data that is not a package archive
+// or object file this package can read.
+var ErrFormat = errors.New("not a supported Go package archive")
+
+// formatError returns an error that wraps ErrFormat.
+func formatError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrFormat}, args...)...)
+}
+
+const (
+	arMagic     = "!<arch>\n"
+	arHeaderLen = 60
+
+	// ExportMember and ObjectMember are the names of the members of a
+	// package archive with the export data and the object file.
+	ExportMember = "__.PKGDEF"
+	ObjectMember = "_go_.o"
+)
+
+// Member is a member of an ar archive.
+type Member struct {
+	Name string
+
+	// Size is the size the member occupies in the archive, including its
+	// header and the padding that aligns members to even offsets.
+	Size int64
+
+	Data []byte
+}
+
+// ReadArchive reads the members of the ar archive in data.
+func ReadArchive(data []byte) ([]Member, error) {
+	if !bytes.HasPrefix(data, []byte(arMagic)) {
+		return nil, formatError("missing ar header")
+	}
+	var members []Member
+	for off := len(arMagic); off < len(data); {
+		if len(data)-off < arHeaderLen {
+			return nil, formatError("truncated ar header at offset %d", off)
+		}
+		hdr := data[off : off+arHeaderLen]
+		name := strings.TrimRight(string(hdr[:16]), " ")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(hdr[48:58])), 10, 64)
+		if err != nil || size < 0 || size > int64(len(data)-off-arHeaderLen) {
+			return nil, formatError("invalid size of ar member %q", name)
+		}
+		off += arHeaderLen
+		m := Member{Name: name, Size: arHeaderLen + size, Data: data[off : off+int(size)]}
+		if off += int(size); size%2 == 1 && off < len(data) {
+			off++
+			m.Size++
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+// FindMember returns the data of the member with the given name, or nil if
+// there is no such member.
+func FindMember(members []Member, name string) []byte {
+	for _, m := range members {
+		if m.Name == name {
+			return m.Data
+		}
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goobj
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Kind is the kind of data a symbol has.
+type Kind string
+
+const (
+	// Text is executable code.
+	Text Kind = "text"
+
+	// RoData is read-only data, such as dictionaries, type descriptors
+	// and string literals.
+	RoData Kind = "rodata"
+
+	// Data is writable data.
+	Data Kind = "data"
+
+	// Pcln is the metadata the runtime needs for a function, such as its
+	// pc-value tables and the liveness maps of its stack frame.
+	Pcln Kind = "pcln"
+
+	// DWARF is debug information.
+	DWARF Kind = "dwarf"
+
+	// Other is any other data.
+	Other Kind = "other"
+)
+
+// Kinds are the kinds of symbols, in the order they are reported.
+var Kinds = []Kind{Text, RoData, Data, Pcln, DWARF, Other}
+
+// Block is a contiguous part of an object file.
+type Block struct {
+	Name string
+
+	// Count is the number of entries in the block, if it is a table of
+	// symbols, relocations or auxiliary symbols.
+	Count int
+
+	Size int64
+}
+
+// Reloc is a relocation, a reference from a symbol's data to a symbol.
+type Reloc struct {
+	Off  int32
+	Size uint8
+	Type uint16
+	Add  int64
+
+	// Sym is the index of the target in File.Syms, or -1 if the target is
+	// a symbol of another package.
+	Sym int
+}
+
+// Aux is a reference from a symbol to an auxiliary symbol.
+type Aux struct {
+	Type uint8
+
+	// Sym is the index of the auxiliary symbol in File.Syms, or -1 if it
+	// is a symbol of another package.
+	Sym int
+}
+
+// Sym is a symbol that is defined or referenced by name in an object file.
+type Sym struct {
+	Name string
+
+	// Kind is the kind of the symbol's data. The kind of an auxiliary
+	// symbol is the kind of its first reference, either Pcln or DWARF.
+	Kind Kind
+
+	Dupok bool
+
+	// Dict is whether the symbol is the dictionary of a generic
+	// instantiation.
+	Dict bool
+
+	// Size is the size of the symbol's data in the object file, which is
+	// zero for symbols that are only referenced, and for zeroed data.
+	Size int64
+
+	Relocs []Reloc
+	Aux    []Aux
+
+	// Owner is the index in File.Syms of the first symbol that has this
+	// symbol as an auxiliary symbol, ex. a function that has this symbol
+	// as its pc-value table, or -1 if the symbol is not auxiliary.
+	Owner int
+}
+
+// File is an object file.
+type File struct {
+
+	// Header is the text header that precedes the object file in a package
+	// archive member, ex. go object linux amd64 go1.22.0 ...
+	Header string
+
+	// Blocks are the header, the string table, and the blocks of the
+	// object file, in the order they appear.
+	Blocks []Block
+
+	// Syms are the symbols defined in the object file, followed by the
+	// symbols it references by name.
+	Syms []Sym
+
+	// NDef is the number of defined symbols.
+	NDef int
+}
+
+// The blocks of a go120ld object file, see cmd/internal/goobj.
+var blockNames = []string{
+	"autolib", "pkgidx", "file", "symdef", "hashed64def", "hasheddef", "nonpkgdef", "nonpkgref",
+	"refflags", "hash64", "hash", "relocidx", "auxidx", "dataidx", "reloc", "aux", "data", "refname",
+}
+
+const (
+	objMagic        = "\x00go120ld"
+	fingerprintSize = 8
+
+	blkSymdef      = 3
+	blkHashed64def = 4
+	blkHasheddef   = 5
+	blkNonpkgdef   = 6
+	blkNonpkgref   = 7
+	blkRelocIdx    = 11
+	blkAuxIdx      = 12
+	blkDataIdx     = 13
+	blkReloc       = 14
+	blkAux         = 15
+	blkEnd         = 18
+
+	symSize = 21
+	auxSize = 9
+
+	pkgIdxNone     = 1<<31 - 1
+	pkgIdxHashed64 = pkgIdxNone - 1
+	pkgIdxHashed   = pkgIdxNone - 2
+	pkgIdxSelf     = pkgIdxNone - 4
+
+	symFlagDupok = 1
+	symFlagDict  = 4 // in the second flags byte
+
+	auxGotype     = 0
+	auxDwarfInfo  = 3
+	auxDwarfLines = 6
+)
+
+// RelocSize is the size of a relocation in an object file.
+const RelocSize = 23
+
+// symKind returns the kind of a symbol of type t, a cmd/internal/objabi
+// SymKind as numbered since Go 1.24.
+func symKind(t uint8) Kind {
+	switch {
+	case t == 1 || t == 2:
+		return Text
+	case t == 3 || t == 4:
+		return RoData
+	case 5 <= t && t <= 11:
+		return Data
+	case 12 <= t && t <= 21:
+		return DWARF
+	}
+	return Other
+}
+
+// Parse parses the object file in the data of a package archive's _go_.o
+// member.
+func Parse(data []byte) (*File, error) {
+	i := bytes.Index(data, []byte("\n!\n"))
+	if i < 0 {
+		return nil, formatError("no object file")
+	}
+	start := i + len("\n!\n")
+	b := data[start:]
+	offsetsOff := len(objMagic) + fingerprintSize + 4
+	hdrSize := offsetsOff + 4*(blkEnd+1)
+	if bytes.HasPrefix(b, []byte("\x00go118ld")) {
+		return nil, formatError("object file is in the go118ld format of Go 1.18 and 1.19, need go1.24 or later")
+	}
+	if !bytes.HasPrefix(b, []byte(objMagic)) {
+		return nil, formatError("object file is not in the go120ld format")
+	}
+	if len(b) < hdrSize {
+		return nil, formatError("truncated object file")
+	}
+	var offsets [blkEnd + 1]uint32
+	prev := uint32(hdrSize)
+	for i := range offsets {
+		offsets[i] = binary.LittleEndian.Uint32(b[offsetsOff+4*i:])
+		if offsets[i] < prev || offsets[i] > uint32(len(b)) {
+			return nil, formatError("invalid object file block offsets")
+		}
+		prev = offsets[i]
+	}
+
+	f := File{Header: string(data[:bytes.IndexByte(data, '\n')])}
+	f.Blocks = append(f.Blocks,
+		Block{Name: "header", Size: int64(start + hdrSize)},
+		Block{Name: "strings", Size: int64(offsets[0]) - int64(hdrSize)},
+	)
+	count := func(blk int, size uint32) int {
+		return int((offsets[blk+1] - offsets[blk]) / size)
+	}
+	for blk, name := range blockNames {
+		s := Block{Name: name, Size: int64(offsets[blk+1] - offsets[blk])}
+		switch blk {
+		case blkSymdef, blkHashed64def, blkHasheddef, blkNonpkgdef, blkNonpkgref:
+			s.Count = count(blk, symSize)
+		case blkReloc:
+			s.Count = count(blk, RelocSize)
+		case blkAux:
+			s.Count = count(blk, auxSize)
+		}
+		f.Blocks = append(f.Blocks, s)
+	}
+	if trailer := len(b) - int(offsets[blkEnd]); trailer > 0 {
+		f.Blocks = append(f.Blocks, Block{Name: "trailer", Size: int64(trailer)})
+	}
+
+	nsym := count(blkSymdef, symSize)
+	nhashed64 := count(blkHashed64def, symSize)
+	nhashed := count(blkHasheddef, symSize)
+	nnonpkg := nsym + nhashed64 + nhashed
+	f.NDef = nnonpkg + count(blkNonpkgdef, symSize)
+	nall := f.NDef + count(blkNonpkgref, symSize)
+	for _, blk := range []int{blkRelocIdx, blkAuxIdx, blkDataIdx} {
+		if int64(offsets[blk])+4*int64(f.NDef+1) > int64(offsets[blk+1]) {
+			return nil, formatError("truncated object file index")
+		}
+	}
+
+	u32 := func(off uint32) uint32 {
+		return binary.LittleEndian.Uint32(b[off:])
+	}
+	// indexed returns the range of entries of the i'th symbol in the
+	// block indexed by blk.
+	indexed := func(blk, i int) (uint32, uint32) {
+		off := offsets[blk] + 4*uint32(i)
+		return u32(off), u32(off + 4)
+	}
+	// sym returns the index of the symbol a reference is to, or -1 if it
+	// is not defined or referenced by name in this object file.
+	sym := func(off uint32) int {
+		pkg, idx := u32(off), int(u32(off+4))
+		var base int
+		switch pkg {
+		case pkgIdxSelf:
+		case pkgIdxHashed64:
+			base = nsym
+		case pkgIdxHashed:
+			base = nsym + nhashed64
+		case pkgIdxNone:
+			base = nnonpkg
+		default:
+			return -1
+		}
+		if i := base + idx; i < nall {
+			return i
+		}
+		return -1
+	}
+
+	f.Syms = make([]Sym, nall)
+	for i := range f.Syms {
+		s := &f.Syms[i]
+		soff := offsets[blkSymdef] + uint32(i)*symSize
+		nameLen, nameOff := u32(soff), u32(soff+4)
+		if int64(nameOff)+int64(nameLen) > int64(len(b)) {
+			return nil, formatError("invalid object file symbol name")
+		}
+		s.Name = string(b[nameOff : nameOff+nameLen])
+		s.Kind = symKind(b[soff+10])
+		s.Dupok = b[soff+11]&symFlagDupok != 0
+		s.Dict = b[soff+12]&symFlagDict != 0
+		s.Owner = -1
+		if i >= f.NDef {
+			continue
+		}
+
+		start, end := indexed(blkDataIdx, i)
+		s.Size = int64(end) - int64(start)
+
+		start, end = indexed(blkRelocIdx, i)
+		if int64(offsets[blkReloc])+int64(end)*RelocSize > int64(offsets[blkReloc+1]) || start > end {
+			return nil, formatError("invalid object file relocation index")
+		}
+		for j := start; j < end; j++ {
+			roff := offsets[blkReloc] + j*RelocSize
+			s.Relocs = append(s.Relocs, Reloc{
+				Off:  int32(u32(roff)),
+				Size: b[roff+4],
+				Type: binary.LittleEndian.Uint16(b[roff+5:]),
+				Add:  int64(binary.LittleEndian.Uint64(b[roff+7:])),
+				Sym:  sym(roff + 15),
+			})
+		}
+
+		start, end = indexed(blkAuxIdx, i)
+		if int64(offsets[blkAux])+int64(end)*auxSize > int64(offsets[blkAux+1]) || start > end {
+			return nil, formatError("invalid object file aux index")
+		}
+		for j := start; j < end; j++ {
+			aoff := offsets[blkAux] + j*auxSize
+			s.Aux = append(s.Aux, Aux{Type: b[aoff], Sym: sym(aoff + 1)})
+		}
+	}
+
+	// Mark each auxiliary symbol as owned by the first symbol that refers
+	// to it, as function metadata such as liveness maps may be shared.
+	for i := range f.Syms {
+		for _, a := range f.Syms[i].Aux {
+			if a.Type == auxGotype || a.Sym < 0 || a.Sym == i {
+				continue
+			}
+			s := &f.Syms[a.Sym]
+			if s.Owner >= 0 {
+				continue
+			}
+			s.Owner = i
+			s.Kind = Pcln
+			if auxDwarfInfo <= a.Type && a.Type <= auxDwarfLines {
+				s.Kind = DWARF
+			}
+		}
+	}
+	return &f, nil
+}
+
+// Root returns the index of the symbol that owns the i'th symbol, following
+// the owners of auxiliary symbols of auxiliary symbols, or i if the symbol
+// is not auxiliary.
+func (f *File) Root(i int) int {
+	for n := 0; f.Syms[i].Owner >= 0 && n < len(f.Syms); n++ {
+		i = f.Syms[i].Owner
+	}
+	return i
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goobj_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go-generics-the-hard-way/internal/goobj"
+)
+
+// member returns an ar member with the given name and data.
+func member(name, data string) string {
+	m := fmt.Sprintf("%-16s%-12s%-6s%-6s%-8s%-10d`\n%s", name, "0", "0", "0", "644", len(data), data)
+	if len(data)%2 == 1 {
+		m += "\n"
+	}
+	return m
+}
+
+func TestReadArchive(t *testing.T) {
+	data := "!<arch>\n" + member("__.PKGDEF", "odd") + member("_go_.o", "even")
+	members, err := goobj.ReadArchive([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("got %d members, want 2", len(members))
+	}
+	if m := members[0]; m.Name != "__.PKGDEF" || string(m.Data) != "odd" || m.Size != 60+4 {
+		t.Errorf("got %s %q of size %d, want the padding counted", m.Name, m.Data, m.Size)
+	}
+	if got := string(goobj.FindMember(members, goobj.ObjectMember)); got != "even" {
+		t.Errorf("FindMember(%s) = %q, want %q", goobj.ObjectMember, got, "even")
+	}
+	if got := goobj.FindMember(members, "missing"); got != nil {
+		t.Errorf("FindMember(missing) = %q, want nil", got)
+	}
+
+	for _, data := range []string{"", "!<arch>\n_go_.o", "!<arch>\n" + member("_go_.o", "data")[:62]} {
+		if _, err := goobj.ReadArchive([]byte(data)); !errors.Is(err, goobj.ErrFormat) {
+			t.Errorf("ReadArchive(%q) = %v, want an error that wraps ErrFormat", data, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	header := "go object linux amd64 go1.24.0\n\n!\n"
+	for _, data := range []string{
+		"",
+		header + "\x00go118ld",
+		header + "\x00go120ld",
+		header + "\x00go120ld" + string(make([]byte, 8+4)) + "\xff\xff\xff\xff" + string(make([]byte, 4*18)),
+	} {
+		if _, err := goobj.Parse([]byte(data)); !errors.Is(err, goobj.ErrFormat) {
+			t.Errorf("Parse(%q) = %v, want an error that wraps ErrFormat", data, err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package instances lists the instantiations of generic types and functions
+// in a compiled Go binary.
+//
+// Go erases generic "templates" from binaries, so reflect.TypeOf(List) does
+// not compile and the runtime cannot enumerate the instantiations of List.
+// The binary still describes them, though: the runtime type descriptors,
+// which the runtime enumerates from a module's type links for reflection,
+// include the concrete instantiations of generic types and pointers to
+// them, ex. main.List[int] and *main.List[string], and the pclntab names
+// the functions stenciled for each GC shape, ex.
+// main.(*List[go.shape.int]).add.
+//
+// The package reads both from an ELF binary built with Go 1.27 or later.
+// It reads the type descriptors from the image rather than the symbol
+// table, so it works for stripped binaries too. Binaries built with older
+// releases, ex. Go 1.18, store the type links in a .typelink section and
+// lay out the moduledata differently, and Open returns an error that wraps
+// ErrFormat for them.
+package instances
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-generics-the-hard-way/internal/binsize"
+	"go-generics-the-hard-way/internal/typeargs"
+)
+
+// ErrFormat is returned for binaries the package cannot read.
+var ErrFormat = errors.New("unsupported binary")
+
+// Source is where in a binary an instantiation was found.
+type Source string
+
+const (
+	// TypeLinks is the runtime type descriptors reachable from the type
+	// links.
+	TypeLinks Source = "typelinks"
+
+	// PCLnTab is the names of the functions in the pclntab.
+	PCLnTab Source = "pclntab"
+)
+
+// Instantiation is a generic type or function instantiated with a list of
+// type arguments.
+type Instantiation struct {
+
+	// Name is the instantiated type or function, ex. main.List[int].
+	Name string `json:"name"`
+
+	// Generic is the generic type or function, ex. main.List.
+	Generic string `json:"generic"`
+
+	// TypeArgs are the type arguments, ex. int.
+	TypeArgs []string `json:"typeArgs"`
+
+	// Shaped is whether the type arguments are GC shapes, i.e. whether the
+	// instantiation is the code stenciled for one or more concrete
+	// instantiations rather than a concrete instantiation.
+	Shaped bool `json:"shaped"`
+
+	Sources []Source `json:"sources"`
+
+	// Methods are the names of the methods in the type descriptors of a
+	// type and of the pointer to it.
+	Methods []string `json:"methods,omitempty"`
+
+	// Funcs are the functions of the instantiation in the pclntab.
+	Funcs []string `json:"funcs,omitempty"`
+}
+
+// Report is the instantiations in a binary.
+type Report struct {
+
+	// Types is the number of type descriptors read from the binary.
+	Types int `json:"types"`
+
+	// Funcs is the number of functions in the pclntab.
+	Funcs int `json:"funcs"`
+
+	// Instantiations are sorted by the generic type or function, with the
+	// concrete instantiations before the shaped ones.
+	Instantiations []*Instantiation `json:"instantiations"`
+}
+
+// Open reads the instantiations in the ELF binary at path.
+func Open(path string) (*Report, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Read reads the instantiations in an ELF binary.
+func Read(f *elf.File) (*Report, error) {
+	var (
+		r      Report
+		byName = map[string]*Instantiation{}
+	)
+	add := func(name, generic string, src Source) *Instantiation {
+		in, ok := byName[name]
+		if !ok {
+			in = &Instantiation{
+				Name:     name,
+				Generic:  generic,
+				TypeArgs: typeargs.Split(name[len(generic)+1 : len(name)-1]),
+				Shaped:   strings.Contains(name, "go.shape."),
+			}
+			byName[name] = in
+			r.Instantiations = append(r.Instantiations, in)
+		}
+		if len(in.Sources) == 0 || in.Sources[len(in.Sources)-1] != src {
+			in.Sources = append(in.Sources, src)
+		}
+		return in
+	}
+
+	img := newImage(f)
+	tr, err := newTypeReader(f, img)
+	if err != nil {
+		return nil, err
+	}
+	links, err := tr.typeLinks()
+	if err != nil {
+		return nil, err
+	}
+	types, err := tr.reachable(links)
+	if err != nil {
+		return nil, err
+	}
+	r.Types = len(types)
+
+	// Only defined types are instantiations, but the methods of the
+	// pointer receivers are in the descriptor of the pointer.
+	addrs := make([]uint64, 0, len(types))
+	for addr := range types {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i] < addrs[j] })
+	for _, addr := range addrs {
+		t := types[addr]
+		named := t
+		if elem := types[t.elem]; t.kind == kindPointer && elem != nil {
+			named = elem
+		}
+		if !named.named {
+			continue
+		}
+		name, generic, ok := binsize.ParseInstantiation(named.name)
+		if !ok || name != named.name {
+			continue
+		}
+		if pkg, ok := qualifier(named); ok {
+			name, generic = pkg+name[strings.IndexByte(name, '.'):], pkg+generic[strings.IndexByte(generic, '.'):]
+		}
+		in := add(name, generic, TypeLinks)
+		in.Methods = append(in.Methods, t.methods...)
+	}
+
+	funcs, err := readFuncs(f)
+	if err != nil {
+		return nil, err
+	}
+	r.Funcs = len(funcs)
+	for _, fn := range funcs {
+		// The equality functions the compiler generates for types are
+		// named after them, ex. type:.eq.main.Pair[int].
+		name, generic, ok := binsize.ParseInstantiation(strings.TrimPrefix(fn, "type:.eq."))
+		if !ok || !strings.Contains(generic, ".") {
+			continue
+		}
+		in := add(name, generic, PCLnTab)
+		in.Funcs = append(in.Funcs, fn)
+	}
+
+	for _, in := range r.Instantiations {
+		in.Methods = dedup(in.Methods)
+		in.Funcs = dedup(in.Funcs)
+	}
+	sort.Slice(r.Instantiations, func(i, j int) bool {
+		a, b := r.Instantiations[i], r.Instantiations[j]
+		if a.Generic != b.Generic {
+			return a.Generic < b.Generic
+		}
+		if a.Shaped != b.Shaped {
+			return !a.Shaped
+		}
+		return a.Name < b.Name
+	})
+	return &r, nil
+}
+
+// Lookup returns the instantiations of a generic type or function, ex.
+// main.List, which may also be given without its package qualifier, ex.
+// List.
+func (r *Report) Lookup(generic string) []*Instantiation {
+	var ins []*Instantiation
+	for _, in := range r.Instantiations {
+		if in.Generic == generic || binsize.TrimPackages(in.Generic) == generic {
+			ins = append(ins, in)
+		}
+	}
+	return ins
+}
+
+// qualifier returns the import path of a defined type's package, if the
+// type's name is qualified by the package name.
+func qualifier(t *rtype) (string, bool) {
+	i := strings.IndexByte(t.name, '.')
+	if t.pkgPath == "" || i < 0 || strings.IndexByte(t.name[:i], '[') >= 0 {
+		return "", false
+	}
+	return t.pkgPath, true
+}
+
+// readFuncs reads the names of the functions in the binary's pclntab.
+func readFuncs(f *elf.File) ([]string, error) {
+	text := f.Section(".text")
+	pclntab := f.Section(".gopclntab")
+	if text == nil || pclntab == nil {
+		return nil, fmt.Errorf("%w: not a Go binary, there is no .text or .gopclntab section", ErrFormat)
+	}
+	data, err := pclntab.Data()
+	if err != nil {
+		return nil, err
+	}
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, text.Addr))
+	if err != nil {
+		return nil, err
+	}
+	funcs := make([]string, 0, len(table.Funcs))
+	for _, fn := range table.Funcs {
+		funcs = append(funcs, fn.Name)
+	}
+	return funcs, nil
+}
+
+// dedup sorts a list of names and removes the duplicates.
+func dedup(names []string) []string {
+	sort.Strings(names)
+	out := names[:0]
+	for i, n := range names {
+		if i == 0 || n != names[i-1] {
+			out = append(out, n)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
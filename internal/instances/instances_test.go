@@ -0,0 +1,92 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"go-generics-the-hard-way/internal/instances"
+	"go-generics-the-hard-way/internal/testenv"
+)
+
+func TestOpen(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("binaries are only ELF on Linux")
+	}
+	if testing.Short() {
+		t.Skip("builds a binary")
+	}
+	// The binary is built by the same toolchain as the test, and Open only
+	// reads the module data of recent releases.
+	testenv.NeedGo(t, 27)
+
+	// The type descriptors are read from the image, so a stripped binary
+	// has the same instantiations.
+	for name, ldflags := range map[string]string{"unstripped": "", "stripped": "-s -w"} {
+		t.Run(name, func(t *testing.T) {
+			bin := filepath.Join(t.TempDir(), "lists")
+			build := exec.Command("go", "build", "-gcflags=-l", "-ldflags="+ldflags, "-o", bin, "./testdata/lists")
+			if out, err := build.CombinedOutput(); err != nil {
+				t.Fatalf("go build failed: %v\n%s", err, out)
+			}
+			r, err := instances.Open(bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			byName := map[string]*instances.Instantiation{}
+			for _, in := range r.Lookup("main.List") {
+				byName[in.Name] = in
+			}
+			for name, arg := range map[string]string{
+				"main.List[int]":     "int",
+				"main.List[string]":  "string",
+				"main.List[main.ID]": "main.ID",
+			} {
+				in := byName[name]
+				if in == nil || in.Shaped || in.Generic != "main.List" ||
+					!reflect.DeepEqual(in.TypeArgs, []string{arg}) ||
+					!reflect.DeepEqual(in.Methods, []string{"Add", "Len"}) ||
+					!reflect.DeepEqual(in.Sources, []instances.Source{instances.TypeLinks}) {
+					t.Errorf("%s = %+v, want a type descriptor with methods Add and Len", name, in)
+				}
+			}
+
+			// ID shares the code stenciled for string.
+			for _, name := range []string{"main.List[go.shape.int]", "main.List[go.shape.string]"} {
+				in := byName[name]
+				if in == nil || !in.Shaped || len(in.Funcs) == 0 ||
+					!reflect.DeepEqual(in.Sources, []instances.Source{instances.PCLnTab}) {
+					t.Errorf("%s = %+v, want stenciled code", name, in)
+				}
+			}
+			if len(byName) != 5 {
+				t.Errorf("got %d instantiations of main.List, want 5", len(byName))
+			}
+
+			pairs := r.Lookup("Pair")
+			if len(pairs) == 0 || pairs[0].Name != "main.Pair[string,map[string]int]" ||
+				!reflect.DeepEqual(pairs[0].TypeArgs, []string{"string", "map[string]int"}) {
+				t.Errorf("Lookup(Pair) = %+v, want main.Pair[string,map[string]int]", pairs)
+			}
+		})
+	}
+}
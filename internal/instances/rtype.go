@@ -0,0 +1,404 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instances
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+)
+
+// The kinds and flags of runtime type descriptors, from internal/abi.
+const (
+	kindArray     = 17
+	kindChan      = 18
+	kindFunc      = 19
+	kindInterface = 20
+	kindMap       = 21
+	kindPointer   = 22
+	kindSlice     = 23
+	kindString    = 24
+	kindStruct    = 25
+	kindUnsafePtr = 26
+	kindMask      = 0x1f
+
+	tflagUncommon  = 1 << 0
+	tflagExtraStar = 1 << 1
+	tflagNamed     = 1 << 2
+)
+
+// The indices of the moduledata fields the package reads, in words. The
+// fields before them are a pointer, six slices and nine pointer-sized
+// fields, in Go 1.27 and later.
+const (
+	mdText        = 22
+	mdTypes       = 37
+	mdTypeDescLen = 38
+	mdEtypes      = 39
+)
+
+// rtype is a runtime type descriptor, abi.Type, and the parts of its
+// kind-specific type that refer to other types.
+type rtype struct {
+	addr  uint64
+	kind  uint8
+	named bool
+	name  string
+
+	// pkgPath is the import path of a defined type's package. The name
+	// is only qualified by the package name, ex. sync.entry[int] for
+	// internal/sync.entry[int].
+	pkgPath string
+
+	// size is the size of the descriptor, as abi.Type.DescriptorSize
+	// computes it.
+	size uint64
+
+	// elems are the types the descriptor refers to, ex. the element type
+	// of a pointer, the fields of a struct or the parameters of a func,
+	// and the pointer to the type itself.
+	elems []uint64
+
+	// elem is the element type of an array, chan, pointer or slice.
+	elem uint64
+
+	// methods are the names of the methods of a defined type or of a
+	// pointer to one.
+	methods []string
+}
+
+// image is the allocated sections of an ELF binary, addressed by their
+// virtual addresses.
+type image struct {
+	order    binary.ByteOrder
+	ptrSize  uint64
+	sections []*elf.Section
+	data     map[*elf.Section][]byte
+}
+
+func newImage(f *elf.File) *image {
+	img := &image{order: f.ByteOrder, ptrSize: 8, data: map[*elf.Section][]byte{}}
+	if f.Class == elf.ELFCLASS32 {
+		img.ptrSize = 4
+	}
+	for _, s := range f.Sections {
+		if s.Flags&elf.SHF_ALLOC != 0 && s.Type != elf.SHT_NOBITS {
+			img.sections = append(img.sections, s)
+		}
+	}
+	return img
+}
+
+// at returns the bytes from addr to the end of the section that contains
+// it.
+func (img *image) at(addr uint64) ([]byte, error) {
+	for _, s := range img.sections {
+		if addr < s.Addr || addr >= s.Addr+s.Size {
+			continue
+		}
+		data, ok := img.data[s]
+		if !ok {
+			var err error
+			if data, err = s.Data(); err != nil {
+				return nil, err
+			}
+			img.data[s] = data
+		}
+		if off := addr - s.Addr; off < uint64(len(data)) {
+			return data[off:], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: address %#x is not in an allocated section", ErrFormat, addr)
+}
+
+// read returns n bytes at addr.
+func (img *image) read(addr, n uint64) ([]byte, error) {
+	b, err := img.at(addr)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(b)) < n {
+		return nil, fmt.Errorf("%w: %d bytes at %#x cross the end of a section", ErrFormat, n, addr)
+	}
+	return b[:n], nil
+}
+
+func (img *image) word(b []byte, i uint64) uint64 {
+	if img.ptrSize == 4 {
+		return uint64(img.order.Uint32(b[i:]))
+	}
+	return img.order.Uint64(b[i:])
+}
+
+// typeReader reads the type descriptors of a module, which the linker
+// places between the moduledata's types and etypes.
+type typeReader struct {
+	*image
+	types, typeDescLen, etypes uint64
+}
+
+// newTypeReader reads the moduledata of the binary's first and only module.
+func newTypeReader(f *elf.File, img *image) (*typeReader, error) {
+	// Until Go 1.27 the linker wrote the type links to their own section,
+	// as offsets from types, and the moduledata had a different layout.
+	if f.Section(".typelink") != nil {
+		return nil, fmt.Errorf("%w: the binary has a .typelink section, it must be built with Go 1.27 or later", ErrFormat)
+	}
+	var addr uint64
+	if s := f.Section(".go.module"); s != nil {
+		addr = s.Addr
+	} else if syms, err := f.Symbols(); err == nil {
+		for _, s := range syms {
+			if s.Name == "runtime.firstmoduledata" {
+				addr = s.Value
+			}
+		}
+	}
+	if addr == 0 {
+		return nil, fmt.Errorf("%w: there is no .go.module section or runtime.firstmoduledata symbol", ErrFormat)
+	}
+	md, err := img.read(addr, (mdEtypes+1)*img.ptrSize)
+	if err != nil {
+		return nil, err
+	}
+	r := &typeReader{
+		image:       img,
+		types:       img.word(md, mdTypes*img.ptrSize),
+		typeDescLen: img.word(md, mdTypeDescLen*img.ptrSize),
+		etypes:      img.word(md, mdEtypes*img.ptrSize),
+	}
+	// The fields move between releases, so check that they are where this
+	// package expects them to be before trusting them.
+	text := f.Section(".text")
+	if text == nil || img.word(md, mdText*img.ptrSize) != text.Addr ||
+		r.types == 0 || r.types > r.etypes || r.typeDescLen > r.etypes-r.types {
+		return nil, fmt.Errorf("%w: unsupported moduledata layout, the binary must be built with Go 1.27 or later", ErrFormat)
+	}
+	return r, nil
+}
+
+// typeLinks returns the addresses of the type descriptors the runtime
+// enumerates for reflection, which the linker sorts to the start of the
+// types, the same way as runtime.moduleTypelinks.
+func (r *typeReader) typeLinks() ([]uint64, error) {
+	var addrs []uint64
+	end := r.types + r.typeDescLen
+	for td := r.types + r.ptrSize; td < end; {
+		td = align(td, r.ptrSize)
+		t, err := r.typ(td)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, td)
+		td += t.size
+	}
+	return addrs, nil
+}
+
+// reachable returns the type descriptors the roots refer to, directly or
+// indirectly, including the roots.
+func (r *typeReader) reachable(roots []uint64) (map[uint64]*rtype, error) {
+	types := map[uint64]*rtype{}
+	queue := append([]uint64(nil), roots...)
+	for len(queue) > 0 {
+		addr := queue[0]
+		queue = queue[1:]
+		if _, ok := types[addr]; ok {
+			continue
+		}
+		t, err := r.typ(addr)
+		if err != nil {
+			return nil, err
+		}
+		types[addr] = t
+		queue = append(queue, t.elems...)
+	}
+	return types, nil
+}
+
+// typ reads the type descriptor at addr.
+func (r *typeReader) typ(addr uint64) (*rtype, error) {
+	p := r.ptrSize
+	base := 4*p + 16
+	hdr, err := r.read(addr, base)
+	if err != nil {
+		return nil, err
+	}
+	tflag := hdr[2*p+4]
+	t := &rtype{
+		addr:  addr,
+		kind:  hdr[2*p+7] & kindMask,
+		named: tflag&tflagNamed != 0,
+	}
+	if t.name, err = r.name(int32(r.order.Uint32(hdr[4*p+8:]))); err != nil {
+		return nil, err
+	}
+	if tflag&tflagExtraStar != 0 && len(t.name) > 0 {
+		t.name = t.name[1:]
+	}
+	t.addTypeOff(r, int32(r.order.Uint32(hdr[4*p+12:])))
+
+	// The kind-specific fields follow the abi.Type, and then the
+	// uncommon type, the parameters of a func and the methods.
+	var add uint64
+	switch t.kind {
+	case kindArray, kindChan, kindPointer, kindSlice:
+		b, err := r.read(addr, base+p)
+		if err != nil {
+			return nil, err
+		}
+		t.elem = r.word(b, base)
+		t.addElem(r, t.elem)
+		switch t.kind {
+		case kindArray:
+			base += 3 * p
+		case kindChan:
+			base += 2 * p
+		default:
+			base += p
+		}
+	case kindMap:
+		b, err := r.read(addr, base+2*p)
+		if err != nil {
+			return nil, err
+		}
+		t.addElem(r, r.word(b, base))
+		t.addElem(r, r.word(b, base+p))
+		// Key, Elem, Group, Hasher, GroupSize, KeysOff, KeyStride,
+		// ElemsOff, ElemStride, ElemOff and Flags.
+		base = align(base+10*p+4, p)
+	case kindFunc:
+		b, err := r.read(addr, base+4)
+		if err != nil {
+			return nil, err
+		}
+		in, out := uint64(r.order.Uint16(b[base:])), uint64(r.order.Uint16(b[base+2:])&(1<<15-1))
+		base = align(base+4, p)
+		add = (in + out) * p
+	case kindInterface, kindStruct:
+		// PkgPath and the slice of methods or fields.
+		b, err := r.read(addr, base+4*p)
+		if err != nil {
+			return nil, err
+		}
+		ptr, n := r.word(b, base+p), r.word(b, base+2*p)
+		switch {
+		case n == 0:
+		case t.kind == kindInterface:
+			add = n * 8
+			ms, err := r.read(ptr, add)
+			if err != nil {
+				return nil, err
+			}
+			for i := uint64(0); i < n; i++ {
+				t.addTypeOff(r, int32(r.order.Uint32(ms[i*8+4:])))
+			}
+		default:
+			add = n * 3 * p
+			fs, err := r.read(ptr, add)
+			if err != nil {
+				return nil, err
+			}
+			for i := uint64(0); i < n; i++ {
+				t.addElem(r, r.word(fs, i*3*p+p))
+			}
+		}
+		base += 4 * p
+	default:
+		if t.kind == 0 || t.kind > kindUnsafePtr {
+			return nil, fmt.Errorf("%w: invalid kind %d of type descriptor at %#x", ErrFormat, t.kind, addr)
+		}
+	}
+	t.size = base + add
+
+	var mcount uint64
+	if tflag&tflagUncommon != 0 {
+		u, err := r.read(addr+base, 16)
+		if err != nil {
+			return nil, err
+		}
+		if t.pkgPath, err = r.name(int32(r.order.Uint32(u))); err != nil {
+			return nil, err
+		}
+		mcount = uint64(r.order.Uint16(u[4:]))
+		ms, err := r.read(addr+base+uint64(r.order.Uint32(u[8:])), mcount*16)
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < mcount; i++ {
+			name, err := r.name(int32(r.order.Uint32(ms[i*16:])))
+			if err != nil {
+				return nil, err
+			}
+			t.methods = append(t.methods, name)
+			t.addTypeOff(r, int32(r.order.Uint32(ms[i*16+4:])))
+		}
+		t.size += 16
+	}
+	if t.kind == kindFunc {
+		ps, err := r.read(addr+t.size, add)
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < add; i += p {
+			t.addElem(r, r.word(ps, i))
+		}
+	}
+	t.size += mcount * 16
+	return t, nil
+}
+
+// addElem adds a reference to the type descriptor at addr, unless it is
+// nil or outside the module's types.
+func (t *rtype) addElem(r *typeReader, addr uint64) {
+	if addr > r.types && addr < r.etypes {
+		t.elems = append(t.elems, addr)
+	}
+}
+
+// addTypeOff adds a reference to a type descriptor by its offset from the
+// module's types. The linker sets the offsets of unreachable types to -1
+// and a missing pointer type to 0.
+func (t *rtype) addTypeOff(r *typeReader, off int32) {
+	if off > 0 {
+		t.addElem(r, r.types+uint64(off))
+	}
+}
+
+// name reads an abi.Name by its offset from the module's types. A name is
+// a byte of flags, followed by the varint length of the name and the name.
+func (r *typeReader) name(off int32) (string, error) {
+	if off <= 0 {
+		return "", nil
+	}
+	b, err := r.at(r.types + uint64(off))
+	if err != nil {
+		return "", err
+	}
+	if len(b) < 2 {
+		return "", fmt.Errorf("%w: truncated name at offset %#x", ErrFormat, off)
+	}
+	n, i := binary.Uvarint(b[1:])
+	if i <= 0 || uint64(len(b)-1-i) < n {
+		return "", fmt.Errorf("%w: truncated name at offset %#x", ErrFormat, off)
+	}
+	return string(b[1+i : 1+i+int(n)]), nil
+}
+
+func align(n, to uint64) uint64 {
+	return (n + to - 1) &^ (to - 1)
+}
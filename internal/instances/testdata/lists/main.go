@@ -0,0 +1,52 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import "fmt"
+
+type List[T any] []T
+
+func (l *List[T]) Add(val T) {
+	*l = append(*l, val)
+}
+
+func (l List[T]) Len() int {
+	return len(l)
+}
+
+// ID has the same GC shape as string.
+type ID string
+
+type Pair[K comparable, V any] struct {
+	Key K
+	Val V
+}
+
+func main() {
+	var ints List[int]
+	ints.Add(1)
+
+	var strs List[string]
+	strs.Add("1")
+
+	var ids List[ID]
+	ids.Add("1")
+
+	p := Pair[string, map[string]int]{Key: "1"}
+
+	fmt.Println(ints, strs, ids, p)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testenv provides helpers for tests that depend on the version of
+// the Go toolchain running them.
+//
+// Several packages in this repository read formats that are internal to the
+// Go toolchain, ex. package archives and the runtime's type descriptors, and
+// only support the releases those formats have had since a given version of
+// Go. Their tests build with the same toolchain as the test binary, so they
+// use NeedGo to skip on older releases, ex. the Go 1.18 release the rest of
+// the repository supports.
+package testenv
+
+import (
+	"regexp"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+var goVersion = regexp.MustCompile(`\bgo1\.(\d+)`)
+
+// GoMinor returns the minor version of a Go version string, ex. 24 for
+// go1.24.3 or devel go1.25-abcdef. The second return value is false if the
+// string has no such version, ex. a development build without one.
+func GoMinor(version string) (int, bool) {
+	m := goVersion.FindStringSubmatch(version)
+	if m == nil {
+		return 0, false
+	}
+	minor, err := strconv.Atoi(m[1])
+	return minor, err == nil
+}
+
+// NeedGo skips the test if it was built by a release of Go older than
+// go1.minor. A toolchain with no version is assumed to be recent.
+func NeedGo(t testing.TB, minor int) {
+	t.Helper()
+	if have, ok := GoMinor(runtime.Version()); ok && have < minor {
+		t.Skipf("requires go1.%d or later, have %s", minor, runtime.Version())
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv_test
+
+import (
+	"testing"
+
+	"go-generics-the-hard-way/internal/testenv"
+)
+
+func TestGoMinor(t *testing.T) {
+	testCases := []struct {
+		version string
+		minor   int
+		ok      bool
+	}{
+		{"go1.18beta2", 18, true},
+		{"go1.24.3", 24, true},
+		{"devel go1.25-abcdef Mon Jan 1 00:00:00 2025 +0000", 25, true},
+		{"go object linux amd64 go1.20.1 X:none", 20, true},
+		{"devel +abcdef", 0, false},
+		{"cargo1.5", 0, false},
+	}
+	for _, tc := range testCases {
+		if minor, ok := testenv.GoMinor(tc.version); minor != tc.minor || ok != tc.ok {
+			t.Errorf("GoMinor(%q) = %d, %v, want %d, %v", tc.version, minor, ok, tc.minor, tc.ok)
+		}
+	}
+}
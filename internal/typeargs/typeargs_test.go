@@ -0,0 +1,42 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typeargs_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go-generics-the-hard-way/internal/typeargs"
+)
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		list string
+		want []string
+	}{
+		{list: "int", want: []string{"int"}},
+		{list: "string,[]int", want: []string{"string", "[]int"}},
+		{list: "string, map[string][]int", want: []string{"string", "map[string][]int"}},
+		{list: "func(int, string) error,int", want: []string{"func(int, string) error", "int"}},
+		{list: "struct { A int; B string },pkg.List[int,string]", want: []string{"struct { A int; B string }", "pkg.List[int,string]"}},
+	}
+	for _, tc := range testCases {
+		if got := typeargs.Split(tc.list); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Split(%q) = %q, want %q", tc.list, got, tc.want)
+		}
+	}
+}
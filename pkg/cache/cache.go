@@ -0,0 +1,296 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache provides a generic, bounded cache with LRU or LFU eviction
+// and optional per-entry expiration.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Policy is an eviction policy.
+type Policy uint8
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU Policy = iota
+
+	// LFU evicts the least frequently used entry. Ties are broken by
+	// evicting the least recently used entry.
+	LFU
+)
+
+// EvictReason describes why an entry was removed from a cache.
+type EvictReason uint8
+
+const (
+	// Capacity means the entry was evicted to make room for another entry.
+	Capacity EvictReason = iota
+
+	// Expired means the entry's TTL elapsed.
+	Expired
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case Capacity:
+		return "capacity"
+	case Expired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// Options configure a cache.
+type Options[K comparable, V any] struct {
+
+	// Capacity is the maximum number of entries in the cache. A value less
+	// than one means the cache is unbounded.
+	Capacity int
+
+	// Policy is the eviction policy used when the cache is full.
+	Policy Policy
+
+	// TTL is the default time-to-live for entries added with Put. A value of
+	// zero means entries do not expire.
+	TTL time.Duration
+
+	// OnEvict, if not nil, is called after an entry is evicted or expires.
+	// It is not called for entries removed with Delete or Purge, or for an
+	// entry replaced by Put.
+	//
+	// Please note OnEvict is called without holding the cache's lock, so it
+	// may safely call back into the cache.
+	OnEvict func(key K, val V, reason EvictReason)
+
+	// Now returns the current time. It defaults to time.Now and exists so
+	// tests can control expiration.
+	Now func() time.Time
+}
+
+// Stats are the counters for a cache.
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
+
+// HitRatio returns the ratio of hits to lookups, or zero if there have not
+// been any lookups.
+func (s Stats) HitRatio() float64 {
+	if n := s.Hits + s.Misses; n > 0 {
+		return float64(s.Hits) / float64(n)
+	}
+	return 0
+}
+
+func (s Stats) add(o Stats) Stats {
+	return Stats{
+		Hits:        s.Hits + o.Hits,
+		Misses:      s.Misses + o.Misses,
+		Evictions:   s.Evictions + o.Evictions,
+		Expirations: s.Expirations + o.Expirations,
+	}
+}
+
+// evicted is an entry to pass to OnEvict once the lock has been released.
+type evicted[K comparable, V any] struct {
+	key    K
+	val    V
+	reason EvictReason
+}
+
+// Cache is a bounded map with an eviction policy. It is safe for concurrent
+// use, although Sharded should be preferred for caches with heavy contention.
+//
+// The zero value is not usable, please use New.
+type Cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	opts    Options[K, V]
+	items   map[K]*entry[K, V]
+	policy  policy[K, V]
+	stats   Stats
+	pending []evicted[K, V]
+}
+
+// New returns a new cache configured by opts.
+func New[K comparable, V any](opts Options[K, V]) *Cache[K, V] {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	c := &Cache[K, V]{
+		opts:  opts,
+		items: map[K]*entry[K, V]{},
+	}
+	switch opts.Policy {
+	case LFU:
+		c.policy = newLFU[K, V]()
+	default:
+		c.policy = newLRU[K, V]()
+	}
+	return c
+}
+
+// Get returns the value for key. The second return value is false if the key
+// is not cached or has expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	e, ok := c.items[key]
+	if ok && c.expired(e) {
+		c.evict(e, Expired)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		c.unlock()
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.policy.touch(e)
+	val := e.val
+	c.mu.Unlock()
+	return val, true
+}
+
+// Put caches val for key using the default TTL.
+func (c *Cache[K, V]) Put(key K, val V) {
+	c.PutWithTTL(key, val, c.opts.TTL)
+}
+
+// PutWithTTL caches val for key. The entry expires after ttl, or never if ttl
+// is zero.
+func (c *Cache[K, V]) PutWithTTL(key K, val V, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.opts.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	if e, ok := c.items[key]; ok {
+		e.val = val
+		e.expires = expires
+		c.policy.touch(e)
+		c.mu.Unlock()
+		return
+	}
+
+	if c.opts.Capacity > 0 && len(c.items) >= c.opts.Capacity {
+		if v := c.policy.victim(); v != nil {
+			reason := Capacity
+			if c.expired(v) {
+				reason = Expired
+			}
+			c.evict(v, reason)
+		}
+	}
+
+	e := &entry[K, V]{key: key, val: val, expires: expires}
+	c.items[key] = e
+	c.policy.add(e)
+	c.unlock()
+}
+
+// Delete removes key from the cache and returns true if it was present.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if ok {
+		c.remove(e)
+	}
+	return ok
+}
+
+// Len returns the number of entries in the cache, including entries that
+// have expired but have not yet been removed.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// RemoveExpired removes all expired entries and returns how many were
+// removed. Expired entries are otherwise removed lazily by Get and Put.
+func (c *Cache[K, V]) RemoveExpired() int {
+	c.mu.Lock()
+	var n int
+	for _, e := range c.items {
+		if c.expired(e) {
+			c.evict(e, Expired)
+			n++
+		}
+	}
+	c.unlock()
+	return n
+}
+
+// Purge removes all entries from the cache without calling OnEvict.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.items {
+		c.remove(e)
+	}
+}
+
+// Stats returns a snapshot of the cache's counters.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *Cache[K, V]) expired(e *entry[K, V]) bool {
+	return !e.expires.IsZero() && !c.opts.Now().Before(e.expires)
+}
+
+func (c *Cache[K, V]) remove(e *entry[K, V]) {
+	c.policy.remove(e)
+	delete(c.items, e.key)
+}
+
+// evict removes e and queues it for OnEvict. The caller must hold the lock
+// and release it with unlock.
+func (c *Cache[K, V]) evict(e *entry[K, V], reason EvictReason) {
+	c.remove(e)
+	if reason == Expired {
+		c.stats.Expirations++
+	} else {
+		c.stats.Evictions++
+	}
+	if c.opts.OnEvict != nil {
+		c.pending = append(c.pending, evicted[K, V]{e.key, e.val, reason})
+	}
+}
+
+// unlock releases the lock and then calls OnEvict for any evicted entries.
+func (c *Cache[K, V]) unlock() {
+	if len(c.pending) == 0 {
+		c.mu.Unlock()
+		return
+	}
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+	for i := range pending {
+		c.opts.OnEvict(pending[i].key, pending[i].val, pending[i].reason)
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-generics-the-hard-way/pkg/cache"
+	"go-generics-the-hard-way/pkg/hasher"
+)
+
+// ID is a type definition with an underlying type of string.
+type ID string
+
+func ExampleNew_lru() {
+	c := cache.New(cache.Options[string, int]{
+		Capacity: 2,
+		OnEvict: func(k string, v int, r cache.EvictReason) {
+			fmt.Println("evicted", k, v, r)
+		},
+	})
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Get("a")
+	c.Put("c", 3)
+	fmt.Println(c.Get("b"))
+	fmt.Println(c.Get("a"))
+	fmt.Printf("%+v\n", c.Stats())
+	// Output:
+	// evicted b 2 capacity
+	// 0 false
+	// 1 true
+	// {Hits:2 Misses:1 Evictions:1 Expirations:0}
+}
+
+func ExampleNew_lfu() {
+	c := cache.New(cache.Options[string, int]{
+		Capacity: 2,
+		Policy:   cache.LFU,
+		OnEvict: func(k string, v int, r cache.EvictReason) {
+			fmt.Println("evicted", k, v, r)
+		},
+	})
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Put("c", 3)
+	c.Put("d", 4)
+	// Output:
+	// evicted b 2 capacity
+	// evicted c 3 capacity
+}
+
+func ExampleNewSharded() {
+	c := cache.NewSharded(
+		4,
+		hasher.String[ID],
+		cache.Options[ID, []int]{Capacity: 100})
+	c.Put("acct-1", []int{1, 2, 3})
+	fmt.Println(c.Get("acct-1"))
+	fmt.Println(c.Len())
+	// Output:
+	// [1 2 3] true
+	// 1
+}
+
+// clock is a fake time source for testing expiration.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestCacheTTL(t *testing.T) {
+	clk := &clock{now: time.Unix(0, 0)}
+	var expired []string
+	c := cache.New(cache.Options[string, int]{
+		TTL: time.Minute,
+		Now: clk.Now,
+		OnEvict: func(k string, _ int, r cache.EvictReason) {
+			if r != cache.Expired {
+				t.Errorf("%s evicted with reason %s", k, r)
+			}
+			expired = append(expired, k)
+		},
+	})
+	c.Put("a", 1)
+	c.PutWithTTL("b", 2, time.Hour)
+	c.PutWithTTL("c", 3, 0)
+	c.PutWithTTL("d", 4, 2*time.Minute)
+
+	clk.Advance(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a should have expired")
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Errorf("Get(b) = %d, %v", v, ok)
+	}
+
+	clk.Advance(24 * time.Hour)
+	if n := c.RemoveExpired(); n != 2 {
+		t.Errorf("RemoveExpired() = %d, want 2", n)
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Errorf("Get(c) = %d, %v", v, ok)
+	}
+	if n := len(expired); n != 3 {
+		t.Errorf("OnEvict called %d times, want 3", n)
+	}
+
+	st := c.Stats()
+	if st.Expirations != 3 || st.Hits != 2 || st.Misses != 1 {
+		t.Errorf("unexpected stats %+v", st)
+	}
+}
+
+func TestCacheOnEvictReentrant(t *testing.T) {
+	var c *cache.Cache[int, int]
+	c = cache.New(cache.Options[int, int]{
+		Capacity: 1,
+		OnEvict: func(k, v int, _ cache.EvictReason) {
+			// Calling back into the cache must not deadlock.
+			c.Get(k)
+		},
+	})
+	c.Put(1, 1)
+	c.Put(2, 2)
+	if c.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", c.Len())
+	}
+}
+
+func TestCacheLFUCapacity(t *testing.T) {
+	c := cache.New(cache.Options[int, int]{Capacity: 8, Policy: cache.LFU})
+
+	// Keys 0-3 are hot and must survive a stream of cold keys.
+	for i := 0; i < 4; i++ {
+		c.Put(i, i)
+		for j := 0; j < 10; j++ {
+			c.Get(i)
+		}
+	}
+	for i := 100; i < 1000; i++ {
+		c.Put(i, i)
+		c.Get(i)
+	}
+	for i := 0; i < 4; i++ {
+		if _, ok := c.Get(i); !ok {
+			t.Errorf("hot key %d was evicted", i)
+		}
+	}
+	if c.Len() != 8 {
+		t.Errorf("Len() = %d, want 8", c.Len())
+	}
+}
+
+func TestShardedConcurrent(t *testing.T) {
+	c := cache.NewSharded(
+		8,
+		hasher.Integer[int],
+		cache.Options[int, int]{Capacity: 1024})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				k := (g*2000 + i) % 1500
+				c.Put(k, k)
+				if v, ok := c.Get(k); ok && v != k {
+					t.Errorf("Get(%d) = %d", k, v)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if n := c.Len(); n > 1024 {
+		t.Errorf("Len() = %d, want <= 1024", n)
+	}
+}
+
+func TestNewShardedNilHash(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewSharded did not panic with a nil hash function")
+		}
+	}()
+	cache.NewSharded[int, int](4, nil, cache.Options[int, int]{})
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"time"
+)
+
+// entry is a cached value and its position in the eviction policy's lists.
+type entry[K comparable, V any] struct {
+	key     K
+	val     V
+	expires time.Time
+
+	prev, next *entry[K, V]
+
+	// bucket is only used by the LFU policy.
+	bucket *bucket[K, V]
+}
+
+// list is a circular, doubly-linked list of entries with a sentinel root.
+type list[K comparable, V any] struct {
+	root entry[K, V]
+	len  int
+}
+
+func (l *list[K, V]) init() {
+	l.root.prev = &l.root
+	l.root.next = &l.root
+	l.len = 0
+}
+
+func (l *list[K, V]) pushFront(e *entry[K, V]) {
+	e.prev = &l.root
+	e.next = l.root.next
+	l.root.next.prev = e
+	l.root.next = e
+	l.len++
+}
+
+func (l *list[K, V]) remove(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+	l.len--
+}
+
+func (l *list[K, V]) back() *entry[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// policy decides which entry is evicted when a cache is full.
+type policy[K comparable, V any] interface {
+	// add records a new entry.
+	add(e *entry[K, V])
+
+	// touch records an access to an existing entry.
+	touch(e *entry[K, V])
+
+	// remove forgets an entry.
+	remove(e *entry[K, V])
+
+	// victim returns the entry that should be evicted next.
+	victim() *entry[K, V]
+}
+
+// lru evicts the least recently used entry.
+type lru[K comparable, V any] struct {
+	l list[K, V]
+}
+
+func newLRU[K comparable, V any]() *lru[K, V] {
+	p := &lru[K, V]{}
+	p.l.init()
+	return p
+}
+
+func (p *lru[K, V]) add(e *entry[K, V]) {
+	p.l.pushFront(e)
+}
+
+func (p *lru[K, V]) touch(e *entry[K, V]) {
+	p.l.remove(e)
+	p.l.pushFront(e)
+}
+
+func (p *lru[K, V]) remove(e *entry[K, V]) {
+	p.l.remove(e)
+}
+
+func (p *lru[K, V]) victim() *entry[K, V] {
+	return p.l.back()
+}
+
+// bucket is the list of entries that have been accessed freq times.
+type bucket[K comparable, V any] struct {
+	freq       uint64
+	entries    list[K, V]
+	prev, next *bucket[K, V]
+}
+
+// lfu evicts the least frequently used entry, breaking ties by evicting the
+// least recently used entry. All operations are O(1).
+type lfu[K comparable, V any] struct {
+	// root is the sentinel of a circular list of buckets in ascending order
+	// of frequency.
+	root bucket[K, V]
+}
+
+func newLFU[K comparable, V any]() *lfu[K, V] {
+	p := &lfu[K, V]{}
+	p.root.prev = &p.root
+	p.root.next = &p.root
+	return p
+}
+
+// insertAfter returns a new bucket for freq inserted after b.
+func (p *lfu[K, V]) insertAfter(b *bucket[K, V], freq uint64) *bucket[K, V] {
+	n := &bucket[K, V]{freq: freq, prev: b, next: b.next}
+	n.entries.init()
+	b.next.prev = n
+	b.next = n
+	return n
+}
+
+// unlink removes e from its bucket and drops the bucket if it is now empty.
+func (p *lfu[K, V]) unlink(e *entry[K, V]) {
+	b := e.bucket
+	b.entries.remove(e)
+	e.bucket = nil
+	if b.entries.len == 0 {
+		b.prev.next = b.next
+		b.next.prev = b.prev
+	}
+}
+
+func (p *lfu[K, V]) add(e *entry[K, V]) {
+	b := p.root.next
+	if b == &p.root || b.freq != 1 {
+		b = p.insertAfter(&p.root, 1)
+	}
+	b.entries.pushFront(e)
+	e.bucket = b
+}
+
+func (p *lfu[K, V]) touch(e *entry[K, V]) {
+	cur := e.bucket
+	freq := cur.freq + 1
+
+	// Find or create the bucket for the next frequency before unlinking the
+	// entry, because unlinking may drop the current bucket.
+	next := cur.next
+	if next == &p.root || next.freq != freq {
+		next = p.insertAfter(cur, freq)
+	}
+	p.unlink(e)
+	next.entries.pushFront(e)
+	e.bucket = next
+}
+
+func (p *lfu[K, V]) remove(e *entry[K, V]) {
+	p.unlink(e)
+}
+
+func (p *lfu[K, V]) victim() *entry[K, V] {
+	if p.root.next == &p.root {
+		return nil
+	}
+	return p.root.next.entries.back()
+}
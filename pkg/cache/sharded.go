@@ -0,0 +1,122 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"time"
+
+	"go-generics-the-hard-way/pkg/hasher"
+)
+
+// Sharded is a cache split into independently locked shards to reduce lock
+// contention. Each shard enforces its own share of the capacity and its own
+// eviction policy, so eviction is only approximately LRU or LFU across the
+// cache as a whole.
+type Sharded[K comparable, V any] struct {
+	shards []*Cache[K, V]
+	mask   uint64
+	hash   hasher.Func[K]
+}
+
+// NewSharded returns a new cache with the provided number of shards, rounded
+// up to the next power of two. The capacity in opts is divided evenly among
+// the shards. The hash function picks each key's shard, ex. hasher.String
+// or hasher.Integer. NewSharded panics if hash is nil.
+func NewSharded[K comparable, V any](
+	shards int, hash hasher.Func[K], opts Options[K, V]) *Sharded[K, V] {
+
+	if hash == nil {
+		panic("cache: NewSharded called with a nil hash function")
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	if opts.Capacity > 0 {
+		opts.Capacity = (opts.Capacity + n - 1) / n
+	}
+	s := &Sharded[K, V]{
+		shards: make([]*Cache[K, V], n),
+		mask:   uint64(n - 1),
+		hash:   hash,
+	}
+	for i := range s.shards {
+		s.shards[i] = New(opts)
+	}
+	return s
+}
+
+func (s *Sharded[K, V]) shard(key K) *Cache[K, V] {
+	return s.shards[s.hash(key)&s.mask]
+}
+
+// Get returns the value for key. The second return value is false if the key
+// is not cached or has expired.
+func (s *Sharded[K, V]) Get(key K) (V, bool) {
+	return s.shard(key).Get(key)
+}
+
+// Put caches val for key using the default TTL.
+func (s *Sharded[K, V]) Put(key K, val V) {
+	s.shard(key).Put(key, val)
+}
+
+// PutWithTTL caches val for key. The entry expires after ttl, or never if ttl
+// is zero.
+func (s *Sharded[K, V]) PutWithTTL(key K, val V, ttl time.Duration) {
+	s.shard(key).PutWithTTL(key, val, ttl)
+}
+
+// Delete removes key from the cache and returns true if it was present.
+func (s *Sharded[K, V]) Delete(key K) bool {
+	return s.shard(key).Delete(key)
+}
+
+// Len returns the number of entries across all shards.
+func (s *Sharded[K, V]) Len() int {
+	var n int
+	for _, c := range s.shards {
+		n += c.Len()
+	}
+	return n
+}
+
+// RemoveExpired removes all expired entries from every shard and returns how
+// many were removed.
+func (s *Sharded[K, V]) RemoveExpired() int {
+	var n int
+	for _, c := range s.shards {
+		n += c.RemoveExpired()
+	}
+	return n
+}
+
+// Purge removes all entries from every shard without calling OnEvict.
+func (s *Sharded[K, V]) Purge() {
+	for _, c := range s.shards {
+		c.Purge()
+	}
+}
+
+// Stats returns the sum of the counters from every shard.
+func (s *Sharded[K, V]) Stats() Stats {
+	var st Stats
+	for _, c := range s.shards {
+		st = st.add(c.Stats())
+	}
+	return st
+}
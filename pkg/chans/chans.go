@@ -0,0 +1,271 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chans provides generic helpers for channels, the kind of package
+// anticipated by the container patterns lesson.
+//
+// Every function takes a context. The goroutines started by a function exit
+// when its input channels are closed or when the context is canceled,
+// whichever happens first, so canceling the context never leaks goroutines
+// even if nothing is reading from the returned channels.
+//
+// Values are transformed by Stage, which runs a func(T) (U, error) on a
+// bounded pool of workers. Stages that change the type of the values are
+// chained by hand, since Go does not allow a method to declare its own type
+// parameters, so there is no way to write a fluent builder whose Then
+// method turns a Pipeline[T] into a Pipeline[U]. Pipeline covers the common
+// case of chaining stages that all take and return the same type.
+package chans
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// send sends val on out and returns true, or returns false if ctx is done
+// first.
+func send[T any](ctx context.Context, out chan<- T, val T) bool {
+	select {
+	case out <- val:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// recv receives a value from in. The second return value is false if in is
+// closed or ctx is done.
+func recv[T any](ctx context.Context, in <-chan T) (T, bool) {
+	select {
+	case val, ok := <-in:
+		return val, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
+// Generate returns a channel that emits the provided values and then closes.
+func Generate[T any](ctx context.Context, vals ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for i := 0; i < len(vals); i++ {
+			if !send(ctx, out, vals[i]) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Collect receives values from in until it is closed or ctx is done, and
+// returns the values received.
+func Collect[T any](ctx context.Context, in <-chan T) []T {
+	var vals []T
+	for {
+		val, ok := recv(ctx, in)
+		if !ok {
+			return vals
+		}
+		vals = append(vals, val)
+	}
+}
+
+// OrDone returns a channel that emits the values from in until in is closed
+// or ctx is done. It makes it possible to range over a channel without
+// having to also select on ctx.Done().
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			val, ok := recv(ctx, in)
+			if !ok || !send(ctx, out, val) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Merge returns a channel that emits the values from all of the provided
+// channels. The returned channel is closed once all of the provided channels
+// are closed or ctx is done.
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for i := 0; i < len(ins); i++ {
+		go func(in <-chan T) {
+			defer wg.Done()
+			for {
+				val, ok := recv(ctx, in)
+				if !ok || !send(ctx, out, val) {
+					return
+				}
+			}
+		}(ins[i])
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Broadcast returns n channels that each emit every value from in.
+//
+// Please note a value is not received from in until the previous value has
+// been sent on every returned channel, so the slowest reader sets the pace
+// for all of them.
+func Broadcast[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	outs := make([]chan T, n)
+	ros := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		ros[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for i := range outs {
+				close(outs[i])
+			}
+		}()
+		for {
+			val, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			for i := range outs {
+				if !send(ctx, outs[i], val) {
+					return
+				}
+			}
+		}
+	}()
+	return ros
+}
+
+// Tee returns two channels that each emit every value from in. It is
+// Broadcast with n=2.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	outs := Broadcast(ctx, in, 2)
+	return outs[0], outs[1]
+}
+
+// Batch returns a channel that emits the values from in in slices of up to
+// size values. A partial batch is emitted once timeout has elapsed since its
+// first value was received, or when in is closed. A timeout of zero means
+// partial batches are only emitted when in is closed.
+//
+// Batch panics if size is less than one.
+func Batch[T any](
+	ctx context.Context, in <-chan T, size int, timeout time.Duration) <-chan []T {
+
+	if size < 1 {
+		panic("chans: Batch size must be at least one")
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+
+		var (
+			batch []T
+			timer *time.Timer
+			fire  <-chan time.Time
+		)
+		stop := func() {
+			if timer != nil {
+				timer.Stop()
+				timer, fire = nil, nil
+			}
+		}
+		defer stop()
+
+		flush := func() bool {
+			stop()
+			if len(batch) == 0 {
+				return true
+			}
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+
+		for {
+			select {
+			case val, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				if batch == nil {
+					batch = make([]T, 0, size)
+					if timeout > 0 {
+						timer = time.NewTimer(timeout)
+						fire = timer.C
+					}
+				}
+				batch = append(batch, val)
+				if len(batch) >= size && !flush() {
+					return
+				}
+			case <-fire:
+				timer, fire = nil, nil
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Throttle returns a channel that emits the values from in no more often
+// than once per interval.
+func Throttle[T any](
+	ctx context.Context, in <-chan T, interval time.Duration) <-chan T {
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var next time.Time
+		for {
+			val, ok := recv(ctx, in)
+			if !ok {
+				return
+			}
+			if d := time.Until(next); d > 0 {
+				timer := time.NewTimer(d)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+			if !send(ctx, out, val) {
+				return
+			}
+			next = time.Now().Add(interval)
+		}
+	}()
+	return out
+}
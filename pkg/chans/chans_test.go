@@ -0,0 +1,295 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chans_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-generics-the-hard-way/pkg/chans"
+)
+
+// checkLeaks fails the test if the number of goroutines has not returned to
+// its starting value shortly after the test completes.
+func checkLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			after := runtime.NumGoroutine()
+			if after <= before {
+				return
+			}
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<16)
+				buf = buf[:runtime.Stack(buf, true)]
+				t.Errorf("leaked %d goroutines:\n%s", after-before, buf)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+func sorted(vals []int) []int {
+	sort.Ints(vals)
+	return vals
+}
+
+func ExampleMerge() {
+	ctx := context.Background()
+	out := chans.Merge(ctx,
+		chans.Generate(ctx, 1, 2, 3),
+		chans.Generate(ctx, 4, 5),
+		chans.Generate(ctx, 6))
+	fmt.Println(sorted(chans.Collect(ctx, out)))
+	// Output: [1 2 3 4 5 6]
+}
+
+func ExampleBatch() {
+	ctx := context.Background()
+	in := chans.Generate(ctx, "a", "b", "c", "d", "e")
+	for batch := range chans.Batch(ctx, in, 2, 0) {
+		fmt.Println(batch)
+	}
+	// Output:
+	// [a b]
+	// [c d]
+	// [e]
+}
+
+func ExampleStage() {
+	ctx := context.Background()
+
+	// The first stage parses strings into ints, the second squares them.
+	// Each stage has its own pool of workers.
+	in := chans.Generate(ctx, "1", "2", "3", "4")
+	ints, errc1 := chans.Stage(ctx, in, 2, strconv.Atoi)
+	squares, errc2 := chans.Stage(ctx, ints, 2, func(i int) (int, error) {
+		return i * i, nil
+	})
+
+	vals := chans.Collect(ctx, squares)
+	fmt.Println(sorted(vals), chans.FirstError(errc1, errc2))
+	// Output: [1 4 9 16] <nil>
+}
+
+func ExamplePipeline() {
+	ctx := context.Background()
+
+	in := chans.Generate(ctx, 1, 2, 3, 4)
+	out, errc := chans.Pipeline(ctx, in, 2,
+		func(i int) (int, error) { return i * i, nil },
+		func(i int) (int, error) { return i + 1, nil },
+	)
+
+	vals := chans.Collect(ctx, out)
+	fmt.Println(sorted(vals), <-errc)
+	// Output: [2 5 10 17] <nil>
+}
+
+func TestTee(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	a, b := chans.Tee(ctx, chans.Generate(ctx, 1, 2, 3))
+
+	var gotA, gotB []int
+	for a != nil || b != nil {
+		select {
+		case v, ok := <-a:
+			if !ok {
+				a = nil
+				continue
+			}
+			gotA = append(gotA, v)
+		case v, ok := <-b:
+			if !ok {
+				b = nil
+				continue
+			}
+			gotB = append(gotB, v)
+		}
+	}
+	if fmt.Sprint(gotA) != "[1 2 3]" || fmt.Sprint(gotB) != "[1 2 3]" {
+		t.Fatalf("got %v and %v", gotA, gotB)
+	}
+}
+
+func TestBatchTimeout(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	in := make(chan int)
+	out := chans.Batch(ctx, in, 10, 20*time.Millisecond)
+
+	in <- 1
+	in <- 2
+	select {
+	case batch := <-out:
+		if fmt.Sprint(batch) != "[1 2]" {
+			t.Fatalf("got %v", batch)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("partial batch was not flushed")
+	}
+	close(in)
+	if _, ok := <-out; ok {
+		t.Fatal("out should be closed")
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	const interval = 20 * time.Millisecond
+	start := time.Now()
+	vals := chans.Collect(ctx,
+		chans.Throttle(ctx, chans.Generate(ctx, 1, 2, 3, 4), interval))
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Fatalf("4 values took %s, want at least %s", elapsed, 3*interval)
+	}
+	if fmt.Sprint(vals) != "[1 2 3 4]" {
+		t.Fatalf("got %v", vals)
+	}
+}
+
+func TestStageError(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	errBad := errors.New("bad value")
+
+	// Nothing reads from in after the error, so the stage must stop reading
+	// without blocking the producer forever.
+	in := make(chan int)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		defer close(in)
+		for i := 0; ; i++ {
+			select {
+			case in <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	out, errc := chans.Stage(ctx, in, 4, func(i int) (int, error) {
+		if i == 10 {
+			return 0, errBad
+		}
+		return i, nil
+	})
+	for range out {
+	}
+	if err := <-errc; !errors.Is(err, errBad) {
+		t.Fatalf("err = %v, want %v", err, errBad)
+	}
+}
+
+func TestPipelineError(t *testing.T) {
+	checkLeaks(t)
+	ctx := context.Background()
+	errBad := errors.New("bad value")
+
+	in := make(chan int)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		defer close(in)
+		for i := 0; ; i++ {
+			select {
+			case in <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// The error comes from the last stage, so the first stage must be
+	// canceled rather than left blocked on sending to it.
+	out, errc := chans.Pipeline(ctx, in, 4,
+		func(i int) (int, error) { return i, nil },
+		func(i int) (int, error) {
+			if i == 10 {
+				return 0, errBad
+			}
+			return i, nil
+		},
+	)
+	for range out {
+	}
+	if err := <-errc; !errors.Is(err, errBad) {
+		t.Fatalf("err = %v, want %v", err, errBad)
+	}
+}
+
+func TestBatchSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Batch(size=%d) did not panic", size)
+				}
+			}()
+			chans.Batch(context.Background(), make(chan int), size, 0)
+		}()
+	}
+}
+
+// TestCancel verifies every helper releases its goroutines when the context
+// is canceled, even though no one is reading from the returned channels and
+// the input channels are never closed.
+func TestCancel(t *testing.T) {
+	checkLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	in := make(chan int)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case in <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	_ = chans.OrDone(ctx, in)
+	_ = chans.Merge(ctx, in, in)
+	_, _ = chans.Tee(ctx, in)
+	_ = chans.Broadcast(ctx, in, 3)
+	_ = chans.Batch(ctx, in, 2, time.Millisecond)
+	_ = chans.Throttle(ctx, in, time.Hour)
+	_, _ = chans.Stage(ctx, in, 4, func(i int) (string, error) {
+		return strconv.Itoa(i), nil
+	})
+	_, _ = chans.Pipeline(ctx, in, 2, func(i int) (int, error) {
+		return i, nil
+	})
+	_ = chans.Generate(ctx, 1, 2, 3)
+
+	// Give the helpers a chance to block.
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chans
+
+import (
+	"context"
+	"sync"
+)
+
+// StageFn transforms a value of type T into a value of type U.
+type StageFn[T, U any] func(T) (U, error)
+
+// Stage returns a channel that emits the result of calling fn on each value
+// from in, using a pool of workers goroutines. Because the workers run
+// concurrently, the results are not guaranteed to be emitted in the order in
+// which their inputs were received.
+//
+// The first error returned by fn stops the stage and is sent on the returned
+// error channel, which is buffered so the stage never blocks on reporting it.
+// The error channel is closed after the value channel, so callers should
+// drain the values and then receive from the error channel:
+//
+//	out, errc := Stage(ctx, in, 4, fn)
+//	for v := range out {
+//		...
+//	}
+//	if err := <-errc; err != nil {
+//		...
+//	}
+//
+// Stages may be chained by passing the value channel of one stage as the
+// input of the next, and their error channels combined with FirstError.
+func Stage[T, U any](
+	ctx context.Context,
+	in <-chan T,
+	workers int,
+	fn StageFn[T, U]) (<-chan U, <-chan error) {
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	out := make(chan U)
+	errc := make(chan error, 1)
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+	)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				val, ok := recv(ctx, in)
+				if !ok {
+					return
+				}
+				res, err := fn(val)
+				if err != nil {
+					once.Do(func() {
+						errc <- err
+						cancel()
+					})
+					return
+				}
+				if !send(ctx, out, res) {
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(out)
+		close(errc)
+	}()
+	return out, errc
+}
+
+// Pipeline chains a Stage for each of the provided functions, in order, and
+// returns the value channel of the last stage. Each stage has its own pool of
+// workers goroutines.
+//
+// The returned error channel behaves like the one returned by Stage. The
+// first error from any stage is sent on it and cancels every stage, so the
+// earlier stages do not block sending values the later stages no longer
+// read. If no functions are provided the values from in are returned as is.
+func Pipeline[T any](
+	ctx context.Context,
+	in <-chan T,
+	workers int,
+	fns ...StageFn[T, T]) (<-chan T, <-chan error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	out := in
+	errcs := make([]<-chan error, len(fns))
+	for i, fn := range fns {
+		out, errcs[i] = Stage(ctx, out, workers, fn)
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		defer cancel()
+		defer close(errc)
+		var first error
+		for err := range Merge(context.Background(), errcs...) {
+			if first == nil {
+				first = err
+				cancel()
+			}
+		}
+		if first != nil {
+			errc <- first
+		}
+	}()
+	return out, errc
+}
+
+// FirstError waits for all of the provided error channels to close and
+// returns the first error received from any of them, or nil.
+func FirstError(errcs ...<-chan error) error {
+	var first error
+	for err := range Merge(context.Background(), errcs...) {
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
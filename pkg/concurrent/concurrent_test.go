@@ -0,0 +1,161 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrent_test
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/concurrent"
+	"go-generics-the-hard-way/pkg/hasher"
+)
+
+// ID is a type definition with an underlying type of string.
+type ID string
+
+func ExampleMap() {
+	var m concurrent.Map[ID, error]
+	m.Store("acct-1", nil)
+	fmt.Println(m.Load("acct-1"))
+	fmt.Println(m.Load("acct-2"))
+	// Output:
+	// <nil> true
+	// <nil> false
+}
+
+func ExampleSyncMap() {
+	m := concurrent.NewSyncMap[ID, int](8, hasher.String[ID])
+	m.Store("acct-1", 1)
+	fmt.Println(m.LoadOrStore("acct-1", 100))
+	fmt.Println(m.LoadOrStore("acct-2", 2))
+
+	var keys []string
+	m.Range(func(k ID, _ int) bool {
+		keys = append(keys, string(k))
+		return true
+	})
+	sort.Strings(keys)
+	fmt.Println(keys, m.Len(), m.Shards())
+	// Output:
+	// 1 true
+	// 2 false
+	// [acct-1 acct-2] 2 8
+}
+
+func ExampleValue() {
+	type config struct {
+		Host string
+		Port int
+	}
+	var v concurrent.Value[config]
+	fmt.Printf("%+v\n", v.Load())
+	v.Store(config{"local", 80})
+	old := v.Swap(config{"remote", 443})
+	fmt.Printf("%+v %+v\n", old, v.Load())
+	// Output:
+	// {Host: Port:0}
+	// {Host:local Port:80} {Host:remote Port:443}
+}
+
+func ExampleCounter() {
+	c := concurrent.NewCounter[int8]()
+	c.Add(100)
+	c.Add(-30)
+	c.Inc()
+	fmt.Println(c.Load())
+	// Output: 71
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	c := concurrent.NewCounterWithShards[int64](8)
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				c.Inc()
+				c.Add(-2)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := c.Load(); n != -16000 {
+		t.Fatalf("Load() = %d, want -16000", n)
+	}
+	c.Reset()
+	if n := c.Load(); n != 0 {
+		t.Fatalf("Load() = %d after Reset, want 0", n)
+	}
+}
+
+func TestValueUpdate(t *testing.T) {
+	var v concurrent.Value[[]int]
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				v.Update(func(s []int) []int {
+					// Copy the slice so the previous value is not modified.
+					return append(append([]int(nil), s...), g)
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+	if n := len(v.Load()); n != 800 {
+		t.Fatalf("len = %d, want 800", n)
+	}
+}
+
+func TestSyncMapConcurrent(t *testing.T) {
+	m := concurrent.NewSyncMap[int, int](4, hasher.Integer[int])
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				k := g*1000 + i
+				m.Store(k, k)
+				if v, ok := m.Load(k); !ok || v != k {
+					t.Errorf("Load(%d) = %d, %v", k, v, ok)
+				}
+				if i%2 == 0 {
+					m.Delete(k)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if n := m.Len(); n != 4000 {
+		t.Fatalf("Len() = %d, want 4000", n)
+	}
+}
+
+func TestNewSyncMapNilHash(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewSyncMap did not panic with a nil hash function")
+		}
+	}()
+	concurrent.NewSyncMap[int, int](4, nil)
+}
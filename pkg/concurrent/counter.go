@@ -0,0 +1,105 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrent
+
+import (
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+
+	"go-generics-the-hard-way/pkg/constraints"
+	"go-generics-the-hard-way/pkg/hasher"
+)
+
+type counterShard struct {
+	n uint64
+	_ [cacheLineSize - 8]byte
+}
+
+// Counter is an integer counter that is sharded to reduce contention when it
+// is updated by many goroutines at once. Updates are cheap, while Load must
+// sum every shard.
+//
+// The zero value is not usable, please use NewCounter.
+type Counter[T constraints.Integer] struct {
+	shards []counterShard
+	mask   uintptr
+}
+
+// NewCounter returns a new counter with one shard per CPU that may execute Go
+// code at once, i.e. GOMAXPROCS rounded up to the next power of two.
+func NewCounter[T constraints.Integer]() *Counter[T] {
+	return NewCounterWithShards[T](runtime.GOMAXPROCS(0))
+}
+
+// NewCounterWithShards returns a new counter with the provided number of
+// shards, rounded up to the next power of two.
+func NewCounterWithShards[T constraints.Integer](shards int) *Counter[T] {
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	return &Counter[T]{
+		shards: make([]counterShard, n),
+		mask:   uintptr(n - 1),
+	}
+}
+
+// shard returns the shard for the calling goroutine.
+//
+// Go does not expose which CPU a goroutine is running on, so the shard is
+// picked by hashing the address of a variable on the calling goroutine's
+// stack. Every goroutine has its own stack, so concurrent goroutines tend to
+// update different shards, while a single goroutine tends to keep updating
+// the same one.
+func (c *Counter[T]) shard() *counterShard {
+	var x byte
+	h := hasher.Mix(uint64(uintptr(unsafe.Pointer(&x)) >> 10))
+	return &c.shards[uintptr(h)&c.mask]
+}
+
+// Add adds delta, which may be negative for signed types, to the counter.
+func (c *Counter[T]) Add(delta T) {
+	// Converting a negative value to uint64 sign-extends it, so adding it
+	// is the same as subtracting its absolute value.
+	atomic.AddUint64(&c.shard().n, uint64(delta))
+}
+
+// Inc adds one to the counter.
+func (c *Counter[T]) Inc() {
+	atomic.AddUint64(&c.shard().n, 1)
+}
+
+// Load returns the sum of all shards. Like all integer arithmetic in Go, the
+// sum wraps around if it overflows T.
+//
+// Please note the sum is not a consistent snapshot if the counter is being
+// updated concurrently.
+func (c *Counter[T]) Load() T {
+	var sum uint64
+	for i := range c.shards {
+		sum += atomic.LoadUint64(&c.shards[i].n)
+	}
+	return T(sum)
+}
+
+// Reset sets every shard to zero.
+func (c *Counter[T]) Reset() {
+	for i := range c.shards {
+		atomic.StoreUint64(&c.shards[i].n, 0)
+	}
+}
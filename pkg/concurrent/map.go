@@ -0,0 +1,94 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package concurrent provides generic containers that are safe for
+// concurrent use.
+//
+// The sync.Map and atomic.Value types both store their values in the empty
+// interface. This package provides typed wrappers for them, as well as
+// SyncMap, Value, and Counter, which do not box their values at all.
+package concurrent
+
+import (
+	"sync"
+)
+
+// Map is a typed wrapper for sync.Map.
+//
+// Please note Map only provides type safety. The keys and values are still
+// boxed in the empty interface by the underlying sync.Map, so Map has the
+// same performance characteristics as sync.Map. Use SyncMap to avoid boxing.
+//
+// The zero value is an empty map ready to use.
+type Map[K comparable, V any] struct {
+	m sync.Map
+}
+
+// Load returns the value for key. The second return value is false if the
+// key is not in the map.
+func (m *Map[K, V]) Load(key K) (V, bool) {
+	v, ok := m.m.Load(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return unbox[V](v), true
+}
+
+// Store sets the value for key.
+func (m *Map[K, V]) Store(key K, val V) {
+	m.m.Store(key, val)
+}
+
+// LoadOrStore returns the existing value for key if present. Otherwise it
+// stores and returns val. The second return value is true if the value was
+// loaded.
+func (m *Map[K, V]) LoadOrStore(key K, val V) (V, bool) {
+	v, loaded := m.m.LoadOrStore(key, val)
+	return unbox[V](v), loaded
+}
+
+// LoadAndDelete deletes the value for key and returns the previous value.
+// The second return value is false if the key was not in the map.
+func (m *Map[K, V]) LoadAndDelete(key K) (V, bool) {
+	v, ok := m.m.LoadAndDelete(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return unbox[V](v), true
+}
+
+// Delete removes key from the map.
+func (m *Map[K, V]) Delete(key K) {
+	m.m.Delete(key)
+}
+
+// Range calls fn for each key and value in the map, in no particular order.
+// Iteration stops early if fn returns false.
+func (m *Map[K, V]) Range(fn func(key K, val V) bool) {
+	m.m.Range(func(k, v interface{}) bool {
+		return fn(unbox[K](k), unbox[V](v))
+	})
+}
+
+// unbox asserts v is a T. A comma-ok assertion is used because when T is an
+// interface type, storing a nil T stores a nil interface{}, which would cause
+// a plain assertion to panic.
+func unbox[T any](v interface{}) T {
+	t, _ := v.(T)
+	return t
+}
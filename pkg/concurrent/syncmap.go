@@ -0,0 +1,170 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrent
+
+import (
+	"sync"
+
+	"go-generics-the-hard-way/pkg/hasher"
+)
+
+// cacheLineSize is used to pad shards so that two shards are never on the
+// same cache line, which would cause false sharing between the CPUs that are
+// updating them.
+const cacheLineSize = 64
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+	_  [cacheLineSize]byte
+}
+
+// SyncMap is a map split into independently locked shards. Unlike sync.Map,
+// it stores its keys and values as K and V, so they are never boxed.
+//
+// The zero value is not usable, please use NewSyncMap.
+type SyncMap[K comparable, V any] struct {
+	shards []shard[K, V]
+	mask   uint64
+	hash   hasher.Func[K]
+}
+
+// NewSyncMap returns a new map with the provided number of shards, rounded up
+// to the next power of two. The hash function picks each key's shard.
+// NewSyncMap panics if hash is nil.
+//
+// More shards means less contention between goroutines that access
+// different keys, at the cost of memory and a slower Len and Range. A shard
+// count of a small multiple of GOMAXPROCS is a reasonable starting point.
+func NewSyncMap[K comparable, V any](shards int, hash hasher.Func[K]) *SyncMap[K, V] {
+	if hash == nil {
+		panic("concurrent: NewSyncMap called with a nil hash function")
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	m := &SyncMap[K, V]{
+		shards: make([]shard[K, V], n),
+		mask:   uint64(n - 1),
+		hash:   hash,
+	}
+	for i := range m.shards {
+		m.shards[i].m = map[K]V{}
+	}
+	return m
+}
+
+func (m *SyncMap[K, V]) shard(key K) *shard[K, V] {
+	return &m.shards[m.hash(key)&m.mask]
+}
+
+// Shards returns the number of shards.
+func (m *SyncMap[K, V]) Shards() int {
+	return len(m.shards)
+}
+
+// Load returns the value for key. The second return value is false if the
+// key is not in the map.
+func (m *SyncMap[K, V]) Load(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.RLock()
+	v, ok := s.m[key]
+	s.mu.RUnlock()
+	return v, ok
+}
+
+// Store sets the value for key.
+func (m *SyncMap[K, V]) Store(key K, val V) {
+	s := m.shard(key)
+	s.mu.Lock()
+	s.m[key] = val
+	s.mu.Unlock()
+}
+
+// LoadOrStore returns the existing value for key if present. Otherwise it
+// stores and returns val. The second return value is true if the value was
+// loaded.
+func (m *SyncMap[K, V]) LoadOrStore(key K, val V) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = val
+	return val, false
+}
+
+// LoadAndDelete deletes the value for key and returns the previous value.
+// The second return value is false if the key was not in the map.
+func (m *SyncMap[K, V]) LoadAndDelete(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	v, ok := s.m[key]
+	delete(s.m, key)
+	s.mu.Unlock()
+	return v, ok
+}
+
+// Delete removes key from the map.
+func (m *SyncMap[K, V]) Delete(key K) {
+	s := m.shard(key)
+	s.mu.Lock()
+	delete(s.m, key)
+	s.mu.Unlock()
+}
+
+// Len returns the number of keys in the map.
+func (m *SyncMap[K, V]) Len() int {
+	var n int
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Range calls fn for each key and value in the map, in no particular order.
+// Iteration stops early if fn returns false.
+//
+// Each shard is copied before fn is called for its entries, so fn may safely
+// modify the map. Like sync.Map, Range does not correspond to a consistent
+// snapshot of the whole map.
+func (m *SyncMap[K, V]) Range(fn func(key K, val V) bool) {
+	var (
+		keys []K
+		vals []V
+	)
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		keys, vals = keys[:0], vals[:0]
+		for k, v := range s.m {
+			keys = append(keys, k)
+			vals = append(vals, v)
+		}
+		s.mu.RUnlock()
+		for j := range keys {
+			if !fn(keys[j], vals[j]) {
+				return
+			}
+		}
+	}
+}
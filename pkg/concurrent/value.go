@@ -0,0 +1,77 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrent
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Value atomically loads and stores values of type T.
+//
+// Unlike atomic.Value, which boxes each value in the empty interface, Value
+// stores a pointer to a copy of each value, so Load never needs a type
+// assertion. Also unlike atomic.Value, storing the zero value of T is valid.
+//
+// The zero value of Value holds the zero value of T and is ready to use.
+type Value[T any] struct {
+	p unsafe.Pointer // *T
+}
+
+// Load returns the value most recently stored, or the zero value of T if
+// nothing has been stored.
+func (v *Value[T]) Load() T {
+	p := (*T)(atomic.LoadPointer(&v.p))
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// Store sets the value to val.
+func (v *Value[T]) Store(val T) {
+	atomic.StorePointer(&v.p, unsafe.Pointer(&val))
+}
+
+// Swap sets the value to val and returns the previous value.
+func (v *Value[T]) Swap(val T) T {
+	p := (*T)(atomic.SwapPointer(&v.p, unsafe.Pointer(&val)))
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// Update atomically replaces the value with the result of calling fn with
+// the current value, retrying if another goroutine stores a value first. It
+// returns the new value. Because fn may be called more than once, it should
+// not have side effects.
+func (v *Value[T]) Update(fn func(T) T) T {
+	for {
+		old := atomic.LoadPointer(&v.p)
+		var cur T
+		if old != nil {
+			cur = *(*T)(old)
+		}
+		next := fn(cur)
+		if atomic.CompareAndSwapPointer(&v.p, old, unsafe.Pointer(&next)) {
+			return next
+		}
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package constraints defines the type constraints shared by the packages in
+// this repository. The constraints are the same ones built up over the course
+// of "Getting started" and "Getting going", just collected in one place.
+package constraints
+
+// Signed expresses a type constraint satisfied by any signed integer type.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned expresses a type constraint satisfied by any unsigned integer type.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Integer expresses a type constraint satisfied by any integer type.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// Float expresses a type constraint satisfied by any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Complex expresses a type constraint satisfied by any complex type.
+type Complex interface {
+	~complex64 | ~complex128
+}
+
+// Numeric expresses a type constraint satisfied by any numeric type.
+type Numeric interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~float32 | ~float64 |
+		~complex64 | ~complex128
+}
+
+// Real expresses a type constraint satisfied by any numeric type that is not
+// complex, i.e. any numeric type that supports the ordering operators.
+type Real interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~float32 | ~float64
+}
+
+// Ordered expresses a type constraint satisfied by any type that supports the
+// operators < <= >= >.
+//
+// Please note Ordered is Numeric without the complex types, which cannot be
+// ordered, plus ~string.
+type Ordered interface {
+	Real | ~string
+}
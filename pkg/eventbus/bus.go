@@ -0,0 +1,291 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventbus provides Bus, an event bus on which the topics are types.
+//
+// Subscribe[T] and Publish[T] key the topic on the instantiated type. As
+// "Internals" shows, Go does not erase type parameters: a topicKey[int] and a
+// topicKey[string] are distinct types at runtime, so a topic can be found by
+// storing a topicKey[T] in an interface and using it as a map key, and every
+// handler receives its events as a T rather than as an interface{} that must
+// be asserted back to the type that was published.
+package eventbus
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Mode is how a bus delivers events to its subscribers.
+type Mode int
+
+const (
+	// Sync delivers an event on the goroutine that publishes it, calling the
+	// handlers in the order in which they subscribed. Publish returns after
+	// every handler has returned.
+	Sync Mode = iota
+
+	// Async queues an event for each subscriber and returns without waiting
+	// for the handlers. A subscriber's handler is called on a goroutine that
+	// runs while the subscriber has queued events.
+	Async
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Sync:
+		return "Sync"
+	case Async:
+		return "Async"
+	}
+	return "Mode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// Bus delivers published events to the subscribers for the event's type.
+//
+// Each subscriber receives the events published to it in order: an event
+// published after another, by the same goroutine or after synchronizing with
+// the goroutine that published the other, is delivered to the subscriber
+// after it. In Async mode a subscriber's handler is never called
+// concurrently with itself. In Sync mode the handler is called on the
+// publishing goroutine, so events published concurrently may be handled
+// concurrently.
+//
+// The zero value is not usable, please use New.
+type Bus struct {
+	mode Mode
+
+	mu     sync.RWMutex
+	topics map[interface{}]interface{} // topicKey[T] -> *topic[T]
+
+	pendingMu   sync.Mutex
+	pendingCond sync.Cond
+	pending     int
+}
+
+// New returns a new bus that delivers events in the given mode.
+func New(mode Mode) *Bus {
+	b := &Bus{mode: mode, topics: map[interface{}]interface{}{}}
+	b.pendingCond.L = &b.pendingMu
+	return b
+}
+
+// Mode returns the bus's delivery mode.
+func (b *Bus) Mode() Mode {
+	return b.mode
+}
+
+// Wait blocks until every event queued for an Async subscriber has been
+// handled, including any events the handlers publish while Wait is blocked.
+// Wait returns immediately on a Sync bus.
+func (b *Bus) Wait() {
+	b.pendingMu.Lock()
+	for b.pending > 0 {
+		b.pendingCond.Wait()
+	}
+	b.pendingMu.Unlock()
+}
+
+func (b *Bus) addPending(n int) {
+	if n == 0 {
+		return
+	}
+	b.pendingMu.Lock()
+	b.pending += n
+	if b.pending == 0 {
+		b.pendingCond.Broadcast()
+	}
+	b.pendingMu.Unlock()
+}
+
+// topicKey identifies the topic for events of type T.
+type topicKey[T any] struct{}
+
+// topic is the list of subscribers for events of type T.
+type topic[T any] struct {
+	mu sync.RWMutex
+
+	// subs is replaced, never modified, when a subscriber is added or
+	// removed, so Publish can deliver to a snapshot without holding mu.
+	subs []*subscriber[T]
+}
+
+// topicFor returns the topic for events of type T. If there is no such topic
+// and create is false, nil is returned.
+func topicFor[T any](b *Bus, create bool) *topic[T] {
+	b.mu.RLock()
+	t, ok := b.topics[topicKey[T]{}]
+	b.mu.RUnlock()
+	if ok || !create {
+		t, _ := t.(*topic[T])
+		return t
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.topics[topicKey[T]{}]; ok {
+		return t.(*topic[T])
+	}
+	nt := &topic[T]{}
+	b.topics[topicKey[T]{}] = nt
+	return nt
+}
+
+func (t *topic[T]) remove(s *subscriber[T]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	subs := make([]*subscriber[T], 0, len(t.subs))
+	for _, ts := range t.subs {
+		if ts != s {
+			subs = append(subs, ts)
+		}
+	}
+	t.subs = subs
+}
+
+type subscriber[T any] struct {
+	bus    *Bus
+	fn     func(T)
+	closed int32 // accessed atomically
+
+	// The remaining fields are only used in Async mode.
+	mu      sync.Mutex
+	queue   []T
+	head    int  // the index of the next event in queue
+	running bool // whether a goroutine is draining queue
+}
+
+// deliver delivers v to the subscriber and reports whether it was delivered,
+// or queued for delivery, before the subscriber unsubscribed.
+func (s *subscriber[T]) deliver(v T) bool {
+	if s.bus.mode == Sync {
+		if atomic.LoadInt32(&s.closed) != 0 {
+			return false
+		}
+		s.fn(v)
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if atomic.LoadInt32(&s.closed) != 0 {
+		return false
+	}
+	s.queue = append(s.queue, v)
+	s.bus.addPending(1)
+	if !s.running {
+		s.running = true
+		go s.drain()
+	}
+	return true
+}
+
+// drain calls the handler for each queued event, one at a time, until the
+// queue is empty. An event is taken from the queue while holding mu and the
+// handler is then called without it, so the handler may publish to or
+// unsubscribe from the bus. This is why a handler may be called after close
+// has returned, for the event that was taken from the queue before it.
+func (s *subscriber[T]) drain() {
+	var zero T
+	for {
+		s.mu.Lock()
+		if s.head == len(s.queue) {
+			s.queue, s.head = s.queue[:0], 0
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		v := s.queue[s.head]
+		s.queue[s.head] = zero
+		s.head++
+		s.mu.Unlock()
+
+		s.fn(v)
+		s.bus.addPending(-1)
+	}
+}
+
+// close stops delivery to the subscriber and discards its queued events.
+func (s *subscriber[T]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	atomic.StoreInt32(&s.closed, 1)
+	dropped := len(s.queue) - s.head
+	s.queue, s.head = nil, 0
+	s.bus.addPending(-dropped)
+}
+
+// Subscription is returned by Subscribe and is used to unsubscribe.
+type Subscription struct {
+	once        sync.Once
+	unsubscribe func()
+}
+
+// Unsubscribe removes the subscription from the bus and discards any events
+// queued for it, so no further events are delivered to the handler.
+//
+// Unsubscribe does not wait for the handler. A call that is in progress when
+// Unsubscribe is called, or whose event was already being delivered, ex.
+// taken from the queue in Async mode or passed to Publish in Sync mode, may
+// still run after Unsubscribe returns. On an Async bus, call Wait after
+// Unsubscribe to wait for such a call to return.
+//
+// It is safe to call Unsubscribe more than once, and from within the handler.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(s.unsubscribe)
+}
+
+// Subscribe calls fn for every event of type T published to the bus until
+// the returned subscription is unsubscribed.
+//
+// The topic is the exact type T, so a handler for a type definition such as
+// `type ID string` does not receive events published as a string.
+func Subscribe[T any](b *Bus, fn func(T)) *Subscription {
+	t := topicFor[T](b, true)
+	s := &subscriber[T]{bus: b, fn: fn}
+
+	t.mu.Lock()
+	subs := make([]*subscriber[T], len(t.subs), len(t.subs)+1)
+	copy(subs, t.subs)
+	t.subs = append(subs, s)
+	t.mu.Unlock()
+
+	return &Subscription{unsubscribe: func() {
+		t.remove(s)
+		s.close()
+	}}
+}
+
+// Publish delivers v to the subscribers for type T and returns the number of
+// subscribers it was delivered, or in Async mode queued, to.
+func Publish[T any](b *Bus, v T) int {
+	t := topicFor[T](b, false)
+	if t == nil {
+		return 0
+	}
+	t.mu.RLock()
+	subs := t.subs
+	t.mu.RUnlock()
+
+	n := 0
+	for _, s := range subs {
+		if s.deliver(v) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/eventbus"
+)
+
+// ID is a type definition with an underlying type of string.
+type ID string
+
+type OrderPlaced struct {
+	ID    ID
+	Total float64
+}
+
+func ExampleSubscribe() {
+	bus := eventbus.New(eventbus.Sync)
+
+	sub := eventbus.Subscribe(bus, func(e OrderPlaced) {
+		fmt.Printf("order %s: %.2f\n", e.ID, e.Total)
+	})
+	eventbus.Subscribe(bus, func(id ID) {
+		fmt.Println("id", id)
+	})
+
+	eventbus.Publish(bus, OrderPlaced{ID: "o-1", Total: 9.5})
+	eventbus.Publish(bus, ID("o-2"))
+
+	// The topic is the exact type, so a string is not an ID.
+	fmt.Println(eventbus.Publish(bus, "o-3"))
+
+	sub.Unsubscribe()
+	fmt.Println(eventbus.Publish(bus, OrderPlaced{ID: "o-4"}))
+	// Output:
+	// order o-1: 9.50
+	// id o-2
+	// 0
+	// 0
+}
+
+func ExampleBus_Wait() {
+	bus := eventbus.New(eventbus.Async)
+
+	var sum int
+	eventbus.Subscribe(bus, func(n int) {
+		sum += n
+	})
+	for i := 1; i <= 100; i++ {
+		eventbus.Publish(bus, i)
+	}
+	bus.Wait()
+	fmt.Println(sum)
+	// Output: 5050
+}
+
+func TestAsyncOrder(t *testing.T) {
+	bus := eventbus.New(eventbus.Async)
+
+	const n = 10000
+	got := make([][]int, 4)
+	for i := range got {
+		i := i
+		eventbus.Subscribe(bus, func(v int) {
+			got[i] = append(got[i], v)
+		})
+	}
+	for i := 0; i < n; i++ {
+		if delivered := eventbus.Publish(bus, i); delivered != len(got) {
+			t.Fatalf("Publish delivered to %d subscribers, want %d", delivered, len(got))
+		}
+	}
+	bus.Wait()
+	for i, vals := range got {
+		if len(vals) != n {
+			t.Fatalf("subscriber %d got %d events, want %d", i, len(vals), n)
+		}
+		for j, v := range vals {
+			if v != j {
+				t.Fatalf("subscriber %d got event %d at %d", i, v, j)
+			}
+		}
+	}
+}
+
+func TestSyncOrder(t *testing.T) {
+	bus := eventbus.New(eventbus.Sync)
+	var got []string
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		eventbus.Subscribe(bus, func(v int) {
+			got = append(got, fmt.Sprint(name, v))
+		})
+	}
+	eventbus.Publish(bus, 1)
+	eventbus.Publish(bus, 2)
+	if want := "[a1 b1 c1 a2 b2 c2]"; fmt.Sprint(got) != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestUnsubscribeDiscardsQueuedEvents(t *testing.T) {
+	bus := eventbus.New(eventbus.Async)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var got []int
+	sub := eventbus.Subscribe(bus, func(v int) {
+		if v == 0 {
+			close(started)
+			<-release
+		}
+		got = append(got, v)
+	})
+	for i := 0; i < 10; i++ {
+		eventbus.Publish(bus, i)
+	}
+	<-started
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	close(release)
+	bus.Wait()
+
+	if len(got) != 1 {
+		t.Errorf("got %v, want only the event in progress", got)
+	}
+	if n := eventbus.Publish(bus, 10); n != 0 {
+		t.Errorf("Publish delivered to %d subscribers after Unsubscribe", n)
+	}
+}
+
+func TestHandlersMayUseTheBus(t *testing.T) {
+	for _, mode := range []eventbus.Mode{eventbus.Sync, eventbus.Async} {
+		t.Run(mode.String(), func(t *testing.T) {
+			bus := eventbus.New(mode)
+
+			var (
+				mu    sync.Mutex
+				count int
+				sub   *eventbus.Subscription
+			)
+			sub = eventbus.Subscribe(bus, func(n int) {
+				if n == 0 {
+					sub.Unsubscribe()
+					return
+				}
+				eventbus.Subscribe(bus, func(ID) {
+					mu.Lock()
+					count++
+					mu.Unlock()
+				})
+				eventbus.Publish(bus, n-1)
+			})
+			eventbus.Publish(bus, 3)
+			bus.Wait()
+			eventbus.Publish(bus, ID("x"))
+			bus.Wait()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if count != 3 {
+				t.Errorf("got %d ID events, want 3", count)
+			}
+			if n := eventbus.Publish(bus, 3); n != 0 {
+				t.Errorf("Publish delivered to %d subscribers after Unsubscribe", n)
+			}
+		})
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	bus := eventbus.New(eventbus.Async)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sub := eventbus.Subscribe(bus, func(int) {})
+				eventbus.Publish(bus, j)
+				eventbus.Publish(bus, fmt.Sprint(j))
+				sub.Unsubscribe()
+			}
+		}()
+	}
+	wg.Wait()
+	bus.Wait()
+}
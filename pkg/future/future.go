@@ -0,0 +1,255 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package future provides a generic Future[T] and combinators for composing
+// them, as well as a typed, errgroup-style Group[T].
+//
+// Before generics the usual way to collect the results of concurrent work was
+// a chan interface{} and a type assertion for every value received, which
+// boxes every result. A Future[T] and a Group[T] deliver their results as T.
+package future
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Future is the eventual result of an asynchronous operation.
+type Future[T any] struct {
+	done chan struct{}
+	once sync.Once
+	val  T
+	err  error
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// complete settles the future. Only the first call has any effect.
+func (f *Future[T]) complete(val T, err error) {
+	f.once.Do(func() {
+		f.val, f.err = val, err
+		close(f.done)
+	})
+}
+
+// New returns a pending future and a function that settles it. Only the
+// first call to the function has any effect.
+func New[T any]() (*Future[T], func(T, error)) {
+	f := newFuture[T]()
+	return f, f.complete
+}
+
+// Go runs fn in a new goroutine and returns a future for its result.
+func Go[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
+	f := newFuture[T]()
+	go func() {
+		f.complete(fn(ctx))
+	}()
+	return f
+}
+
+// Resolved returns a future that has already succeeded with val.
+func Resolved[T any](val T) *Future[T] {
+	f := newFuture[T]()
+	f.complete(val, nil)
+	return f
+}
+
+// Rejected returns a future that has already failed with err.
+func Rejected[T any](err error) *Future[T] {
+	f := newFuture[T]()
+	var zero T
+	f.complete(zero, err)
+	return f
+}
+
+// Done returns a channel that is closed when the future has settled.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await blocks until the future settles or ctx is done, and returns the
+// future's result or ctx's error.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Then returns a future for the result of calling fn with the value of f
+// once f succeeds. If f fails, fn is not called and the returned future
+// fails with the same error.
+//
+// Please note Then is a function and not a method on Future[T] because
+// methods may not declare their own type parameters, and U is not known
+// until Then is called.
+func Then[T, U any](f *Future[T], fn func(T) (U, error)) *Future[U] {
+	u := newFuture[U]()
+	go func() {
+		<-f.done
+		if f.err != nil {
+			var zero U
+			u.complete(zero, f.err)
+			return
+		}
+		u.complete(fn(f.val))
+	}()
+	return u
+}
+
+// WithTimeout returns a future that settles with the result of f, or fails
+// with context.DeadlineExceeded if f has not settled within d.
+func WithTimeout[T any](f *Future[T], d time.Duration) *Future[T] {
+	t := newFuture[T]()
+	go func() {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-f.done:
+			t.complete(f.val, f.err)
+		case <-timer.C:
+			var zero T
+			t.complete(zero, context.DeadlineExceeded)
+		}
+	}()
+	return t
+}
+
+// All returns a future that succeeds with the values of all of the provided
+// futures, in the same order, once they have all succeeded. It fails as soon
+// as any of the futures fails.
+func All[T any](fs ...*Future[T]) *Future[[]T] {
+	all := newFuture[[]T]()
+	if len(fs) == 0 {
+		all.complete([]T{}, nil)
+		return all
+	}
+	vals := make([]T, len(fs))
+	var wg sync.WaitGroup
+	wg.Add(len(fs))
+	for i := range fs {
+		go func(i int) {
+			defer wg.Done()
+			f := fs[i]
+			select {
+			case <-f.done:
+			case <-all.done:
+				// Another future failed, so stop waiting.
+				return
+			}
+			if f.err != nil {
+				all.complete(nil, f.err)
+				return
+			}
+			vals[i] = f.val
+		}(i)
+	}
+	go func() {
+		wg.Wait()
+		all.complete(vals, nil)
+	}()
+	return all
+}
+
+// ErrNoFutures is the error from Any and Race when they are called without
+// any futures, since there is nothing that could ever settle them.
+var ErrNoFutures = errors.New("future: no futures provided")
+
+// AnyError is the error from Any when all of the futures failed.
+type AnyError struct {
+
+	// Errors are the errors from each of the futures, in the same order as
+	// the futures.
+	Errors []error
+}
+
+func (e *AnyError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		parts[i] = err.Error()
+	}
+	return fmt.Sprintf("future: all %d futures failed: %s",
+		len(e.Errors), strings.Join(parts, "; "))
+}
+
+// Any returns a future that succeeds with the value of the first of the
+// provided futures to succeed. If they all fail then it fails with an
+// *AnyError. If no futures are provided it fails with ErrNoFutures.
+func Any[T any](fs ...*Future[T]) *Future[T] {
+	first := newFuture[T]()
+	if len(fs) == 0 {
+		var zero T
+		first.complete(zero, ErrNoFutures)
+		return first
+	}
+	errs := make([]error, len(fs))
+	var wg sync.WaitGroup
+	wg.Add(len(fs))
+	for i := range fs {
+		go func(i int) {
+			defer wg.Done()
+			f := fs[i]
+			select {
+			case <-f.done:
+			case <-first.done:
+				return
+			}
+			if f.err != nil {
+				errs[i] = f.err
+				return
+			}
+			first.complete(f.val, nil)
+		}(i)
+	}
+	go func() {
+		wg.Wait()
+		var zero T
+		first.complete(zero, &AnyError{Errors: errs})
+	}()
+	return first
+}
+
+// Race returns a future that settles with the result of the first of the
+// provided futures to settle, whether it succeeded or failed. If no futures
+// are provided it fails with ErrNoFutures.
+func Race[T any](fs ...*Future[T]) *Future[T] {
+	first := newFuture[T]()
+	if len(fs) == 0 {
+		var zero T
+		first.complete(zero, ErrNoFutures)
+		return first
+	}
+	for i := range fs {
+		go func(f *Future[T]) {
+			select {
+			case <-f.done:
+				first.complete(f.val, f.err)
+			case <-first.done:
+			}
+		}(fs[i])
+	}
+	return first
+}
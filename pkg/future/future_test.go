@@ -0,0 +1,177 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package future_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-generics-the-hard-way/pkg/future"
+)
+
+func after[T any](d time.Duration, val T, err error) *future.Future[T] {
+	return future.Go(context.Background(), func(context.Context) (T, error) {
+		time.Sleep(d)
+		return val, err
+	})
+}
+
+func ExampleThen() {
+	ctx := context.Background()
+	s := future.Resolved("42")
+	i := future.Then(s, strconv.Atoi)
+	d := future.Then(i, func(i int) (float64, error) {
+		return float64(i) / 8, nil
+	})
+	fmt.Println(d.Await(ctx))
+	// Output: 5.25 <nil>
+}
+
+func ExampleAll() {
+	ctx := context.Background()
+	all := future.All(
+		after(20*time.Millisecond, "a", nil),
+		after(0, "b", nil),
+		after(10*time.Millisecond, "c", nil))
+	fmt.Println(all.Await(ctx))
+	// Output: [a b c] <nil>
+}
+
+func ExampleAny() {
+	ctx := context.Background()
+	first := future.Any(
+		future.Rejected[int](errors.New("down")),
+		after(10*time.Millisecond, 2, nil),
+		after(time.Second, 3, nil))
+	fmt.Println(first.Await(ctx))
+
+	none := future.Any(
+		future.Rejected[int](errors.New("down")),
+		future.Rejected[int](errors.New("also down")))
+	fmt.Println(none.Await(ctx))
+	// Output:
+	// 2 <nil>
+	// 0 future: all 2 futures failed: down; also down
+}
+
+func ExampleRace() {
+	ctx := context.Background()
+	errSlow := errors.New("slow")
+	first := future.Race(
+		after(time.Second, "late", nil),
+		after(0, "", errSlow))
+	fmt.Println(first.Await(ctx))
+	// Output: slow
+}
+
+func ExampleWithTimeout() {
+	ctx := context.Background()
+	f := future.WithTimeout(after(time.Second, 1, nil), 10*time.Millisecond)
+	fmt.Println(f.Await(ctx))
+	// Output: 0 context deadline exceeded
+}
+
+func ExampleNew() {
+	ctx := context.Background()
+	f, resolve := future.New[string]()
+	go resolve("hello", nil)
+	fmt.Println(f.Await(ctx))
+	// Output: hello <nil>
+}
+
+func ExampleGroup() {
+	g, ctx := future.WithContext[int](context.Background())
+	for _, s := range []string{"3", "1", "2"} {
+		s := s
+		g.Go(func(context.Context) (int, error) {
+			return strconv.Atoi(s)
+		})
+	}
+	fmt.Println(g.Wait())
+	fmt.Println(ctx.Err())
+	// Output:
+	// [3 1 2] <nil>
+	// context canceled
+}
+
+func TestGroupCancel(t *testing.T) {
+	errBoom := errors.New("boom")
+	g, _ := future.WithContext[int](context.Background())
+	g.Go(func(ctx context.Context) (int, error) {
+		// This function only returns once the group's context is canceled
+		// by the failure below.
+		<-ctx.Done()
+		return 1, nil
+	})
+	g.Go(func(context.Context) (int, error) {
+		return 0, errBoom
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := g.Wait(); !errors.Is(err, errBoom) {
+			t.Errorf("err = %v, want %v", err, errBoom)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the group's context was not canceled")
+	}
+}
+
+func TestAllFailsFast(t *testing.T) {
+	errBoom := errors.New("boom")
+	start := time.Now()
+	_, err := future.All(
+		after(time.Minute, 1, nil),
+		after(0, 0, errBoom)).Await(context.Background())
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want %v", err, errBoom)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Fatal("All did not fail fast")
+	}
+}
+
+func TestAwaitContext(t *testing.T) {
+	f, _ := future.New[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Await(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestNoFutures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := future.Any[int]().Await(ctx); !errors.Is(err, future.ErrNoFutures) {
+		t.Fatalf("Any() err = %v, want %v", err, future.ErrNoFutures)
+	}
+	if _, err := future.Race[int]().Await(ctx); !errors.Is(err, future.ErrNoFutures) {
+		t.Fatalf("Race() err = %v, want %v", err, future.ErrNoFutures)
+	}
+	if vals, err := future.All[int]().Await(ctx); len(vals) != 0 || err != nil {
+		t.Fatalf("All() = %v, %v", vals, err)
+	}
+}
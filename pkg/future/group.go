@@ -0,0 +1,94 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package future
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs functions concurrently and collects their results in the order
+// in which the functions were launched. It is modeled after errgroup.Group,
+// except each function returns a value of type T.
+//
+// The zero value is a usable group that is not bound to a context.
+type Group[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg sync.WaitGroup
+
+	mu   sync.Mutex
+	vals []T
+	err  error
+}
+
+// WithContext returns a new group and a context derived from ctx. The
+// context is canceled the first time a function in the group returns an
+// error, or when Wait returns, whichever occurs first.
+func WithContext[T any](ctx context.Context) (*Group[T], context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group[T]{ctx: ctx, cancel: cancel}, ctx
+}
+
+// Go runs fn in a new goroutine. The function is passed the group's context,
+// or context.Background if the group was not created with WithContext.
+func (g *Group[T]) Go(fn func(context.Context) (T, error)) {
+	g.mu.Lock()
+	i := len(g.vals)
+	var zero T
+	g.vals = append(g.vals, zero)
+	g.mu.Unlock()
+
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		val, err := fn(ctx)
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if err != nil {
+			if g.err == nil {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel()
+				}
+			}
+			return
+		}
+		g.vals[i] = val
+	}()
+}
+
+// Wait blocks until all of the functions have returned, and then returns
+// their results in the order in which they were launched along with the
+// first error, if any. The results of functions that failed are the zero
+// value of T.
+func (g *Group[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.vals, g.err
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package graph provides a generic graph with typed vertices and weighted
+// edges.
+//
+// Vertices and edges are visited in the order in which they were added, so
+// every traversal is deterministic.
+package graph
+
+import (
+	"go-generics-the-hard-way/pkg/constraints"
+)
+
+type edge[W constraints.Numeric] struct {
+	to int
+	w  W
+}
+
+// Graph is a directed or undirected graph with vertices of type V and edge
+// weights of type W.
+//
+// The zero value is not usable, please use NewDirected or NewUndirected.
+type Graph[V comparable, W constraints.Numeric] struct {
+	directed bool
+	vertices []V
+	index    map[V]int
+	edges    [][]edge[W]
+}
+
+// NewDirected returns a new, empty directed graph.
+func NewDirected[V comparable, W constraints.Numeric]() *Graph[V, W] {
+	return &Graph[V, W]{directed: true, index: map[V]int{}}
+}
+
+// NewUndirected returns a new, empty undirected graph.
+func NewUndirected[V comparable, W constraints.Numeric]() *Graph[V, W] {
+	return &Graph[V, W]{index: map[V]int{}}
+}
+
+// Directed returns true if the graph is directed.
+func (g *Graph[V, W]) Directed() bool {
+	return g.directed
+}
+
+// Order returns the number of vertices in the graph.
+func (g *Graph[V, W]) Order() int {
+	return len(g.vertices)
+}
+
+// AddVertex adds v to the graph if it is not already present.
+func (g *Graph[V, W]) AddVertex(v V) {
+	g.vertex(v)
+}
+
+// HasVertex returns true if v is in the graph.
+func (g *Graph[V, W]) HasVertex(v V) bool {
+	_, ok := g.index[v]
+	return ok
+}
+
+// Vertices returns the vertices in the order in which they were added.
+func (g *Graph[V, W]) Vertices() []V {
+	vertices := make([]V, len(g.vertices))
+	copy(vertices, g.vertices)
+	return vertices
+}
+
+// AddEdge adds an edge from one vertex to another with weight w, adding the
+// vertices if they are not already present. If the edge already exists its
+// weight is replaced. An undirected edge is added in both directions.
+func (g *Graph[V, W]) AddEdge(from, to V, w W) {
+	i, j := g.vertex(from), g.vertex(to)
+	g.setEdge(i, j, w)
+	if !g.directed && i != j {
+		g.setEdge(j, i, w)
+	}
+}
+
+// Weight returns the weight of the edge from one vertex to another. The
+// second return value is false if there is no such edge.
+func (g *Graph[V, W]) Weight(from, to V) (W, bool) {
+	i, ok := g.index[from]
+	if ok {
+		j, ok := g.index[to]
+		if ok {
+			for _, e := range g.edges[i] {
+				if e.to == j {
+					return e.w, true
+				}
+			}
+		}
+	}
+	var zero W
+	return zero, false
+}
+
+// HasEdge returns true if there is an edge from one vertex to another.
+func (g *Graph[V, W]) HasEdge(from, to V) bool {
+	_, ok := g.Weight(from, to)
+	return ok
+}
+
+// Neighbors returns the vertices reachable from v by a single edge, in the
+// order in which the edges were added.
+func (g *Graph[V, W]) Neighbors(v V) []V {
+	i, ok := g.index[v]
+	if !ok {
+		return nil
+	}
+	neighbors := make([]V, len(g.edges[i]))
+	for k, e := range g.edges[i] {
+		neighbors[k] = g.vertices[e.to]
+	}
+	return neighbors
+}
+
+// TotalWeight returns the sum of the weights of all edges. Each undirected
+// edge is counted once.
+func (g *Graph[V, W]) TotalWeight() W {
+	var sum W
+	for i := range g.edges {
+		for _, e := range g.edges[i] {
+			if g.directed || i <= e.to {
+				sum += e.w
+			}
+		}
+	}
+	return sum
+}
+
+// BFS visits the vertices reachable from start in breadth-first order.
+// Visiting stops early if fn returns false.
+func (g *Graph[V, W]) BFS(start V, fn func(V) bool) {
+	s, ok := g.index[start]
+	if !ok {
+		return
+	}
+	seen := make([]bool, len(g.vertices))
+	seen[s] = true
+	queue := []int{s}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		if !fn(g.vertices[i]) {
+			return
+		}
+		for _, e := range g.edges[i] {
+			if !seen[e.to] {
+				seen[e.to] = true
+				queue = append(queue, e.to)
+			}
+		}
+	}
+}
+
+// DFS visits the vertices reachable from start in depth-first pre-order.
+// Visiting stops early if fn returns false.
+func (g *Graph[V, W]) DFS(start V, fn func(V) bool) {
+	s, ok := g.index[start]
+	if !ok {
+		return
+	}
+	seen := make([]bool, len(g.vertices))
+	var visit func(int) bool
+	visit = func(i int) bool {
+		seen[i] = true
+		if !fn(g.vertices[i]) {
+			return false
+		}
+		for _, e := range g.edges[i] {
+			if !seen[e.to] && !visit(e.to) {
+				return false
+			}
+		}
+		return true
+	}
+	visit(s)
+}
+
+// ConnectedComponents returns the graph's connected components, each in the
+// order in which its vertices were added. The components of a directed graph
+// are its weakly connected components, i.e. edge direction is ignored.
+func (g *Graph[V, W]) ConnectedComponents() [][]V {
+	// Union-find over the vertex indices.
+	parent := make([]int, len(g.vertices))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range g.edges {
+		for _, e := range g.edges[i] {
+			if a, b := find(i), find(e.to); a != b {
+				if b < a {
+					a, b = b, a
+				}
+				parent[b] = a
+			}
+		}
+	}
+
+	var components [][]V
+	index := map[int]int{}
+	for i, v := range g.vertices {
+		root := find(i)
+		k, ok := index[root]
+		if !ok {
+			k = len(components)
+			index[root] = k
+			components = append(components, nil)
+		}
+		components[k] = append(components[k], v)
+	}
+	return components
+}
+
+func (g *Graph[V, W]) vertex(v V) int {
+	i, ok := g.index[v]
+	if !ok {
+		i = len(g.vertices)
+		g.index[v] = i
+		g.vertices = append(g.vertices, v)
+		g.edges = append(g.edges, nil)
+	}
+	return i
+}
+
+func (g *Graph[V, W]) setEdge(i, j int, w W) {
+	for k := range g.edges[i] {
+		if g.edges[i][k].to == j {
+			g.edges[i][k].w = w
+			return
+		}
+	}
+	g.edges[i] = append(g.edges[i], edge[W]{to: j, w: w})
+}
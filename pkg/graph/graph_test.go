@@ -0,0 +1,118 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph_test
+
+import (
+	"errors"
+	"fmt"
+
+	"go-generics-the-hard-way/pkg/graph"
+)
+
+// Package is a type definition with an underlying type of string.
+type Package string
+
+func ExampleGraph_TopologicalSort() {
+	// An edge from a to b means a must be installed before b.
+	g := graph.NewDirected[Package, int]()
+	g.AddEdge("libc", "openssl", 1)
+	g.AddEdge("libc", "zlib", 1)
+	g.AddEdge("zlib", "openssl", 1)
+	g.AddEdge("openssl", "curl", 1)
+	g.AddVertex("vim")
+
+	fmt.Println(g.TopologicalSort())
+
+	g.AddEdge("curl", "libc", 1)
+	_, err := g.TopologicalSort()
+	var cerr *graph.CycleError[Package]
+	fmt.Println(errors.As(err, &cerr), err)
+	// Output:
+	// [libc vim zlib openssl curl] <nil>
+	// true graph: cycle detected: libc -> openssl -> curl -> libc
+}
+
+func ExampleGraph_BFS() {
+	g := graph.NewUndirected[int, float64]()
+	g.AddEdge(1, 2, 0)
+	g.AddEdge(1, 3, 0)
+	g.AddEdge(2, 4, 0)
+	g.AddEdge(3, 4, 0)
+	g.AddEdge(4, 5, 0)
+
+	var bfs, dfs []int
+	g.BFS(1, func(v int) bool {
+		bfs = append(bfs, v)
+		return true
+	})
+	g.DFS(1, func(v int) bool {
+		dfs = append(dfs, v)
+		return true
+	})
+	fmt.Println(bfs)
+	fmt.Println(dfs)
+	// Output:
+	// [1 2 3 4 5]
+	// [1 2 4 3 5]
+}
+
+func ExampleGraph_ConnectedComponents() {
+	g := graph.NewUndirected[string, uint8]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("c", "d", 1)
+	g.AddEdge("b", "e", 1)
+	g.AddVertex("f")
+	fmt.Println(g.ConnectedComponents())
+	// Output: [[a b e] [c d] [f]]
+}
+
+func ExampleGraph_TotalWeight() {
+	// TotalWeight only needs addition, so it works with any Numeric weight,
+	// even complex ones.
+	g := graph.NewUndirected[string, complex128]()
+	g.AddEdge("a", "b", 1+2i)
+	g.AddEdge("b", "c", 3+4i)
+	fmt.Println(g.TotalWeight())
+	// Output: (4+6i)
+}
+
+func ExampleShortestPaths() {
+	g := graph.NewDirected[string, float64]()
+	g.AddEdge("a", "b", 7)
+	g.AddEdge("a", "c", 9)
+	g.AddEdge("a", "f", 14)
+	g.AddEdge("b", "c", 10)
+	g.AddEdge("b", "d", 15)
+	g.AddEdge("c", "d", 11)
+	g.AddEdge("c", "f", 2)
+	g.AddEdge("d", "e", 6)
+	g.AddEdge("f", "e", 9)
+	g.AddVertex("z")
+
+	p, err := graph.ShortestPaths(g, "a")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(p.Dist("e"))
+	fmt.Println(p.To("e"))
+	fmt.Println(p.Dist("z"))
+	// Output:
+	// 20 true
+	// [a c f e]
+	// 0 false
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+
+	"go-generics-the-hard-way/pkg/constraints"
+	"go-generics-the-hard-way/pkg/heap"
+)
+
+// ErrNegativeWeight is returned by ShortestPaths when the graph has an edge
+// with a negative weight.
+var ErrNegativeWeight = errors.New("graph: negative edge weight")
+
+// Paths are the shortest paths from a single source vertex.
+type Paths[V comparable, W constraints.Real] struct {
+	g       *Graph[V, W]
+	dist    []W
+	prev    []int
+	reached []bool
+}
+
+// Dist returns the length of the shortest path to v. The second return value
+// is false if v is not reachable from the source.
+func (p *Paths[V, W]) Dist(v V) (W, bool) {
+	i, ok := p.g.index[v]
+	if !ok || !p.reached[i] {
+		var zero W
+		return zero, false
+	}
+	return p.dist[i], true
+}
+
+// To returns the vertices on the shortest path from the source to v,
+// including both, or nil if v is not reachable from the source.
+func (p *Paths[V, W]) To(v V) []V {
+	i, ok := p.g.index[v]
+	if !ok || !p.reached[i] {
+		return nil
+	}
+	var path []V
+	for ; i >= 0; i = p.prev[i] {
+		path = append(path, p.g.vertices[i])
+	}
+	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+		path[l], path[r] = path[r], path[l]
+	}
+	return path
+}
+
+// ShortestPaths uses Dijkstra's algorithm to find the shortest paths from src
+// to every vertex reachable from it.
+//
+// Please note ShortestPaths is a function and not a method on Graph[V, W]
+// because comparing path lengths requires an ordered weight. The Numeric
+// constraint used by Graph includes the complex types, which cannot be
+// ordered, so ShortestPaths further constrains W to Real.
+func ShortestPaths[V comparable, W constraints.Real](
+	g *Graph[V, W], src V) (*Paths[V, W], error) {
+
+	s, ok := g.index[src]
+	if !ok {
+		return nil, fmt.Errorf("graph: vertex %v not found", src)
+	}
+	for i := range g.edges {
+		for _, e := range g.edges[i] {
+			if e.w < 0 {
+				return nil, ErrNegativeWeight
+			}
+		}
+	}
+
+	n := len(g.vertices)
+	p := &Paths[V, W]{
+		g:       g,
+		dist:    make([]W, n),
+		prev:    make([]int, n),
+		reached: make([]bool, n),
+	}
+	for i := range p.prev {
+		p.prev[i] = -1
+	}
+
+	type item struct {
+		i    int
+		dist W
+	}
+	done := make([]bool, n)
+	queue := heap.New(func(a, b item) bool { return a.dist < b.dist })
+	p.reached[s] = true
+	queue.Push(item{i: s})
+
+	for queue.Len() > 0 {
+		it, _ := queue.Pop()
+		if done[it.i] {
+			continue
+		}
+		done[it.i] = true
+		for _, e := range g.edges[it.i] {
+			d := it.dist + e.w
+			if !p.reached[e.to] || d < p.dist[e.to] {
+				p.reached[e.to] = true
+				p.dist[e.to] = d
+				p.prev[e.to] = it.i
+				queue.Push(item{i: e.to, dist: d})
+			}
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUndirected is returned when an operation that requires a directed graph
+// is performed on an undirected graph.
+var ErrUndirected = errors.New("graph: operation requires a directed graph")
+
+// CycleError is returned by TopologicalSort when the graph has a cycle.
+type CycleError[V comparable] struct {
+
+	// Cycle is a path through the graph that starts and ends at the same
+	// vertex, ex. [a b c a].
+	Cycle []V
+}
+
+func (e *CycleError[V]) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i := range e.Cycle {
+		parts[i] = fmt.Sprint(e.Cycle[i])
+	}
+	return "graph: cycle detected: " + strings.Join(parts, " -> ")
+}
+
+// TopologicalSort returns the vertices of a directed graph ordered so every
+// vertex comes before the vertices its edges point to. Vertices that are not
+// constrained by an edge remain in the order in which they were added.
+//
+// If the graph has a cycle then a *CycleError is returned that describes one
+// of the cycles.
+func (g *Graph[V, W]) TopologicalSort() ([]V, error) {
+	if !g.directed {
+		return nil, ErrUndirected
+	}
+
+	// Kahn's algorithm.
+	indegree := make([]int, len(g.vertices))
+	for i := range g.edges {
+		for _, e := range g.edges[i] {
+			indegree[e.to]++
+		}
+	}
+	var queue []int
+	for i, n := range indegree {
+		if n == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	sorted := make([]V, 0, len(g.vertices))
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, g.vertices[i])
+		for _, e := range g.edges[i] {
+			if indegree[e.to]--; indegree[e.to] == 0 {
+				queue = append(queue, e.to)
+			}
+		}
+	}
+
+	if len(sorted) < len(g.vertices) {
+		return nil, &CycleError[V]{Cycle: g.findCycle(indegree)}
+	}
+	return sorted, nil
+}
+
+// findCycle returns a cycle among the vertices that Kahn's algorithm could
+// not remove, i.e. those with a remaining in-degree greater than zero.
+func (g *Graph[V, W]) findCycle(indegree []int) []V {
+	const (
+		white = iota
+		grey
+		black
+	)
+	color := make([]int, len(g.vertices))
+	var stack []int
+	var cycle []V
+
+	var visit func(int) bool
+	visit = func(i int) bool {
+		color[i] = grey
+		stack = append(stack, i)
+		for _, e := range g.edges[i] {
+			switch color[e.to] {
+			case grey:
+				// Found a back edge, so the cycle is the part of the stack
+				// starting at e.to.
+				k := len(stack) - 1
+				for stack[k] != e.to {
+					k--
+				}
+				for _, j := range stack[k:] {
+					cycle = append(cycle, g.vertices[j])
+				}
+				cycle = append(cycle, g.vertices[e.to])
+				return true
+			case white:
+				if visit(e.to) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[i] = black
+		return false
+	}
+
+	for i := range g.vertices {
+		if indegree[i] > 0 && color[i] == white && visit(i) {
+			break
+		}
+	}
+	return cycle
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hasher provides hash functions for the keys of sharded containers.
+//
+// Go does not expose the hash function used by maps, so there is no way to
+// hash a value of an arbitrary comparable type. Instead sharded containers
+// are given a Func for their key type, ex. String or Integer.
+package hasher
+
+import (
+	"go-generics-the-hard-way/pkg/constraints"
+)
+
+// Func returns the hash of a key.
+type Func[K comparable] func(key K) uint64
+
+// String hashes any type with an underlying type of string using the 64-bit
+// FNV-1a hash.
+func String[K ~string](key K) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// Integer hashes any integer type using the splitmix64 finalizer so
+// sequential keys are spread evenly.
+func Integer[K constraints.Integer](key K) uint64 {
+	return Mix(uint64(key))
+}
+
+// Mix is the splitmix64 finalizer. It may be used to build a Func for other
+// key types, ex. by mixing the fields of a struct.
+func Mix(h uint64) uint64 {
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package heap provides a generic binary heap that may be used as a priority
+// queue.
+//
+// Unlike container/heap, the values in a Heap[T] are never passed through
+// heap.Interface's Push(x any) and Pop() any, so they are never boxed.
+package heap
+
+import (
+	"go-generics-the-hard-way/pkg/constraints"
+)
+
+// LessFn reports whether a must sort before b.
+type LessFn[T any] func(a, b T) bool
+
+// Less is a LessFn for ordered types that sorts values in ascending order.
+func Less[T constraints.Ordered](a, b T) bool {
+	return a < b
+}
+
+// entry is a value in the heap and the order in which it was pushed.
+type entry[T any] struct {
+	val T
+	seq uint64
+}
+
+// Heap is a binary min-heap ordered by a LessFn.
+//
+// The zero value is not usable, please use New, NewStable, or NewOrdered.
+type Heap[T any] struct {
+	less   LessFn[T]
+	stable bool
+	seq    uint64
+	data   []entry[T]
+	index  func(val T, i int)
+}
+
+// New returns a new heap ordered by less.
+//
+// The order in which values that compare equal are popped is unspecified.
+func New[T any](less LessFn[T]) *Heap[T] {
+	return &Heap[T]{less: less}
+}
+
+// NewStable returns a new heap ordered by less that pops values which
+// compare equal in the order in which they were pushed.
+func NewStable[T any](less LessFn[T]) *Heap[T] {
+	return &Heap[T]{less: less, stable: true}
+}
+
+// NewOrdered returns a new heap that pops ordered values in ascending order.
+func NewOrdered[T constraints.Ordered]() *Heap[T] {
+	return New(Less[T])
+}
+
+// OnIndex registers fn to be called every time a value is placed at a new
+// index in the heap, ex. when it is pushed or moved by Pop, Remove, Fix, or
+// Update. When a value is removed from the heap, or replaced by Update, fn is
+// called with an index of -1.
+//
+// This mirrors the Index field in container/heap's PriorityQueue example and
+// is how callers learn the indices to pass to Remove, Fix, and Update. OnIndex
+// should be called before any values are pushed.
+func (h *Heap[T]) OnIndex(fn func(val T, i int)) {
+	h.index = fn
+}
+
+// Len returns the number of values in the heap.
+func (h *Heap[T]) Len() int {
+	return len(h.data)
+}
+
+// At returns the value at index i, where the value at index 0 is the minimum.
+func (h *Heap[T]) At(i int) T {
+	return h.data[i].val
+}
+
+// Push adds val to the heap.
+func (h *Heap[T]) Push(val T) {
+	h.data = append(h.data, entry[T]{val: val, seq: h.seq})
+	h.seq++
+	h.moved(len(h.data) - 1)
+	h.up(len(h.data) - 1)
+}
+
+// Pop removes and returns the minimum value from the heap. The second return
+// value is false if the heap is empty.
+func (h *Heap[T]) Pop() (T, bool) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.Remove(0), true
+}
+
+// Peek returns the minimum value without removing it. The second return value
+// is false if the heap is empty.
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.data[0].val, true
+}
+
+// Remove removes and returns the value at index i.
+func (h *Heap[T]) Remove(i int) T {
+	n := len(h.data) - 1
+	if n != i {
+		h.swap(i, n)
+	}
+	e := h.data[n]
+
+	// Clear the removed entry so the heap does not keep a reference to it.
+	h.data[n] = entry[T]{}
+	h.data = h.data[:n]
+	if h.index != nil {
+		h.index(e.val, -1)
+	}
+
+	if n != i {
+		h.fix(i)
+	}
+	return e.val
+}
+
+// Fix re-establishes the heap ordering after the value at index i has
+// changed, ex. when T is a pointer and the value it points to was modified.
+func (h *Heap[T]) Fix(i int) {
+	h.fix(i)
+}
+
+// Update replaces the value at index i with val and re-establishes the heap
+// ordering. In stable mode val keeps the insertion order of the value it
+// replaced.
+func (h *Heap[T]) Update(i int, val T) {
+	if h.index != nil {
+		h.index(h.data[i].val, -1)
+	}
+	h.data[i].val = val
+	h.moved(i)
+	h.fix(i)
+}
+
+// Clear removes all values from the heap.
+func (h *Heap[T]) Clear() {
+	for i := range h.data {
+		if h.index != nil {
+			h.index(h.data[i].val, -1)
+		}
+		h.data[i] = entry[T]{}
+	}
+	h.data = h.data[:0]
+}
+
+func (h *Heap[T]) fix(i int) {
+	if !h.down(i) {
+		h.up(i)
+	}
+}
+
+func (h *Heap[T]) lessAt(i, j int) bool {
+	a, b := h.data[i], h.data[j]
+	if h.less(a.val, b.val) {
+		return true
+	}
+	if h.stable && !h.less(b.val, a.val) {
+		return a.seq < b.seq
+	}
+	return false
+}
+
+func (h *Heap[T]) swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+	h.moved(i)
+	h.moved(j)
+}
+
+// moved reports the new index of the value at index i to the OnIndex
+// callback, if there is one.
+func (h *Heap[T]) moved(i int) {
+	if h.index != nil {
+		h.index(h.data[i].val, i)
+	}
+}
+
+func (h *Heap[T]) up(j int) {
+	for j > 0 {
+		i := (j - 1) / 2
+		if !h.lessAt(j, i) {
+			break
+		}
+		h.swap(i, j)
+		j = i
+	}
+}
+
+// down moves the value at index i0 down the heap and returns true if it
+// moved.
+func (h *Heap[T]) down(i0 int) bool {
+	n := len(h.data)
+	i := i0
+	for {
+		j := 2*i + 1
+		if j >= n || j < 0 {
+			break
+		}
+		if r := j + 1; r < n && h.lessAt(r, j) {
+			j = r
+		}
+		if !h.lessAt(j, i) {
+			break
+		}
+		h.swap(i, j)
+		i = j
+	}
+	return i > i0
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package heap_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/heap"
+)
+
+type task struct {
+	name     string
+	priority int
+}
+
+func byPriority(a, b task) bool {
+	return a.priority < b.priority
+}
+
+func ExampleNewOrdered() {
+	h := heap.NewOrdered[string]()
+	h.Push("world")
+	h.Push("hello")
+	h.Push("generics")
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		fmt.Println(v)
+	}
+	// Output:
+	// generics
+	// hello
+	// world
+}
+
+func ExampleNewStable() {
+	h := heap.NewStable(byPriority)
+	h.Push(task{"b", 2})
+	h.Push(task{"a1", 1})
+	h.Push(task{"c", 3})
+	h.Push(task{"a2", 1})
+	h.Push(task{"a3", 1})
+	for h.Len() > 0 {
+		t, _ := h.Pop()
+		fmt.Print(t.name, " ")
+	}
+	fmt.Println()
+	// Output: a1 a2 a3 b c
+}
+
+func ExampleHeap_Peek() {
+	h := heap.NewOrdered[int]()
+	_, ok := h.Peek()
+	fmt.Println(ok)
+	h.Push(2)
+	h.Push(1)
+	fmt.Println(h.Peek())
+	fmt.Println(h.Len())
+	// Output:
+	// false
+	// 1 true
+	// 2
+}
+
+func ExampleHeap_Update() {
+	h := heap.New(byPriority)
+	h.Push(task{"a", 1})
+	h.Push(task{"b", 2})
+
+	// Lower the priority of the task at the top of the heap.
+	h.Update(0, task{"a", 3})
+
+	t, _ := h.Peek()
+	fmt.Println(t.name)
+	// Output: b
+}
+
+func ExampleHeap_Fix() {
+	h := heap.New(func(a, b *task) bool { return a.priority < b.priority })
+	a, b := &task{"a", 1}, &task{"b", 2}
+	h.Push(a)
+	h.Push(b)
+
+	// Modify the value the pointer refers to and then fix the heap.
+	a.priority = 3
+	h.Fix(0)
+
+	t, _ := h.Peek()
+	fmt.Println(t.name)
+	// Output: b
+}
+
+func ExampleHeap_OnIndex() {
+	type item struct {
+		name     string
+		priority int
+		index    int
+	}
+	h := heap.New(func(a, b *item) bool { return a.priority < b.priority })
+	h.OnIndex(func(it *item, i int) { it.index = i })
+
+	a, b, c := &item{"a", 1, 0}, &item{"b", 2, 0}, &item{"c", 3, 0}
+	h.Push(c)
+	h.Push(b)
+	h.Push(a)
+
+	// Use the tracked index to change the priority of c without searching
+	// the heap for it.
+	c.priority = 0
+	h.Fix(c.index)
+
+	for h.Len() > 0 {
+		it, _ := h.Pop()
+		fmt.Print(it.name, it.index, " ")
+	}
+	fmt.Println()
+	// Output: c-1 a-1 b-1
+}
+
+func TestHeapOnIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	h := heap.NewOrdered[int]()
+	index := map[int]int{}
+	h.OnIndex(func(v, i int) { index[v] = i })
+
+	check := func() {
+		t.Helper()
+		for i := 0; i < h.Len(); i++ {
+			if got := index[h.At(i)]; got != i {
+				t.Fatalf("index[%d]=%d, want %d", h.At(i), got, i)
+			}
+		}
+	}
+	for _, v := range r.Perm(100) {
+		h.Push(v)
+		check()
+	}
+	for h.Len() > 50 {
+		v := h.At(r.Intn(h.Len()))
+		if got := h.Remove(index[v]); got != v {
+			t.Fatalf("Remove(index[%d])=%d", v, got)
+		}
+		if index[v] != -1 {
+			t.Fatalf("index[%d]=%d after Remove, want -1", v, index[v])
+		}
+		check()
+	}
+	v := h.At(h.Len() - 1)
+	h.Update(index[v], -1)
+	check()
+	if got, _ := h.Peek(); got != -1 {
+		t.Fatalf("Peek()=%d after Update, want -1", got)
+	}
+	h.Clear()
+	for v, i := range index {
+		if i != -1 {
+			t.Fatalf("index[%d]=%d after Clear, want -1", v, i)
+		}
+	}
+}
+
+func TestHeapRemove(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	h := heap.NewOrdered[int]()
+	var want []int
+	for i := 0; i < 200; i++ {
+		v := r.Intn(50)
+		h.Push(v)
+		want = append(want, v)
+	}
+
+	// Remove values at random indices and drop them from the expected values.
+	for i := 0; i < 50; i++ {
+		v := h.Remove(r.Intn(h.Len()))
+		for j := range want {
+			if want[j] == v {
+				want = append(want[:j], want[j+1:]...)
+				break
+			}
+		}
+	}
+
+	sort.Ints(want)
+	for i := range want {
+		v, ok := h.Pop()
+		if !ok {
+			t.Fatalf("heap empty after %d pops, want %d", i, len(want))
+		}
+		if v != want[i] {
+			t.Fatalf("pop %d = %d, want %d", i, v, want[i])
+		}
+	}
+	if h.Len() != 0 {
+		t.Fatalf("len = %d, want 0", h.Len())
+	}
+}
+
+func TestHeapStableAfterRemove(t *testing.T) {
+	h := heap.NewStable(byPriority)
+	for i := 0; i < 20; i++ {
+		h.Push(task{fmt.Sprint(i), i % 2})
+	}
+	h.Remove(5)
+	h.Remove(3)
+
+	last := map[int]int{0: -1, 1: -1}
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		var n int
+		fmt.Sscan(v.name, &n)
+		if n < last[v.priority] {
+			t.Fatalf("%q popped after %d with priority %d", v.name, last[v.priority], v.priority)
+		}
+		last[v.priority] = n
+	}
+}
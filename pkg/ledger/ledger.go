@@ -0,0 +1,110 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ledger provides Ledger, an identifiable, financial record.
+package ledger
+
+import (
+	"go-generics-the-hard-way/pkg/constraints"
+	"go-generics-the-hard-way/pkg/wire"
+)
+
+// SumFn defines a function that can return the sum of one to zero numbers.
+type SumFn[K constraints.Numeric] func(...K) K
+
+// Sum returns the sum of the provided values.
+func Sum[K constraints.Numeric](vals ...K) K {
+	var sum K
+	for i := 0; i < len(vals); i++ {
+		sum += vals[i]
+	}
+	return sum
+}
+
+// Ledger is an identifiable, financial record.
+type Ledger[T ~string, K constraints.Numeric] struct {
+
+	// ID identifies the ledger.
+	ID T
+
+	// Amounts is a list of monies associated with this ledger.
+	Amounts []K
+
+	// SumFn is a function that can be used to sum the amounts
+	// in this ledger. If nil, Sum is used.
+	SumFn SumFn[K]
+}
+
+// Sum returns the sum of the ledger's amounts.
+func (l Ledger[T, K]) Sum() K {
+	if l.SumFn != nil {
+		return l.SumFn(l.Amounts...)
+	}
+	return Sum(l.Amounts...)
+}
+
+// MarshalBinary encodes the ledger's ID and amounts in the format described
+// by package wire. The SumFn field is not encoded.
+func (l Ledger[T, K]) MarshalBinary() ([]byte, error) {
+	ids, amounts, err := codecs[T, K]()
+	if err != nil {
+		return nil, err
+	}
+	b := wire.AppendHeader(nil, wire.Ledger, amounts.Type())
+	b = ids.Append(b, l.ID)
+	return amounts.AppendAll(b, l.Amounts), nil
+}
+
+// UnmarshalBinary replaces the ledger's ID and amounts with those encoded in
+// data by MarshalBinary. The SumFn field is left unchanged.
+func (l *Ledger[T, K]) UnmarshalBinary(data []byte) error {
+	ids, amounts, err := codecs[T, K]()
+	if err != nil {
+		return err
+	}
+	r := wire.NewReader(data)
+	if err := r.Header(wire.Ledger, amounts.Type()); err != nil {
+		return err
+	}
+	id, err := ids.Read(r)
+	if err != nil {
+		return err
+	}
+	vals, err := amounts.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := r.Close(); err != nil {
+		return err
+	}
+	l.ID, l.Amounts = id, vals
+	return nil
+}
+
+// codecs returns the codecs for a ledger's IDs and amounts. The constraints
+// on T and K mean it only fails if package wire falls out of step with
+// package constraints.
+func codecs[T ~string, K constraints.Numeric]() (*wire.Codec[T], *wire.Codec[K], error) {
+	ids, err := wire.CodecFor[T]()
+	if err != nil {
+		return nil, nil, err
+	}
+	amounts, err := wire.CodecFor[K]()
+	if err != nil {
+		return nil, nil, err
+	}
+	return ids, amounts, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/ledger"
+)
+
+type ID string
+
+func ExampleLedger_MarshalBinary() {
+	l := ledger.Ledger[ID, int64]{ID: "acct-1", Amounts: []int64{100, -25, 3}}
+	data, err := l.MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("% x\n", data)
+
+	var out ledger.Ledger[ID, int64]
+	if err := out.UnmarshalBinary(data); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(out.ID, out.Amounts, out.Sum())
+	// Output:
+	// 01 03 05 06 61 63 63 74 2d 31 03 c8 01 31 06
+	// acct-1 [100 -25 3] 78
+}
+
+func TestSumFn(t *testing.T) {
+	l := ledger.Ledger[ID, float64]{
+		Amounts: []float64{1, 2},
+		SumFn: func(vals ...float64) float64 {
+			return -ledger.Sum(vals...)
+		},
+	}
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Sum(); got != -3 {
+		t.Errorf("Sum() = %v, want the SumFn to be kept", got)
+	}
+}
+
+func FuzzLedgerRoundTrip(f *testing.F) {
+	f.Add("acct-1", []byte{})
+	f.Add("", []byte{0, 0, 0, 0, 0, 0, 0xf8, 0x7f})
+	f.Fuzz(func(t *testing.T, id string, data []byte) {
+		l := ledger.Ledger[ID, float64]{ID: ID(id)}
+		for i := 0; i+8 <= len(data); i += 8 {
+			l.Amounts = append(l.Amounts, math.Float64frombits(binary.LittleEndian.Uint64(data[i:])))
+		}
+		b, err := l.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out ledger.Ledger[ID, float64]
+		if err := out.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if out.ID != l.ID || len(out.Amounts) != len(l.Amounts) {
+			t.Fatalf("got %v, want %v", out, l)
+		}
+		// Compare the bits so NaNs compare equal.
+		for i := range l.Amounts {
+			if math.Float64bits(out.Amounts[i]) != math.Float64bits(l.Amounts[i]) {
+				t.Fatalf("got %v, want %v", out.Amounts, l.Amounts)
+			}
+		}
+
+		// Arbitrary data must be rejected without panicking.
+		_ = out.UnmarshalBinary(data)
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package list provides a generic list built on top of a Go slice.
+package list
+
+import (
+	"go-generics-the-hard-way/pkg/wire"
+)
+
+// List is a new type definition for []T.
+type List[T any] []T
+
+// Add appends the provided values to the list.
+func (l *List[T]) Add(vals ...T) {
+	*l = append(*l, vals...)
+}
+
+// Len returns the number of elements in the list.
+func (l List[T]) Len() int {
+	return len(l)
+}
+
+// MarshalBinary encodes the list in the format described by package wire.
+// A *wire.UnsupportedTypeError is returned if T is not a number or a string.
+func (l List[T]) MarshalBinary() ([]byte, error) {
+	c, err := wire.CodecFor[T]()
+	if err != nil {
+		return nil, err
+	}
+	b := wire.AppendHeader(nil, wire.List, c.Type())
+	return c.AppendAll(b, l), nil
+}
+
+// UnmarshalBinary replaces the contents of the list with the list encoded
+// in data by MarshalBinary.
+func (l *List[T]) UnmarshalBinary(data []byte) error {
+	c, err := wire.CodecFor[T]()
+	if err != nil {
+		return err
+	}
+	r := wire.NewReader(data)
+	if err := r.Header(wire.List, c.Type()); err != nil {
+		return err
+	}
+	vals, err := c.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := r.Close(); err != nil {
+		return err
+	}
+	*l = vals
+	return nil
+}
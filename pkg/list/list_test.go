@@ -0,0 +1,115 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/list"
+)
+
+type ID string
+
+func ExampleList_MarshalBinary() {
+	var l list.List[ID]
+	l.Add("acct-1", "acct-2")
+
+	data, err := l.MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("% x\n", data)
+
+	var out list.List[ID]
+	if err := out.UnmarshalBinary(data); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(out)
+	// Output:
+	// 01 01 0f 02 06 61 63 63 74 2d 31 06 61 63 63 74 2d 32
+	// [acct-1 acct-2]
+}
+
+func roundTrip[T any](t *testing.T, l list.List[T]) {
+	t.Helper()
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out list.List[T]
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) == 0 && len(out) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(out, l) {
+		t.Fatalf("got %v, want %v", out, l)
+	}
+}
+
+func FuzzListRoundTrip(f *testing.F) {
+	f.Add([]byte{}, "")
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}, "a,b,,c")
+	f.Fuzz(func(t *testing.T, data []byte, ids string) {
+		var (
+			i64 list.List[int64]
+			u16 list.List[uint16]
+			f32 list.List[float32]
+		)
+		for i := 0; i+8 <= len(data); i += 8 {
+			n := binary.LittleEndian.Uint64(data[i:])
+			i64.Add(int64(n))
+			u16.Add(uint16(n))
+			if f := float32(int32(n)); f == f {
+				f32.Add(f / 7)
+			}
+		}
+		roundTrip(t, i64)
+		roundTrip(t, u16)
+		roundTrip(t, f32)
+		var l list.List[ID]
+		for _, id := range strings.Split(ids, ",") {
+			l.Add(ID(id))
+		}
+		roundTrip(t, l)
+	})
+}
+
+// FuzzListUnmarshal checks that arbitrary data never causes a panic, and that
+// any list decoded from it survives a round trip.
+func FuzzListUnmarshal(f *testing.F) {
+	seed, _ := list.List[int32]{1, -1, 1 << 30}.MarshalBinary()
+	f.Add(seed)
+	f.Add([]byte{1, 1, 3, 0xff, 0xff, 0xff, 0xff, 0x0f})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var l list.List[int32]
+		if err := l.UnmarshalBinary(data); err == nil {
+			roundTrip(t, l)
+		}
+		var s list.List[string]
+		if err := s.UnmarshalBinary(data); err == nil {
+			roundTrip(t, s)
+		}
+	})
+}
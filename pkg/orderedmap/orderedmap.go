@@ -0,0 +1,238 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package orderedmap provides a generic, sorted map backed by a skip list.
+package orderedmap
+
+import (
+	"go-generics-the-hard-way/pkg/constraints"
+)
+
+const (
+	// maxLevel is the maximum number of levels in the skip list. With p=1/4
+	// this comfortably supports 4^32 keys.
+	maxLevel = 32
+
+	// levelShift is the number of random bits consumed for each level,
+	// i.e. each level is promoted with a probability of 1/(1<<levelShift).
+	levelShift = 2
+)
+
+type node[K constraints.Ordered, V any] struct {
+	key  K
+	val  V
+	next []*node[K, V]
+}
+
+// OrderedMap is a map whose keys are kept in ascending order.
+//
+// The zero value is not usable, please use New.
+type OrderedMap[K constraints.Ordered, V any] struct {
+	head  *node[K, V]
+	level int
+	len   int
+	rnd   uint64
+}
+
+// New returns a new, empty ordered map.
+func New[K constraints.Ordered, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		head:  &node[K, V]{next: make([]*node[K, V], maxLevel)},
+		level: 1,
+		rnd:   0x9E3779B97F4A7C15,
+	}
+}
+
+// Len returns the number of keys in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	return m.len
+}
+
+// Get returns the value for key. The second return value is false if the key
+// is not in the map.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if n := m.lowerBound(key, nil); n != nil && n.key == key {
+		return n.val, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has returns true if key is in the map.
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	n := m.lowerBound(key, nil)
+	return n != nil && n.key == key
+}
+
+// Put sets the value for key, replacing any existing value. Put panics if
+// key is a floating-point NaN, which is not equal to any key, itself
+// included, so it could neither be found nor replaced.
+func (m *OrderedMap[K, V]) Put(key K, val V) {
+	if key != key {
+		panic("orderedmap: Put called with a NaN key")
+	}
+	var update [maxLevel]*node[K, V]
+	if n := m.lowerBound(key, &update); n != nil && n.key == key {
+		n.val = val
+		return
+	}
+
+	level := m.randomLevel()
+	if level > m.level {
+		for i := m.level; i < level; i++ {
+			update[i] = m.head
+		}
+		m.level = level
+	}
+
+	n := &node[K, V]{key: key, val: val, next: make([]*node[K, V], level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	m.len++
+}
+
+// Delete removes key from the map and returns true if the key was present.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	var update [maxLevel]*node[K, V]
+	n := m.lowerBound(key, &update)
+	if n == nil || n.key != key {
+		return false
+	}
+	for i := 0; i < len(n.next); i++ {
+		update[i].next[i] = n.next[i]
+	}
+	for m.level > 1 && m.head.next[m.level-1] == nil {
+		m.level--
+	}
+	m.len--
+	return true
+}
+
+// Min returns the smallest key and its value. The last return value is false
+// if the map is empty.
+func (m *OrderedMap[K, V]) Min() (K, V, bool) {
+	return result(m.head.next[0])
+}
+
+// Max returns the largest key and its value. The last return value is false
+// if the map is empty.
+func (m *OrderedMap[K, V]) Max() (K, V, bool) {
+	x := m.head
+	for i := m.level - 1; i >= 0; i-- {
+		for x.next[i] != nil {
+			x = x.next[i]
+		}
+	}
+	if x == m.head {
+		return result[K, V](nil)
+	}
+	return result(x)
+}
+
+// Floor returns the largest key less than or equal to key. The last return
+// value is false if there is no such key.
+func (m *OrderedMap[K, V]) Floor(key K) (K, V, bool) {
+	var update [maxLevel]*node[K, V]
+	if n := m.lowerBound(key, &update); n != nil && n.key == key {
+		return result(n)
+	}
+	if update[0] == m.head {
+		return result[K, V](nil)
+	}
+	return result(update[0])
+}
+
+// Ceiling returns the smallest key greater than or equal to key. The last
+// return value is false if there is no such key.
+func (m *OrderedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return result(m.lowerBound(key, nil))
+}
+
+// Each invokes fn for each key and value in ascending key order. Iteration
+// stops early if fn returns false.
+func (m *OrderedMap[K, V]) Each(fn func(K, V) bool) {
+	for n := m.head.next[0]; n != nil; n = n.next[0] {
+		if !fn(n.key, n.val) {
+			return
+		}
+	}
+}
+
+// Range invokes fn for each key in the half-open interval [lo, hi) in
+// ascending key order. Iteration stops early if fn returns false.
+func (m *OrderedMap[K, V]) Range(lo, hi K, fn func(K, V) bool) {
+	for n := m.lowerBound(lo, nil); n != nil && n.key < hi; n = n.next[0] {
+		if !fn(n.key, n.val) {
+			return
+		}
+	}
+}
+
+// Keys returns the keys in ascending order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.len)
+	for n := m.head.next[0]; n != nil; n = n.next[0] {
+		keys = append(keys, n.key)
+	}
+	return keys
+}
+
+// lowerBound returns the first node with a key greater than or equal to key,
+// or nil if there is no such node. If update is not nil, each level is set to
+// the last node at that level with a key less than key.
+func (m *OrderedMap[K, V]) lowerBound(
+	key K, update *[maxLevel]*node[K, V]) *node[K, V] {
+
+	x := m.head
+	for i := m.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+		if update != nil {
+			update[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+// randomLevel returns a level in [1, maxLevel] with a geometric distribution
+// using a xorshift generator, which unlike math/rand does not need a lock.
+func (m *OrderedMap[K, V]) randomLevel() int {
+	m.rnd ^= m.rnd << 13
+	m.rnd ^= m.rnd >> 7
+	m.rnd ^= m.rnd << 17
+	r := m.rnd
+
+	level := 1
+	for level < maxLevel && r&(1<<levelShift-1) == 0 {
+		level++
+		r >>= levelShift
+	}
+	return level
+}
+
+func result[K constraints.Ordered, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+	return n.key, n.val, true
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orderedmap_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/orderedmap"
+)
+
+// ID is a type definition with an underlying type of string.
+type ID string
+
+func ExampleOrderedMap_Each() {
+	m := orderedmap.New[ID, int]()
+	m.Put("acct-3", 3)
+	m.Put("acct-1", 1)
+	m.Put("acct-2", 2)
+	m.Each(func(k ID, v int) bool {
+		fmt.Println(k, v)
+		return true
+	})
+	// Output:
+	// acct-1 1
+	// acct-2 2
+	// acct-3 3
+}
+
+func ExampleOrderedMap_Floor() {
+	m := orderedmap.New[int, string]()
+	m.Put(10, "ten")
+	m.Put(20, "twenty")
+	fmt.Println(m.Floor(15))
+	fmt.Println(m.Floor(20))
+	fmt.Println(m.Floor(5))
+	// Output:
+	// 10 ten true
+	// 20 twenty true
+	// 0  false
+}
+
+func ExampleOrderedMap_Ceiling() {
+	m := orderedmap.New[int, string]()
+	m.Put(10, "ten")
+	m.Put(20, "twenty")
+	fmt.Println(m.Ceiling(15))
+	fmt.Println(m.Ceiling(10))
+	fmt.Println(m.Ceiling(25))
+	// Output:
+	// 20 twenty true
+	// 10 ten true
+	// 0  false
+}
+
+func ExampleOrderedMap_Range() {
+	m := orderedmap.New[float64, string]()
+	m.Put(1.5, "a")
+	m.Put(2.5, "b")
+	m.Put(3.5, "c")
+	m.Put(4.5, "d")
+	m.Range(2, 4.5, func(k float64, v string) bool {
+		fmt.Println(k, v)
+		return true
+	})
+	// Output:
+	// 2.5 b
+	// 3.5 c
+}
+
+func ExampleOrderedMap_Min() {
+	m := orderedmap.New[string, int]()
+	fmt.Println(m.Min())
+	m.Put("b", 2)
+	m.Put("a", 1)
+	m.Put("c", 3)
+	fmt.Println(m.Min())
+	fmt.Println(m.Max())
+	// Output:
+	//  0 false
+	// a 1 true
+	// c 3 true
+}
+
+func TestOrderedMap(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	m := orderedmap.New[int, int]()
+	want := map[int]int{}
+
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(1000)
+		switch r.Intn(3) {
+		case 0, 1:
+			m.Put(k, i)
+			want[k] = i
+		case 2:
+			_, ok := want[k]
+			if got := m.Delete(k); got != ok {
+				t.Fatalf("Delete(%d) = %v, want %v", k, got, ok)
+			}
+			delete(want, k)
+		}
+	}
+
+	if m.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", m.Len(), len(want))
+	}
+
+	keys := make([]int, 0, len(want))
+	for k, v := range want {
+		keys = append(keys, k)
+		if got, ok := m.Get(k); !ok || got != v {
+			t.Fatalf("Get(%d) = %d, %v, want %d, true", k, got, ok, v)
+		}
+	}
+	sort.Ints(keys)
+
+	got := m.Keys()
+	if len(got) != len(keys) {
+		t.Fatalf("len(Keys()) = %d, want %d", len(got), len(keys))
+	}
+	for i := range keys {
+		if got[i] != keys[i] {
+			t.Fatalf("Keys()[%d] = %d, want %d", i, got[i], keys[i])
+		}
+	}
+
+	for k := -1; k <= 1001; k++ {
+		i := sort.SearchInts(keys, k)
+		ck, _, ok := m.Ceiling(k)
+		if wantOK := i < len(keys); ok != wantOK || (ok && ck != keys[i]) {
+			t.Fatalf("Ceiling(%d) = %d, %v", k, ck, ok)
+		}
+		if i < len(keys) && keys[i] == k {
+			i++
+		}
+		fk, _, ok := m.Floor(k)
+		if wantOK := i > 0; ok != wantOK || (ok && fk != keys[i-1]) {
+			t.Fatalf("Floor(%d) = %d, %v", k, fk, ok)
+		}
+	}
+}
+
+func TestPutNaN(t *testing.T) {
+	m := orderedmap.New[float64, int]()
+	m.Put(1, 1)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Put did not panic with a NaN key")
+			}
+		}()
+		m.Put(math.NaN(), 2)
+	}()
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", m.Len())
+	}
+	if _, ok := m.Get(math.NaN()); ok {
+		t.Error("Get(NaN) found a value")
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Leak describes an object that was taken from a pool in debug mode and not
+// yet returned.
+type Leak struct {
+
+	// Stack is the stack trace of the call to Get that returned the object.
+	Stack string
+}
+
+// DoublePutError is the value a pool in debug mode panics with when an
+// object is returned to the pool twice without being taken out in between.
+type DoublePutError struct {
+
+	// GetStack is the stack trace of the call to Get that last returned the
+	// object, if known.
+	GetStack string
+
+	// PutStack is the stack trace of the first call to Put.
+	PutStack string
+}
+
+func (e *DoublePutError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("pool: object returned to pool twice")
+	if e.GetStack != "" {
+		sb.WriteString("\n\nobject taken from pool at:\n")
+		sb.WriteString(e.GetStack)
+	}
+	sb.WriteString("\n\nobject first returned to pool at:\n")
+	sb.WriteString(e.PutStack)
+	return sb.String()
+}
+
+// zeroSize reports whether values of type T have a size of zero. The runtime
+// may give every zero-size object the same address, so the tracker cannot
+// tell them apart.
+func zeroSize[T any]() bool {
+	var zero T
+	return unsafe.Sizeof(zero) == 0
+}
+
+// record is the history of an object that has been returned to a pool.
+type record struct {
+	getStack string
+	putStack string
+}
+
+// tracker records which objects are in and out of a pool in debug mode.
+//
+// Please note the tracker holds a reference to every object it has seen, so
+// objects the pool would otherwise have released to the garbage collector
+// are kept alive. Debug mode is intended for tests, not production.
+type tracker struct {
+	mu  sync.Mutex
+	out map[unsafe.Pointer]string
+	in  map[unsafe.Pointer]record
+}
+
+func (t *tracker) init() {
+	if t.out == nil {
+		t.out = map[unsafe.Pointer]string{}
+		t.in = map[unsafe.Pointer]record{}
+	}
+}
+
+func (t *tracker) get(p unsafe.Pointer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.init()
+	delete(t.in, p)
+	t.out[p] = stack()
+}
+
+func (t *tracker) put(p unsafe.Pointer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.init()
+	if r, ok := t.in[p]; ok {
+		panic(&DoublePutError{GetStack: r.getStack, PutStack: r.putStack})
+	}
+
+	// Objects that were never taken from the pool are accepted, as a pool
+	// may be seeded with objects created elsewhere.
+	t.in[p] = record{getStack: t.out[p], putStack: stack()}
+	delete(t.out, p)
+}
+
+func (t *tracker) leaks() []Leak {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	leaks := make([]Leak, 0, len(t.out))
+	for _, s := range t.out {
+		leaks = append(leaks, Leak{Stack: s})
+	}
+	sort.Slice(leaks, func(i, j int) bool { return leaks[i].Stack < leaks[j].Stack })
+	return leaks
+}
+
+// stack returns the stack trace of the caller of the pool method that called
+// the tracker.
+func stack() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var sb strings.Builder
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pool provides generic object pools built on sync.Pool.
+//
+// The sync.Pool API traffics in the empty interface, so every call to Get
+// must be followed by a type assertion, and putting a value that is not a
+// pointer, such as a slice, boxes it and causes an allocation. The pools in
+// this package have typed APIs and never require the caller to box a value.
+package pool
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// Pool is a typed pool of *T.
+//
+// The zero value is an empty pool ready to use. A Pool must not be copied
+// after first use.
+type Pool[T any] struct {
+
+	// New, if not nil, returns a new object when the pool is empty. If New
+	// is nil then new(T) is used.
+	New func() *T
+
+	// Reset, if not nil, is called on each object passed to Put before it
+	// is returned to the pool.
+	Reset func(*T)
+
+	// Debug enables leak and double-put detection. It must be set before
+	// the pool is first used. Please see Leaks and DoublePutError.
+	//
+	// Debug has no effect if T has a size of zero, ex. struct{}, as distinct
+	// objects of such a type may share the same address.
+	Debug bool
+
+	p sync.Pool
+	t tracker
+}
+
+// Get returns an object from the pool, or a new object if the pool is empty.
+func (p *Pool[T]) Get() *T {
+	var obj *T
+	if v := p.p.Get(); v != nil {
+		obj = v.(*T)
+	} else if p.New != nil {
+		obj = p.New()
+	} else {
+		obj = new(T)
+	}
+	if p.Debug && !zeroSize[T]() {
+		p.t.get(unsafe.Pointer(obj))
+	}
+	return obj
+}
+
+// Put resets obj and returns it to the pool. The caller must not use obj
+// after it has been returned to the pool.
+//
+// In debug mode, Put panics with a *DoublePutError if obj is already in the
+// pool.
+func (p *Pool[T]) Put(obj *T) {
+	if obj == nil {
+		return
+	}
+	if p.Debug && !zeroSize[T]() {
+		p.t.put(unsafe.Pointer(obj))
+	}
+	if p.Reset != nil {
+		p.Reset(obj)
+	}
+	p.p.Put(obj)
+}
+
+// Leaks returns the objects taken from the pool that have not been returned.
+// It always returns an empty slice if the pool is not in debug mode.
+func (p *Pool[T]) Leaks() []Leak {
+	return p.t.leaks()
+}
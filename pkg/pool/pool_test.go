@@ -0,0 +1,155 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/pool"
+)
+
+func ExamplePool() {
+	p := pool.Pool[bytes.Buffer]{
+		Reset: func(b *bytes.Buffer) { b.Reset() },
+	}
+
+	// No type assertion is required.
+	buf := p.Get()
+	buf.WriteString("hello")
+	fmt.Println(buf.String())
+	p.Put(buf)
+
+	fmt.Println(p.Get().Len())
+	// Output:
+	// hello
+	// 0
+}
+
+func ExampleSlicePool() {
+	p := pool.NewSlicePool[byte](64, 4096)
+
+	b := p.Get(100)
+	fmt.Println(len(b), cap(b))
+	p.Put(b)
+
+	b = p.Get(5000)
+	fmt.Println(len(b), cap(b))
+	// Output:
+	// 100 128
+	// 5000 5000
+}
+
+func TestPoolDoublePut(t *testing.T) {
+	p := pool.Pool[int]{Debug: true}
+	obj := p.Get()
+	p.Put(obj)
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		var dperr *pool.DoublePutError
+		if !ok || !errors.As(err, &dperr) {
+			t.Fatalf("recovered %v, want a *DoublePutError", r)
+		}
+		if !strings.Contains(dperr.PutStack, "TestPoolDoublePut") {
+			t.Fatalf("PutStack does not include the test:\n%s", dperr.PutStack)
+		}
+	}()
+	p.Put(obj)
+	t.Fatal("second Put did not panic")
+}
+
+func TestPoolLeaks(t *testing.T) {
+	p := pool.Pool[int]{Debug: true}
+	a, b := p.Get(), p.Get()
+	p.Put(a)
+
+	leaks := p.Leaks()
+	if len(leaks) != 1 {
+		t.Fatalf("len(Leaks()) = %d, want 1", len(leaks))
+	}
+	if !strings.Contains(leaks[0].Stack, "TestPoolLeaks") {
+		t.Fatalf("leak stack does not include the test:\n%s", leaks[0].Stack)
+	}
+
+	p.Put(b)
+	if n := len(p.Leaks()); n != 0 {
+		t.Fatalf("len(Leaks()) = %d, want 0", n)
+	}
+}
+
+func TestSlicePoolDebug(t *testing.T) {
+	p := pool.NewSlicePool[int](8, 64)
+	p.Debug = true
+
+	s := p.Get(10)
+	if n := len(p.Leaks()); n != 1 {
+		t.Fatalf("len(Leaks()) = %d, want 1", n)
+	}
+
+	// The length of the slice does not matter when it is returned.
+	p.Put(s[:3])
+	if n := len(p.Leaks()); n != 0 {
+		t.Fatalf("len(Leaks()) = %d, want 0", n)
+	}
+
+	defer func() {
+		if _, ok := recover().(*pool.DoublePutError); !ok {
+			t.Fatal("second Put did not panic with a *DoublePutError")
+		}
+	}()
+	p.Put(s)
+}
+
+func TestSlicePoolIgnoresForeignSizes(t *testing.T) {
+	p := pool.NewSlicePool[int](8, 64)
+	p.Put(make([]int, 10))
+	p.Put(make([]int, 128))
+	p.Put(nil)
+	if s := p.Get(1); cap(s) != 8 {
+		t.Fatalf("cap = %d, want 8", cap(s))
+	}
+}
+
+func TestDebugZeroSize(t *testing.T) {
+	// Every new(struct{}) may return the same address, which must not be
+	// mistaken for the same object being returned twice.
+	p := &pool.Pool[struct{}]{Debug: true}
+	a, b := p.Get(), p.Get()
+	p.Put(a)
+	p.Put(b)
+
+	sp := pool.NewSlicePool[struct{}](8, 64)
+	sp.Debug = true
+	s1, s2 := sp.Get(8), sp.Get(8)
+	sp.Put(s1)
+	sp.Put(s2)
+}
+
+func TestSlicePoolNegativeLength(t *testing.T) {
+	p := pool.NewSlicePool[int](8, 64)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Get(-1) did not panic")
+		}
+	}()
+	p.Get(-1)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"math/bits"
+	"sync"
+	"unsafe"
+)
+
+// SlicePool is a pool of []T buffers bucketed by capacity. Each bucket holds
+// slices whose capacity is a power of two, so a request for n elements is
+// served by the smallest bucket with a capacity of at least n.
+//
+// Putting a slice in a sync.Pool boxes the slice header, which costs an
+// allocation on every Put. SlicePool avoids this by storing the headers in
+// holders that are themselves pooled.
+//
+// The zero value is not usable, please use NewSlicePool.
+type SlicePool[T any] struct {
+
+	// Debug enables leak and double-put detection. It must be set before
+	// the pool is first used. Please see Leaks and DoublePutError.
+	//
+	// Debug has no effect if T has a size of zero, ex. struct{}, as distinct
+	// objects of such a type may share the same address.
+	Debug bool
+
+	minShift int
+	maxShift int
+	buckets  []sync.Pool
+
+	// holders is a pool of *[]T that are not holding a slice.
+	holders sync.Pool
+
+	t tracker
+}
+
+// NewSlicePool returns a new pool for slices with capacities from minSize to
+// maxSize, each rounded up to the next power of two.
+func NewSlicePool[T any](minSize, maxSize int) *SlicePool[T] {
+	if minSize < 1 {
+		minSize = 1
+	}
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+	p := &SlicePool[T]{
+		minShift: ceilLog2(minSize),
+		maxShift: ceilLog2(maxSize),
+	}
+	p.buckets = make([]sync.Pool, p.maxShift-p.minShift+1)
+	return p
+}
+
+// ceilLog2 returns the smallest s such that 1<<s >= n.
+func ceilLog2(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// Get returns a slice with a length of n. Its capacity is n rounded up to the
+// next bucket size, or exactly n if n is larger than the largest bucket, in
+// which case the slice is not pooled.
+//
+// Please note the contents of a slice returned by Get are not cleared, and
+// may contain the values written by a previous user of the slice.
+//
+// Get panics if n is negative.
+func (p *SlicePool[T]) Get(n int) []T {
+	if n < 0 {
+		panic("pool: SlicePool.Get called with a negative length")
+	}
+	shift := ceilLog2(n)
+	if shift > p.maxShift {
+		return make([]T, n)
+	}
+	if shift < p.minShift {
+		shift = p.minShift
+	}
+
+	var s []T
+	if v := p.buckets[shift-p.minShift].Get(); v != nil {
+		h := v.(*[]T)
+		s = (*h)[:n]
+		*h = nil
+		p.holders.Put(h)
+	} else {
+		s = make([]T, n, 1<<shift)
+	}
+	if p.Debug && !zeroSize[T]() {
+		p.t.get(slicePtr(s))
+	}
+	return s
+}
+
+// Put returns s to the pool. Slices whose capacity is not one of the pool's
+// bucket sizes are ignored. The caller must not use s after it has been
+// returned to the pool.
+//
+// If T contains pointers, consider clearing s before returning it so the
+// values it refers to may be garbage collected.
+//
+// In debug mode, Put panics with a *DoublePutError if s is already in the
+// pool.
+func (p *SlicePool[T]) Put(s []T) {
+	c := cap(s)
+	if c == 0 || c&(c-1) != 0 {
+		return
+	}
+	shift := bits.TrailingZeros(uint(c))
+	if shift < p.minShift || shift > p.maxShift {
+		return
+	}
+	if p.Debug && !zeroSize[T]() {
+		p.t.put(slicePtr(s))
+	}
+
+	h, _ := p.holders.Get().(*[]T)
+	if h == nil {
+		h = new([]T)
+	}
+	*h = s[:0]
+	p.buckets[shift-p.minShift].Put(h)
+}
+
+// Leaks returns the slices taken from the pool that have not been returned.
+// It always returns an empty slice if the pool is not in debug mode.
+func (p *SlicePool[T]) Leaks() []Leak {
+	return p.t.leaks()
+}
+
+// slicePtr returns a pointer to the first element of s's backing array, which
+// identifies the slice no matter its length.
+func slicePtr[T any](s []T) unsafe.Pointer {
+	return unsafe.Pointer(&s[:1][0])
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry is an opt-in registry of instantiated generic types, a
+// substitute for instantiating generic types at runtime.
+//
+// As "Internals" shows, Go cannot build a List[string] at runtime: generic
+// types do not exist once a program is compiled, only the instantiations
+// the program uses. A program can list those instantiations ahead of time
+// with Register, usually in an init function, which may also be generated:
+//
+//	func init() {
+//		registry.Register[list.List[int]]()
+//		registry.Register[list.List[string]]()
+//	}
+//
+// A plugin or config-driven system can then create an instantiation by the
+// names of its generic type and type arguments, ex. New("List", "string"),
+// and use it through an interface with NewAs, while the instantiation itself
+// stays as type-safe as if the program had named it.
+//
+// Type arguments may be qualified by package name or by full import path,
+// ex. string, []int, and either ledger.ID or
+// go-generics-the-hard-way/pkg/ledger.ID for `type ID string` in package
+// ledger. reflect and fmt's %T qualify them by import path, ex. in the
+// names of the types Types returns.
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-generics-the-hard-way/internal/typeargs"
+)
+
+var (
+	// ErrNotRegistered is returned by New when no instantiation with the
+	// given names is registered.
+	ErrNotRegistered = errors.New("registry: not registered")
+
+	// ErrAmbiguous is returned by New when the names match instantiations
+	// of generic types with the same name in more than one package.
+	ErrAmbiguous = errors.New("registry: ambiguous")
+)
+
+// entry is a registered instantiation.
+type entry struct {
+	typ     reflect.Type
+	pkg     string   // the package name, ex. list
+	generic string   // the generic type, ex. List
+	args    []string // the type arguments, qualified by import paths
+	new     func() interface{}
+}
+
+var (
+	mu sync.RWMutex
+
+	// entries are keyed by the name of the generic type without a
+	// package qualifier, ex. List.
+	entries = map[string][]*entry{}
+)
+
+// Register registers the instantiated generic type T, so New returns a
+// pointer to a new zero T. Registering a type again replaces its
+// constructor. Register panics if T is not an instantiated generic type.
+func Register[T any]() {
+	register(reflect.TypeOf((*T)(nil)).Elem(), func() interface{} { return new(T) })
+}
+
+// RegisterFunc registers the instantiated generic type T with a
+// constructor, for types whose zero value is not usable, ex. a Set[T] that
+// must be made by set.New.
+func RegisterFunc[T any](fn func() *T) {
+	register(reflect.TypeOf((*T)(nil)).Elem(), func() interface{} { return fn() })
+}
+
+func register(t reflect.Type, fn func() interface{}) {
+	e, err := newEntry(t)
+	if err != nil {
+		panic(err)
+	}
+	e.new = fn
+
+	mu.Lock()
+	defer mu.Unlock()
+	es := entries[e.generic]
+	for i, o := range es {
+		if o.typ == t {
+			es[i] = e
+			return
+		}
+	}
+	entries[e.generic] = append(es, e)
+}
+
+// newEntry parses the name of an instantiated generic type, ex.
+// List[go-generics-the-hard-way/pkg/ledger.ID].
+func newEntry(t reflect.Type) (*entry, error) {
+	name := t.Name()
+	open := strings.IndexByte(name, '[')
+	if open <= 0 || !strings.HasSuffix(name, "]") || t.PkgPath() == "" {
+		return nil, fmt.Errorf("registry: %s is not an instantiated generic type", t)
+	}
+	return &entry{
+		typ:     t,
+		pkg:     t.PkgPath()[strings.LastIndexByte(t.PkgPath(), '/')+1:],
+		generic: name[:open],
+		args:    typeargs.Split(name[open+1 : len(name)-1]),
+	}, nil
+}
+
+// matches reports whether the entry is the instantiation of the generic
+// type name with the type arguments args.
+func (e *entry) matches(name string, args []string) bool {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		if q := name[:i]; q != e.pkg && q != e.typ.PkgPath() {
+			return false
+		}
+	}
+	if len(args) != len(e.args) {
+		return false
+	}
+	for i, a := range args {
+		if a != e.args[i] && a != trimPaths(e.args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup returns the registered instantiation of the generic type name,
+// which may be qualified by its package's name or import path, ex. List,
+// list.List or go-generics-the-hard-way/pkg/list.List, with the type
+// arguments args.
+func lookup(name string, args []string) (*entry, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	var found *entry
+	for _, e := range entries[name[strings.LastIndexByte(name, '.')+1:]] {
+		if !e.matches(name, args) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: %s[%s] is both %s and %s",
+				ErrAmbiguous, name, strings.Join(args, ","), found.typ, e.typ)
+		}
+		found = e
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s[%s]", ErrNotRegistered, name, strings.Join(args, ","))
+	}
+	return found, nil
+}
+
+// Lookup returns the registered instantiation of the generic type name with
+// the type arguments args.
+func Lookup(name string, args ...string) (reflect.Type, error) {
+	e, err := lookup(name, args)
+	if err != nil {
+		return nil, err
+	}
+	return e.typ, nil
+}
+
+// New returns a new value of the registered instantiation of the generic
+// type name with the type arguments args, ex. a *list.List[string] for
+// New("List", "string"). The name may be qualified by the package's name or
+// import path to tell apart generic types with the same name.
+func New(name string, args ...string) (interface{}, error) {
+	e, err := lookup(name, args)
+	if err != nil {
+		return nil, err
+	}
+	return e.new(), nil
+}
+
+// NewAs is like New, but returns the value as the interface I, ex.
+//
+//	l, err := registry.NewAs[interface{ Add(...string) }]("List", "string")
+//
+// An error is returned if the value does not implement I.
+func NewAs[I any](name string, args ...string) (I, error) {
+	var zero I
+	v, err := New(name, args...)
+	if err != nil {
+		return zero, err
+	}
+	i, ok := v.(I)
+	if !ok {
+		return zero, fmt.Errorf("registry: %T does not implement %s", v, reflect.TypeOf((*I)(nil)).Elem())
+	}
+	return i, nil
+}
+
+// Types returns the registered instantiations, sorted by their names.
+func Types() []reflect.Type {
+	mu.RLock()
+	defer mu.RUnlock()
+	var types []reflect.Type
+	for _, es := range entries {
+		for _, e := range es {
+			types = append(types, e.typ)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+	return types
+}
+
+// trimPaths qualifies the identifiers in a type by their package names
+// rather than their import paths, ex. ledger.ID for
+// go-generics-the-hard-way/pkg/ledger.ID, so a type argument may be named
+// either way.
+func trimPaths(name string) string {
+	var b strings.Builder
+	for len(name) > 0 {
+		i := strings.IndexAny(name, "[]*(), ;{}")
+		if i < 0 {
+			i = len(name)
+		}
+		tok := name[:i]
+		if slash := strings.LastIndexByte(tok, '/'); slash >= 0 {
+			tok = tok[slash+1:]
+		}
+		b.WriteString(tok)
+		if i < len(name) {
+			b.WriteByte(name[i])
+			i++
+		}
+		name = name[i:]
+	}
+	return b.String()
+}
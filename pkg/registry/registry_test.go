@@ -0,0 +1,158 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/list"
+	"go-generics-the-hard-way/pkg/registry"
+	"go-generics-the-hard-way/pkg/set"
+)
+
+// ID is a type definition with an underlying type of string.
+type ID string
+
+// Pair has the same name as no other generic type, so it is only registered
+// by this file.
+type Pair[K comparable, V any] struct {
+	Key K
+	Val V
+}
+
+// List has the same name as list.List.
+type List[T any] struct {
+	vals []T
+}
+
+func init() {
+	registry.Register[list.List[int]]()
+	registry.Register[list.List[string]]()
+	registry.Register[list.List[ID]]()
+	registry.Register[Pair[string, map[string][]int]]()
+	registry.Register[List[float64]]()
+	registry.Register[List[string]]()
+	registry.RegisterFunc(func() *set.Set[string] {
+		s := set.New[string]()
+		return &s
+	})
+}
+
+func ExampleNew() {
+	v, err := registry.New("List", "int")
+	if err != nil {
+		panic(err)
+	}
+	l := v.(*list.List[int])
+	l.Add(1, 2, 3)
+	fmt.Printf("%T %v\n", v, *l)
+	// Output: *list.List[int] [1 2 3]
+}
+
+func ExampleNewAs() {
+	l, err := registry.NewAs[interface{ Add(...string) }]("list.List", "string")
+	if err != nil {
+		panic(err)
+	}
+	l.Add("Hello", "world")
+	fmt.Println(l)
+	// Output: &[Hello world]
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want interface{}
+		err  error
+	}{
+		{name: "list.List", args: []string{"int"}, want: new(list.List[int])},
+		{name: "go-generics-the-hard-way/pkg/list.List", args: []string{"int"}, want: new(list.List[int])},
+		{name: "List", args: []string{"int"}, want: new(list.List[int])},
+		{name: "List", args: []string{"registry_test.ID"}, want: new(list.List[ID])},
+		{name: "List", args: []string{"go-generics-the-hard-way/pkg/registry_test.ID"}, want: new(list.List[ID])},
+		{name: "List", args: []string{"float64"}, want: &List[float64]{}},
+		{name: "registry_test.List", args: []string{"string"}, want: &List[string]{}},
+		{name: "Pair", args: []string{"string", "map[string][]int"}, want: &Pair[string, map[string][]int]{}},
+		{name: "Set", args: []string{"string"}, want: func() *set.Set[string] { s := set.New[string](); return &s }()},
+		{name: "List", args: []string{"string"}, err: registry.ErrAmbiguous},
+		{name: "List", args: []string{"ID"}, err: registry.ErrNotRegistered},
+		{name: "List", args: []string{"bool"}, err: registry.ErrNotRegistered},
+		{name: "List", args: []string{"int", "int"}, err: registry.ErrNotRegistered},
+		{name: "set.List", args: []string{"int"}, err: registry.ErrNotRegistered},
+		{name: "Pair", args: []string{"string"}, err: registry.ErrNotRegistered},
+	}
+	for _, tc := range testCases {
+		got, err := registry.New(tc.name, tc.args...)
+		if !errors.Is(err, tc.err) || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("New(%q, %q) = %#v, %v, want %#v, %v", tc.name, tc.args, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestNewReturnsNewValues(t *testing.T) {
+	a, _ := registry.New("Set", "string")
+	b, _ := registry.New("Set", "string")
+	a.(*set.Set[string]).Add("a")
+	if b.(*set.Set[string]).Has("a") {
+		t.Error("New returned the same set twice")
+	}
+}
+
+func TestNewAs(t *testing.T) {
+	if _, err := registry.NewAs[interface{ Add(...int) }]("Pair", "string", "map[string][]int"); err == nil {
+		t.Error("NewAs returned a Pair as a list")
+	}
+	l, err := registry.NewAs[interface{ Len() int }]("List", "int")
+	if err != nil || l.Len() != 0 {
+		t.Errorf("NewAs() = %v, %v, want an empty list", l, err)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register[ID] did not panic")
+		}
+	}()
+	registry.Register[ID]()
+}
+
+func TestTypes(t *testing.T) {
+	// Registering a type again replaces it.
+	registry.Register[list.List[int]]()
+
+	var got []string
+	for _, typ := range registry.Types() {
+		got = append(got, typ.String())
+	}
+	want := []string{
+		"list.List[go-generics-the-hard-way/pkg/registry_test.ID]",
+		"list.List[int]",
+		"list.List[string]",
+		"registry_test.List[float64]",
+		"registry_test.List[string]",
+		"registry_test.Pair[string,map[string][]int]",
+		"set.Set[string]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Types() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package set provides a generic set built on top of a Go map.
+package set
+
+import (
+	"sort"
+
+	"go-generics-the-hard-way/pkg/constraints"
+	"go-generics-the-hard-way/pkg/wire"
+)
+
+// Set is a new type definition for map[T]struct{}.
+type Set[T comparable] map[T]struct{}
+
+// New returns a new set that contains the provided values.
+func New[T comparable](vals ...T) Set[T] {
+	s := make(Set[T], len(vals))
+	s.Add(vals...)
+	return s
+}
+
+// Add inserts the provided values into the set.
+func (s Set[T]) Add(vals ...T) {
+	for i := 0; i < len(vals); i++ {
+		s[vals[i]] = struct{}{}
+	}
+}
+
+// Has returns true if the set contains val.
+func (s Set[T]) Has(val T) bool {
+	_, ok := s[val]
+	return ok
+}
+
+// Remove deletes the provided values from the set.
+func (s Set[T]) Remove(vals ...T) {
+	for i := 0; i < len(vals); i++ {
+		delete(s, vals[i])
+	}
+}
+
+// Len returns the number of elements in the set.
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Clone returns a shallow copy of the set.
+func (s Set[T]) Clone() Set[T] {
+	c := make(Set[T], len(s))
+	for k := range s {
+		c[k] = struct{}{}
+	}
+	return c
+}
+
+// Union returns a new set with the elements from both s and other.
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	u := make(Set[T], len(s)+len(other))
+	for k := range s {
+		u[k] = struct{}{}
+	}
+	for k := range other {
+		u[k] = struct{}{}
+	}
+	return u
+}
+
+// Intersect returns a new set with the elements that are in both s and other.
+func (s Set[T]) Intersect(other Set[T]) Set[T] {
+	// Iterate over the smaller of the two sets.
+	a, b := s, other
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	i := make(Set[T])
+	for k := range a {
+		if _, ok := b[k]; ok {
+			i[k] = struct{}{}
+		}
+	}
+	return i
+}
+
+// Difference returns a new set with the elements in s that are not in other.
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	d := make(Set[T])
+	for k := range s {
+		if _, ok := other[k]; !ok {
+			d[k] = struct{}{}
+		}
+	}
+	return d
+}
+
+// SymmetricDifference returns a new set with the elements that are in either
+// s or other, but not in both.
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	d := make(Set[T])
+	for k := range s {
+		if _, ok := other[k]; !ok {
+			d[k] = struct{}{}
+		}
+	}
+	for k := range other {
+		if _, ok := s[k]; !ok {
+			d[k] = struct{}{}
+		}
+	}
+	return d
+}
+
+// IsSubset returns true if every element in s is also in other.
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for k := range s {
+		if _, ok := other[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns true if s and other contain the same elements.
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// Slice returns the elements of the set in an unspecified order.
+func (s Set[T]) Slice() []T {
+	vals := make([]T, 0, len(s))
+	for k := range s {
+		vals = append(vals, k)
+	}
+	return vals
+}
+
+// MarshalBinary encodes the set in the format described by package wire. The
+// elements are encoded in an unspecified order. A *wire.UnsupportedTypeError
+// is returned if T is not a number or a string.
+func (s Set[T]) MarshalBinary() ([]byte, error) {
+	c, err := wire.CodecFor[T]()
+	if err != nil {
+		return nil, err
+	}
+	b := wire.AppendHeader(nil, wire.Set, c.Type())
+	b = wire.AppendUvarint(b, uint64(len(s)))
+	for v := range s {
+		b = c.Append(b, v)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary replaces the contents of the set with the set encoded in
+// data by MarshalBinary.
+func (s *Set[T]) UnmarshalBinary(data []byte) error {
+	c, err := wire.CodecFor[T]()
+	if err != nil {
+		return err
+	}
+	r := wire.NewReader(data)
+	if err := r.Header(wire.Set, c.Type()); err != nil {
+		return err
+	}
+	vals, err := c.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := r.Close(); err != nil {
+		return err
+	}
+	*s = New(vals...)
+	return nil
+}
+
+// Sorted returns the elements of the set in ascending order.
+//
+// Please note Sorted is a function and not a method on Set[T] because a
+// method cannot further constrain the type parameters of its receiver, and
+// only a set of ordered values can be sorted.
+func Sorted[T constraints.Ordered](s Set[T]) []T {
+	vals := s.Slice()
+	sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+	return vals
+}
+
+// Each invokes fn for each element of the set in ascending order. Iteration
+// stops early if fn returns false.
+func Each[T constraints.Ordered](s Set[T], fn func(T) bool) {
+	vals := Sorted(s)
+	for i := 0; i < len(vals); i++ {
+		if !fn(vals[i]) {
+			return
+		}
+	}
+}
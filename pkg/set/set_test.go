@@ -0,0 +1,152 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/set"
+)
+
+// ID is a type definition with an underlying type of string.
+type ID string
+
+func ExampleSet_Add() {
+	s := set.New[int]()
+	s.Add(3, 1, 2, 1)
+	fmt.Println(s.Len(), s.Has(1), s.Has(4))
+	// Output: 3 true false
+}
+
+func ExampleSet_Remove() {
+	s := set.New(1, 2, 3)
+	s.Remove(2, 4)
+	fmt.Println(set.Sorted(s))
+	// Output: [1 3]
+}
+
+func ExampleSet_Union() {
+	a, b := set.New(1, 2, 3), set.New(3, 4)
+	fmt.Println(set.Sorted(a.Union(b)))
+	// Output: [1 2 3 4]
+}
+
+func ExampleSet_Intersect() {
+	a, b := set.New(1, 2, 3), set.New(3, 4, 2)
+	fmt.Println(set.Sorted(a.Intersect(b)))
+	// Output: [2 3]
+}
+
+func ExampleSet_Difference() {
+	a, b := set.New(1, 2, 3), set.New(3, 4)
+	fmt.Println(set.Sorted(a.Difference(b)))
+	fmt.Println(set.Sorted(b.Difference(a)))
+	// Output:
+	// [1 2]
+	// [4]
+}
+
+func ExampleSet_SymmetricDifference() {
+	a, b := set.New(1, 2, 3), set.New(3, 4)
+	fmt.Println(set.Sorted(a.SymmetricDifference(b)))
+	// Output: [1 2 4]
+}
+
+func ExampleSet_IsSubset() {
+	a, b := set.New(1, 2), set.New(1, 2, 3)
+	fmt.Println(a.IsSubset(b), b.IsSubset(a), a.IsSubset(a))
+	fmt.Println(set.New[int]().IsSubset(a))
+	// Output:
+	// true false true
+	// true
+}
+
+func ExampleSet_Equal() {
+	fmt.Println(set.New(1, 2).Equal(set.New(2, 1)))
+	fmt.Println(set.New(1, 2).Equal(set.New(1, 3)))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleSorted() {
+	// Sorted works with type definitions as well, such as ID.
+	s := set.New[ID]("acct-3", "acct-1", "acct-2")
+	fmt.Println(set.Sorted(s))
+	// Output: [acct-1 acct-2 acct-3]
+}
+
+func ExampleEach() {
+	s := set.New(5, 3, 9, 1)
+	set.Each(s, func(v int) bool {
+		fmt.Println(v)
+		return v < 5
+	})
+	// Output:
+	// 1
+	// 3
+	// 5
+}
+
+func ExampleSet_MarshalBinary() {
+	data, err := set.New[ID]("acct-1", "acct-2").MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var s set.Set[ID]
+	if err := s.UnmarshalBinary(data); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(len(data), set.Sorted(s))
+	// Output: 18 [acct-1 acct-2]
+}
+
+func FuzzSetRoundTrip(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := set.New[uint64]()
+		for i := 0; i+8 <= len(data); i += 8 {
+			s.Add(binary.LittleEndian.Uint64(data[i:]))
+		}
+		b, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out set.Set[uint64]
+		if err := out.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if !out.Equal(s) {
+			t.Fatalf("got %v, want %v", out, s)
+		}
+
+		// Arbitrary data must be rejected or decode to a set that
+		// survives a round trip.
+		if err := out.UnmarshalBinary(data); err == nil {
+			b, _ := out.MarshalBinary()
+			var again set.Set[uint64]
+			if err := again.UnmarshalBinary(b); err != nil || !again.Equal(out) {
+				t.Fatalf("got %v, %v, want %v", again, err, out)
+			}
+		}
+	})
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package trie provides a generic prefix tree keyed by any type with an
+// underlying type of string.
+//
+// Because the key is constrained by ~string instead of string, a trie may be
+// keyed by a type definition such as "type ID string", and values of that
+// type never have to be converted to or from a string by the caller.
+package trie
+
+import (
+	"sort"
+)
+
+type node[V any] struct {
+	label byte
+	val   V
+	set   bool
+
+	// count is the number of keys in the subtree rooted at this node,
+	// including the node itself.
+	count int
+
+	// children are sorted by label.
+	children []*node[V]
+}
+
+// child returns the index of the child with the provided label, and whether
+// such a child exists. If it does not, the index is where it would be
+// inserted.
+func (n *node[V]) child(label byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label >= label
+	})
+	return i, i < len(n.children) && n.children[i].label == label
+}
+
+// Trie is a prefix tree that maps keys to values.
+//
+// The zero value is an empty trie ready to use.
+type Trie[K ~string, V any] struct {
+	root node[V]
+}
+
+// New returns a new, empty trie.
+func New[K ~string, V any]() *Trie[K, V] {
+	return &Trie[K, V]{}
+}
+
+// Len returns the number of keys in the trie.
+func (t *Trie[K, V]) Len() int {
+	return t.root.count
+}
+
+// Insert sets the value for key and returns true if the key is new.
+func (t *Trie[K, V]) Insert(key K, val V) bool {
+	// Check whether the key exists first so the counts along the path only
+	// need to be updated once.
+	if n := t.find(key); n != nil && n.set {
+		n.val = val
+		return false
+	}
+
+	n := &t.root
+	n.count++
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			c := &node[V]{label: key[i]}
+			n.children = append(n.children, nil)
+			copy(n.children[j+1:], n.children[j:])
+			n.children[j] = c
+		}
+		n = n.children[j]
+		n.count++
+	}
+	n.val = val
+	n.set = true
+	return true
+}
+
+// Get returns the value for key. The second return value is false if the key
+// is not in the trie.
+func (t *Trie[K, V]) Get(key K) (V, bool) {
+	if n := t.find(key); n != nil && n.set {
+		return n.val, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes key from the trie and returns true if the key was present.
+// Nodes that no longer lead to a key are pruned.
+func (t *Trie[K, V]) Delete(key K) bool {
+	if n := t.find(key); n == nil || !n.set {
+		return false
+	}
+
+	n := &t.root
+	n.count--
+	for i := 0; i < len(key); i++ {
+		j, _ := n.child(key[i])
+		c := n.children[j]
+		if c.count == 1 {
+			// This is the only key below the child, so drop the whole
+			// subtree.
+			n.children = append(n.children[:j], n.children[j+1:]...)
+			return true
+		}
+		c.count--
+		n = c
+	}
+
+	var zero V
+	n.val = zero
+	n.set = false
+	return true
+}
+
+// CountPrefix returns the number of keys that begin with prefix.
+func (t *Trie[K, V]) CountPrefix(prefix K) int {
+	if n := t.find(prefix); n != nil {
+		return n.count
+	}
+	return 0
+}
+
+// WalkPrefix invokes fn in lexicographical order for each key that begins
+// with prefix. Walking stops early if fn returns false.
+func (t *Trie[K, V]) WalkPrefix(prefix K, fn func(key K, val V) bool) {
+	n := t.find(prefix)
+	if n == nil {
+		return
+	}
+	buf := make([]byte, len(prefix), len(prefix)+16)
+	copy(buf, prefix)
+	walk(n, buf, fn)
+}
+
+// Walk invokes fn in lexicographical order for each key in the trie. Walking
+// stops early if fn returns false.
+func (t *Trie[K, V]) Walk(fn func(key K, val V) bool) {
+	t.WalkPrefix("", fn)
+}
+
+// LongestPrefix returns the longest key in the trie that is a prefix of s.
+// The last return value is false if no key is a prefix of s.
+func (t *Trie[K, V]) LongestPrefix(s K) (K, V, bool) {
+	var (
+		match K
+		val   V
+		found bool
+	)
+	n := &t.root
+	if n.set {
+		val, found = n.val, true
+	}
+	for i := 0; i < len(s); i++ {
+		j, ok := n.child(s[i])
+		if !ok {
+			break
+		}
+		n = n.children[j]
+		if n.set {
+			match, val, found = s[:i+1], n.val, true
+		}
+	}
+	return match, val, found
+}
+
+// find returns the node for key, or nil if there is no such node.
+func (t *Trie[K, V]) find(key K) *node[V] {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			return nil
+		}
+		n = n.children[j]
+	}
+	return n
+}
+
+func walk[K ~string, V any](n *node[V], buf []byte, fn func(K, V) bool) bool {
+	if n.set && !fn(K(buf), n.val) {
+		return false
+	}
+	for _, c := range n.children {
+		if !walk(c, append(buf, c.label), fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trie_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/trie"
+)
+
+// ID is a type definition with an underlying type of string, just like the
+// ID used by the ledgers in "Structural constraints."
+type ID string
+
+func ExampleTrie_WalkPrefix() {
+	var t trie.Trie[ID, int]
+	t.Insert("acct-1", 1)
+	t.Insert("acct-10", 10)
+	t.Insert("acct-2", 2)
+	t.Insert("loan-1", 100)
+
+	// The keys passed to the function are IDs, not strings.
+	t.WalkPrefix("acct-1", func(id ID, v int) bool {
+		fmt.Printf("%T %s %d\n", id, id, v)
+		return true
+	})
+	fmt.Println(t.CountPrefix("acct-"), t.CountPrefix("loan-"), t.Len())
+	// Output:
+	// trie_test.ID acct-1 1
+	// trie_test.ID acct-10 10
+	// 3 1 4
+}
+
+func ExampleTrie_LongestPrefix() {
+	t := trie.New[ID, string]()
+	t.Insert("acct", "account")
+	t.Insert("acct-us", "US account")
+	fmt.Println(t.LongestPrefix("acct-us-1"))
+	fmt.Println(t.LongestPrefix("acct-eu-1"))
+	fmt.Println(t.LongestPrefix("loan-1"))
+	// Output:
+	// acct-us US account true
+	// acct account true
+	//   false
+}
+
+func ExampleTrie_Delete() {
+	t := trie.New[string, int]()
+	t.Insert("a", 1)
+	t.Insert("ab", 2)
+	t.Insert("abc", 3)
+	fmt.Println(t.Delete("ab"), t.Delete("ab"))
+	t.Walk(func(k string, v int) bool {
+		fmt.Println(k, v)
+		return true
+	})
+	fmt.Println(t.CountPrefix("a"), t.CountPrefix("ab"))
+	// Output:
+	// true false
+	// a 1
+	// abc 3
+	// 2 1
+}
+
+func TestTrie(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	tr := trie.New[ID, int]()
+	want := map[ID]int{}
+
+	randKey := func() ID {
+		var sb strings.Builder
+		for i, n := 0, r.Intn(6); i < n; i++ {
+			sb.WriteByte("abc"[r.Intn(3)])
+		}
+		return ID(sb.String())
+	}
+
+	for i := 0; i < 3000; i++ {
+		k := randKey()
+		if r.Intn(3) == 0 {
+			_, ok := want[k]
+			if got := tr.Delete(k); got != ok {
+				t.Fatalf("Delete(%q) = %v, want %v", k, got, ok)
+			}
+			delete(want, k)
+			continue
+		}
+		_, ok := want[k]
+		if got := tr.Insert(k, i); got == ok {
+			t.Fatalf("Insert(%q) = %v, want %v", k, got, !ok)
+		}
+		want[k] = i
+	}
+
+	if tr.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", tr.Len(), len(want))
+	}
+
+	for i := 0; i < 200; i++ {
+		prefix := randKey()
+		var keys []ID
+		for k := range want {
+			if strings.HasPrefix(string(k), string(prefix)) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+		if n := tr.CountPrefix(prefix); n != len(keys) {
+			t.Fatalf("CountPrefix(%q) = %d, want %d", prefix, n, len(keys))
+		}
+
+		var got []ID
+		tr.WalkPrefix(prefix, func(k ID, v int) bool {
+			if v != want[k] {
+				t.Fatalf("WalkPrefix(%q) value for %q = %d, want %d", prefix, k, v, want[k])
+			}
+			got = append(got, k)
+			return true
+		})
+		if fmt.Sprint(got) != fmt.Sprint(keys) {
+			t.Fatalf("WalkPrefix(%q) = %v, want %v", prefix, got, keys)
+		}
+	}
+}
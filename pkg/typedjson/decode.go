@@ -0,0 +1,725 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"unicode"
+	"unsafe"
+
+	"go-generics-the-hard-way/pkg/constraints"
+)
+
+// Decode decodes the JSON in data into a new value of type T.
+//
+// An *UnsupportedTypeError is returned if T is not supported. Otherwise, a
+// *DecodeError is returned if data is not valid JSON or cannot be decoded
+// into a T, in which case the returned value may be partially decoded.
+func Decode[T any](data []byte) (T, error) {
+	var v T
+	err := Unmarshal(data, &v)
+	return v, err
+}
+
+// Unmarshal decodes the JSON in data into the value v points to. As with
+// json.Unmarshal, slices are reset, new map entries are added to existing
+// maps, and struct fields that are not present in data are left unchanged.
+//
+// Please note, unlike json.Unmarshal, data is not validated before it is
+// decoded, so v may be partially decoded if an error is returned.
+func Unmarshal[T any](data []byte, v *T) error {
+	dec, err := decoderFor(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+	d := decodeState{data: data}
+	if err := dec.fn(&d, unsafe.Pointer(v)); err != nil {
+		return prependPath(err, "$")
+	}
+	if d.peek(); d.off < len(d.data) {
+		return prependPath(d.invalid("after top-level value"), "$")
+	}
+	return nil
+}
+
+// decodeFn decodes the next JSON value into the value at p.
+type decodeFn func(d *decodeState, p unsafe.Pointer) error
+
+// decoder is the compiled decoding plan for a type.
+type decoder struct {
+	fn decodeFn
+}
+
+var decoders planCache[decoder]
+
+// decoderFor returns the cached plan for t, compiling it if necessary.
+func decoderFor(t reflect.Type) (*decoder, error) {
+	return decoders.get(t, func(c *compiler[decoder], t reflect.Type) (*decoder, error) {
+		return decoderCompiler{c}.compile(t)
+	})
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// decoderCompiler compiles the decoders for a type and the types reachable
+// from it.
+type decoderCompiler struct {
+	*compiler[decoder]
+}
+
+func (c decoderCompiler) compile(t reflect.Type) (*decoder, error) {
+	dec, ok := c.plan(t)
+	if !ok {
+		return dec, nil
+	}
+
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return nil, &UnsupportedTypeError{Type: t, Reason: "implements json.Unmarshaler"}
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return nil, &UnsupportedTypeError{Type: t, Reason: "implements encoding.TextUnmarshaler"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		dec.fn = decodeBool(t)
+	case reflect.Int:
+		dec.fn = decodeInt[int](t)
+	case reflect.Int8:
+		dec.fn = decodeInt[int8](t)
+	case reflect.Int16:
+		dec.fn = decodeInt[int16](t)
+	case reflect.Int32:
+		dec.fn = decodeInt[int32](t)
+	case reflect.Int64:
+		dec.fn = decodeInt[int64](t)
+	case reflect.Uint:
+		dec.fn = decodeUint[uint](t)
+	case reflect.Uint8:
+		dec.fn = decodeUint[uint8](t)
+	case reflect.Uint16:
+		dec.fn = decodeUint[uint16](t)
+	case reflect.Uint32:
+		dec.fn = decodeUint[uint32](t)
+	case reflect.Uint64:
+		dec.fn = decodeUint[uint64](t)
+	case reflect.Uintptr:
+		dec.fn = decodeUint[uintptr](t)
+	case reflect.Float32:
+		dec.fn = decodeFloat[float32](t)
+	case reflect.Float64:
+		dec.fn = decodeFloat[float64](t)
+	case reflect.String:
+		// Any type whose underlying type is string, such as type ID string,
+		// has the same representation as a string, so it is decoded the same
+		// way.
+		dec.fn = decodeString(t)
+	case reflect.Pointer:
+		return c.compilePointer(t, dec)
+	case reflect.Slice:
+		return c.compileSlice(t, dec)
+	case reflect.Array:
+		return c.compileArray(t, dec)
+	case reflect.Map:
+		return c.compileMap(t, dec)
+	case reflect.Struct:
+		return c.compileStruct(t, dec)
+	default:
+		return nil, &UnsupportedTypeError{Type: t, Reason: t.Kind().String() + " kind"}
+	}
+	return dec, nil
+}
+
+func (c decoderCompiler) compilePointer(t reflect.Type, dec *decoder) (*decoder, error) {
+	elem, err := c.compile(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	dec.fn = func(d *decodeState, p unsafe.Pointer) error {
+		if d.peek() == 'n' {
+			if err := d.literal("null"); err != nil {
+				return err
+			}
+			*(*unsafe.Pointer)(p) = nil
+			return nil
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		ptr := *(*unsafe.Pointer)(p)
+		if ptr == nil {
+			ptr = reflect.New(t.Elem()).UnsafePointer()
+			*(*unsafe.Pointer)(p) = ptr
+		}
+		return elem.fn(d, ptr)
+	}
+	return dec, nil
+}
+
+func (c decoderCompiler) compileSlice(t reflect.Type, dec *decoder) (*decoder, error) {
+	et := t.Elem()
+	elem, err := c.compile(et)
+	if err != nil {
+		return nil, err
+	}
+	size, zero := et.Size(), reflect.Zero(et)
+
+	decodeArray := func(d *decodeState, p unsafe.Pointer) error {
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		d.off++
+		s := (*sliceHeader)(p)
+
+		// The elements from the slice's length to its capacity may hold old
+		// values and must be zeroed before they are reused, unless the slice
+		// has been reallocated.
+		oldLen, dirty := s.len, s.cap
+
+		n := 0
+		if d.peek() != ']' {
+			for {
+				if n == s.cap {
+					growSlice(t, s, n)
+					dirty = n
+				}
+				ep := unsafe.Add(s.data, uintptr(n)*size)
+				if n >= oldLen && n < dirty {
+					reflect.NewAt(et, ep).Elem().Set(zero)
+				}
+				if err := elem.fn(d, ep); err != nil {
+					return prependPath(err, "["+strconv.Itoa(n)+"]")
+				}
+				n++
+				if d.peek() == ']' {
+					break
+				}
+				if err := d.consume(',', "after array element"); err != nil {
+					return err
+				}
+			}
+		}
+		d.off++
+
+		// As with encoding/json, an empty array is decoded as an empty slice
+		// rather than a nil slice.
+		if s.data == nil {
+			s.data = reflect.MakeSlice(t, 0, 0).UnsafePointer()
+		}
+		s.len = n
+		return nil
+	}
+
+	// As with encoding/json, a []byte is decoded from a base64 string.
+	isBytes := et.Kind() == reflect.Uint8 &&
+		!reflect.PointerTo(et).Implements(unmarshalerType) &&
+		!reflect.PointerTo(et).Implements(textUnmarshalerType)
+
+	dec.fn = func(d *decodeState, p unsafe.Pointer) error {
+		switch d.peek() {
+		case 'n':
+			if err := d.literal("null"); err != nil {
+				return err
+			}
+			*(*sliceHeader)(p) = sliceHeader{}
+			return nil
+		case '[':
+			return decodeArray(d, p)
+		case '"':
+			if isBytes {
+				return decodeBytes(d, p)
+			}
+		}
+		return d.typeError("", t)
+	}
+	return dec, nil
+}
+
+// growSlice replaces the backing array of the slice s, of type t, with one
+// that is larger, copying the first n elements.
+func growSlice(t reflect.Type, s *sliceHeader, n int) {
+	c := 2 * s.cap
+	if c < 4 {
+		c = 4
+	}
+	ns := reflect.MakeSlice(t, c, c)
+	if n > 0 {
+		reflect.Copy(ns, reflect.NewAt(t, unsafe.Pointer(s)).Elem().Slice(0, n))
+	}
+	s.data, s.cap = ns.UnsafePointer(), c
+}
+
+func decodeBytes(d *decodeState, p unsafe.Pointer) error {
+	d.skipSpace()
+	start := d.off
+	b, err := d.str()
+	if err != nil {
+		return err
+	}
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(b)))
+	n, err := base64.StdEncoding.Decode(out, b)
+	if err != nil {
+		return &DecodeError{Offset: int64(start), Err: err}
+	}
+	*(*[]byte)(p) = out[:n]
+	return nil
+}
+
+func (c decoderCompiler) compileArray(t reflect.Type, dec *decoder) (*decoder, error) {
+	et := t.Elem()
+	elem, err := c.compile(et)
+	if err != nil {
+		return nil, err
+	}
+	n, size, zero := t.Len(), et.Size(), reflect.Zero(et)
+
+	dec.fn = func(d *decodeState, p unsafe.Pointer) error {
+		if ok, err := d.null(); ok || err != nil {
+			return err
+		}
+		if d.peek() != '[' {
+			return d.typeError("", t)
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		d.off++
+
+		// As with encoding/json, extra elements are ignored and missing
+		// elements are zeroed.
+		i := 0
+		if d.peek() != ']' {
+			for {
+				if i < n {
+					if err := elem.fn(d, unsafe.Add(p, uintptr(i)*size)); err != nil {
+						return prependPath(err, "["+strconv.Itoa(i)+"]")
+					}
+				} else if err := d.skip(); err != nil {
+					return err
+				}
+				i++
+				if d.peek() == ']' {
+					break
+				}
+				if err := d.consume(',', "after array element"); err != nil {
+					return err
+				}
+			}
+		}
+		d.off++
+		for ; i < n; i++ {
+			reflect.NewAt(et, unsafe.Add(p, uintptr(i)*size)).Elem().Set(zero)
+		}
+		return nil
+	}
+	return dec, nil
+}
+
+func (c decoderCompiler) compileMap(t reflect.Type, dec *decoder) (*decoder, error) {
+	kt, et := t.Key(), t.Elem()
+
+	var setKey func(k reflect.Value, b []byte) bool
+	switch kt.Kind() {
+	case reflect.String:
+		setKey = func(k reflect.Value, b []byte) bool {
+			k.SetString(string(b))
+			return true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		setKey = func(k reflect.Value, b []byte) bool {
+			n, ok := parseInt(b)
+			if !ok || k.OverflowInt(n) {
+				return false
+			}
+			k.SetInt(n)
+			return true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		setKey = func(k reflect.Value, b []byte) bool {
+			n, ok := parseUint(b)
+			if !ok || k.OverflowUint(n) {
+				return false
+			}
+			k.SetUint(n)
+			return true
+		}
+	default:
+		return nil, &UnsupportedTypeError{Type: t, Reason: "map key must be a string or an integer"}
+	}
+	elem, err := c.compile(et)
+	if err != nil {
+		return nil, err
+	}
+	zero := reflect.Zero(et)
+
+	// As with encoding, maps are accessed with reflect. Each value is decoded
+	// into a reusable variable, which is then copied into the map.
+	dec.fn = func(d *decodeState, p unsafe.Pointer) error {
+		switch d.peek() {
+		case 'n':
+			if err := d.literal("null"); err != nil {
+				return err
+			}
+			*(*unsafe.Pointer)(p) = nil
+			return nil
+		case '{':
+			if err := d.enter(); err != nil {
+				return err
+			}
+			defer d.leave()
+			d.off++
+		default:
+			return d.typeError("", t)
+		}
+
+		m := reflect.NewAt(t, p).Elem()
+		if m.IsNil() {
+			m.Set(reflect.MakeMap(t))
+		}
+		if d.peek() == '}' {
+			d.off++
+			return nil
+		}
+
+		k := reflect.New(kt).Elem()
+		v := reflect.New(et)
+		vp, v := v.UnsafePointer(), v.Elem()
+		for {
+			d.skipSpace()
+			start := d.off
+			b, err := d.str()
+			if err != nil {
+				return err
+			}
+			if !setKey(k, b) {
+				d.off = start
+				return d.typeError("number "+string(b), kt)
+			}
+			if err := d.consume(':', "after object key"); err != nil {
+				return err
+			}
+			v.Set(zero)
+			if err := elem.fn(d, vp); err != nil {
+				return prependPath(err, pathKey(fmt.Sprint(k)))
+			}
+			m.SetMapIndex(k, v)
+
+			if d.peek() == '}' {
+				d.off++
+				return nil
+			}
+			if err := d.consume(',', "after object key:value pair"); err != nil {
+				return err
+			}
+		}
+	}
+	return dec, nil
+}
+
+// fieldDecoder is the compiled plan for a struct field.
+type fieldDecoder struct {
+	field
+	nameBytes []byte
+	dec       *decoder
+}
+
+func (c decoderCompiler) compileStruct(t reflect.Type, dec *decoder) (*decoder, error) {
+	fields, err := structFields(t)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]fieldDecoder, len(fields))
+	byName := make(map[string]int, len(fields))
+	for i, f := range fields {
+		fdec, err := c.compile(f.typ)
+		if err != nil {
+			return nil, err
+		}
+		plan[i] = fieldDecoder{field: f, nameBytes: []byte(f.name), dec: fdec}
+		byName[f.name] = i
+	}
+
+	// As with encoding/json, keys are matched to fields exactly if possible,
+	// and otherwise without regard to case.
+	lookup := func(key []byte) *fieldDecoder {
+		if i, ok := byName[string(key)]; ok {
+			return &plan[i]
+		}
+		for i := range plan {
+			if bytes.EqualFold(plan[i].nameBytes, key) {
+				return &plan[i]
+			}
+		}
+		return nil
+	}
+
+	dec.fn = func(d *decodeState, p unsafe.Pointer) error {
+		if ok, err := d.null(); ok || err != nil {
+			return err
+		}
+		if d.peek() != '{' {
+			return d.typeError("", t)
+		}
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		d.off++
+		if d.peek() == '}' {
+			d.off++
+			return nil
+		}
+		for {
+			key, err := d.str()
+			if err != nil {
+				return err
+			}
+			f := lookup(key)
+			if err := d.consume(':', "after object key"); err != nil {
+				return err
+			}
+			if f == nil {
+				if err := d.skip(); err != nil {
+					return err
+				}
+			} else {
+				fp := unsafe.Add(p, f.offset)
+				if f.quoted {
+					err = decodeQuoted(d, f, fp)
+				} else {
+					err = f.dec.fn(d, fp)
+				}
+				if err != nil {
+					return prependPath(err, pathKey(f.name))
+				}
+			}
+
+			if d.peek() == '}' {
+				d.off++
+				return nil
+			}
+			if err := d.consume(',', "after object key:value pair"); err != nil {
+				return err
+			}
+		}
+	}
+	return dec, nil
+}
+
+// decodeQuoted decodes a field with the string tag option, whose value is
+// encoded in a JSON string.
+func decodeQuoted(d *decodeState, f *fieldDecoder, p unsafe.Pointer) error {
+	if ok, err := d.null(); ok || err != nil {
+		return err
+	}
+	if d.peek() != '"' {
+		return d.typeError("", f.typ)
+	}
+	start := d.off
+	b, err := d.str()
+	if err != nil {
+		return err
+	}
+	inner := decodeState{data: b}
+	err = f.dec.fn(&inner, p)
+	if inner.skipSpace(); err != nil || inner.off < len(b) {
+		return &DecodeError{
+			Offset: int64(start),
+			Err:    &TypeError{Value: "string " + strconv.Quote(string(b)), Type: f.typ},
+		}
+	}
+	return nil
+}
+
+// pathKey returns the path element for an object member.
+func pathKey(name string) string {
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return "[" + strconv.Quote(name) + "]"
+		}
+	}
+	if name == "" {
+		return `[""]`
+	}
+	return "." + name
+}
+
+func decodeBool(t reflect.Type) decodeFn {
+	return func(d *decodeState, p unsafe.Pointer) error {
+		switch d.peek() {
+		case 't':
+			if err := d.literal("true"); err != nil {
+				return err
+			}
+			*(*bool)(p) = true
+			return nil
+		case 'f':
+			if err := d.literal("false"); err != nil {
+				return err
+			}
+			*(*bool)(p) = false
+			return nil
+		case 'n':
+			return d.literal("null")
+		}
+		return d.typeError("", t)
+	}
+}
+
+func decodeInt[T constraints.Signed](t reflect.Type) decodeFn {
+	return func(d *decodeState, p unsafe.Pointer) error {
+		if ok, err := d.null(); ok || err != nil {
+			return err
+		}
+		if c := d.peek(); c != '-' && (c < '0' || c > '9') {
+			return d.typeError("", t)
+		}
+		start := d.off
+		b, err := d.number()
+		if err != nil {
+			return err
+		}
+		n, ok := parseInt(b)
+		if !ok || int64(T(n)) != n {
+			d.off = start
+			return d.typeError("number "+string(b), t)
+		}
+		*(*T)(p) = T(n)
+		return nil
+	}
+}
+
+func decodeUint[T constraints.Unsigned | ~uintptr](t reflect.Type) decodeFn {
+	return func(d *decodeState, p unsafe.Pointer) error {
+		if ok, err := d.null(); ok || err != nil {
+			return err
+		}
+		if c := d.peek(); c != '-' && (c < '0' || c > '9') {
+			return d.typeError("", t)
+		}
+		start := d.off
+		b, err := d.number()
+		if err != nil {
+			return err
+		}
+		n, ok := parseUint(b)
+		if !ok || uint64(T(n)) != n {
+			d.off = start
+			return d.typeError("number "+string(b), t)
+		}
+		*(*T)(p) = T(n)
+		return nil
+	}
+}
+
+func decodeFloat[T constraints.Float](t reflect.Type) decodeFn {
+	bits := int(unsafe.Sizeof(T(0)) * 8)
+	return func(d *decodeState, p unsafe.Pointer) error {
+		if ok, err := d.null(); ok || err != nil {
+			return err
+		}
+		if c := d.peek(); c != '-' && (c < '0' || c > '9') {
+			return d.typeError("", t)
+		}
+		start := d.off
+		b, err := d.number()
+		if err != nil {
+			return err
+		}
+
+		// The text of the number is not copied to a new string, as the string
+		// does not outlive the call to ParseFloat.
+		f, err := strconv.ParseFloat(*(*string)(unsafe.Pointer(&b)), bits)
+		if err != nil {
+			d.off = start
+			return d.typeError("number "+string(b), t)
+		}
+		*(*T)(p) = T(f)
+		return nil
+	}
+}
+
+func decodeString(t reflect.Type) decodeFn {
+	return func(d *decodeState, p unsafe.Pointer) error {
+		if ok, err := d.null(); ok || err != nil {
+			return err
+		}
+		if d.peek() != '"' {
+			return d.typeError("", t)
+		}
+		b, err := d.str()
+		if err != nil {
+			return err
+		}
+		*(*string)(p) = string(b)
+		return nil
+	}
+}
+
+// parseUint parses the digits in b. It returns false if b contains anything
+// other than digits or the result overflows.
+func parseUint(b []byte) (uint64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	var n uint64
+	for _, c := range b {
+		if c < '0' || c > '9' || n > math.MaxUint64/10 {
+			return 0, false
+		}
+		n1 := n*10 + uint64(c-'0')
+		if n1 < n {
+			return 0, false
+		}
+		n = n1
+	}
+	return n, true
+}
+
+// parseInt parses the optionally signed digits in b. It returns false if b
+// contains anything else or the result overflows.
+func parseInt(b []byte) (int64, bool) {
+	neg := len(b) > 0 && b[0] == '-'
+	if neg {
+		b = b[1:]
+	}
+	u, ok := parseUint(b)
+	switch {
+	case !ok:
+		return 0, false
+	case neg && u > 1<<63:
+		return 0, false
+	case neg:
+		return -int64(u), true
+	case u > math.MaxInt64:
+		return 0, false
+	}
+	return int64(u), true
+}
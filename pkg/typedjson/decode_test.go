@@ -0,0 +1,368 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"go-generics-the-hard-way/pkg/typedjson"
+)
+
+type Invoice struct {
+	Customer ID
+	Amounts  []int64
+	Lines    map[ID]Item `json:"lines,omitempty"`
+}
+
+func ExampleDecode() {
+	o, err := typedjson.Decode[Order]([]byte(`{
+		"id": "o-1",
+		"items": [{"sku": "apple", "qty": 3, "price": 0.5}],
+		"paid": true
+	}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(o.ID, o.Items[0].SKU, o.Items[0].Quantity, o.Paid)
+
+	_, err = typedjson.Decode[Invoice]([]byte(`{"Amounts": [1, 2, 3, "4"]}`))
+	fmt.Println(err)
+	// Output:
+	// o-1 apple 3 true
+	// typedjson: $.Amounts[3]: cannot decode string into Go value of type int64 (offset 22)
+}
+
+func ExampleDecoder() {
+	r := strings.NewReader(`
+		{"sku": "apple", "qty": 3}
+		{"sku": "pear", "qty": 1}
+	`)
+	dec, err := typedjson.NewDecoder[Item](r, typedjson.NDJSON)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for {
+		var item Item
+		if err := dec.Decode(&item); err == io.EOF {
+			break
+		} else if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(item.SKU, item.Quantity)
+	}
+	// Output:
+	// apple 3
+	// pear 1
+}
+
+// sameDecode checks that typedjson and encoding/json decode data into a T the
+// same way.
+func sameDecode[T any](t *testing.T, data string) {
+	t.Helper()
+	var want, got T
+	if err := json.Unmarshal([]byte(data), &want); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %v", data, err)
+	}
+	if err := typedjson.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("Unmarshal(%s) failed: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal(%s)\n got %#v\nwant %#v", data, got, want)
+	}
+}
+
+func TestDecodeMatchesEncodingJSON(t *testing.T) {
+	sameDecode[bool](t, `true`)
+	sameDecode[int8](t, `-128`)
+	sameDecode[uint64](t, `18446744073709551615`)
+	sameDecode[int64](t, `-9223372036854775808`)
+	sameDecode[float32](t, `3.4e38`)
+	sameDecode[float64](t, ` -1.5e-7 `)
+	sameDecode[ID](t, `"id"`)
+	sameDecode[string](t, `"escapes \" \\ \/ \b \f \n \r \t é 😀 \ud83d lone"`)
+	sameDecode[string](t, "\"invalid \xff utf-8\"")
+	sameDecode[*int](t, `null`)
+	sameDecode[*int](t, `1`)
+	sameDecode[[]int](t, `null`)
+	sameDecode[[]int](t, `[]`)
+	sameDecode[[]int](t, `[1, 2, 3, 4, 5, 6, 7, 8, 9]`)
+	sameDecode[[]byte](t, `"aGVsbG8="`)
+	sameDecode[[]byte](t, `[104, 105]`)
+	sameDecode[[2]int](t, `[1]`)
+	sameDecode[[2]int](t, `[1, 2, 3]`)
+	sameDecode[map[ID][]ID](t, `{"a": ["b", "c"], "d": null}`)
+	sameDecode[map[int8]bool](t, `{"-1": true, "2": false}`)
+	sameDecode[Order](t, `{"id": "o", "items": [{"sku": "a"}], "tags": {"k": "v"}, "discount": 0.1, "unknown": [{"x": [1, {}]}]}`)
+	sameDecode[Order](t, `{"ID": "case", "ITEMS": null, "Paid": true}`)
+	sameDecode[Shadow](t, `{"A": "a", "b": "b", "Num": "42", "Str": "\"s\"", "Arr": [1, 2], "Bytes": "AQI="}`)
+	sameDecode[Node](t, `{"Value": 1, "Children": [{"Value": 2}, null, {"Value": 3, "Children": []}]}`)
+}
+
+func TestUnmarshalReusesValue(t *testing.T) {
+	o := Order{ID: "keep", Items: make([]Item, 4, 8), Tags: map[string]string{"old": "x"}}
+	o.Items[1].SKU = "stale"
+	items := o.Items
+	if err := typedjson.Unmarshal([]byte(`{"items": [{"qty": 1}, {"qty": 2}], "tags": {"new": "y"}}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.ID != "keep" {
+		t.Errorf("ID = %q, want it unchanged", o.ID)
+	}
+	if &o.Items[0] != &items[0] || len(o.Items) != 2 {
+		t.Errorf("Items was not reused and resliced")
+	}
+	if o.Items[1].SKU != "stale" || o.Items[1].Quantity != 2 {
+		t.Errorf("Items[1] = %+v, want the existing element decoded into", o.Items[1])
+	}
+	if len(o.Tags) != 2 {
+		t.Errorf("Tags = %v, want the old and new entries", o.Tags)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		decode func() error
+		path   string
+		syntax bool
+	}{
+		{
+			name:   "element type",
+			decode: func() error { _, err := typedjson.Decode[Invoice]([]byte(`{"Amounts": [1, 2, 3, true]}`)); return err },
+			path:   "$.Amounts[3]",
+		},
+		{
+			name:   "overflow",
+			decode: func() error { _, err := typedjson.Decode[[]int8]([]byte(`[1, 128]`)); return err },
+			path:   "$[1]",
+		},
+		{
+			name:   "fraction",
+			decode: func() error { _, err := typedjson.Decode[[]int]([]byte(`[1.5]`)); return err },
+			path:   "$[0]",
+		},
+		{
+			name: "map value",
+			decode: func() error {
+				_, err := typedjson.Decode[Invoice]([]byte(`{"lines": {"a-1": {"qty": "1"}}}`))
+				return err
+			},
+			path: `$.lines["a-1"].qty`,
+		},
+		{
+			name:   "map key",
+			decode: func() error { _, err := typedjson.Decode[map[uint8]int]([]byte(`{"256": 1}`)); return err },
+			path:   "$",
+		},
+		{
+			name:   "object",
+			decode: func() error { _, err := typedjson.Decode[Invoice]([]byte(`[]`)); return err },
+			path:   "$",
+		},
+		{
+			name:   "syntax",
+			decode: func() error { _, err := typedjson.Decode[Invoice]([]byte(`{"Amounts": [1 2]}`)); return err },
+			path:   "$.Amounts",
+			syntax: true,
+		},
+		{
+			name:   "truncated",
+			decode: func() error { _, err := typedjson.Decode[Invoice]([]byte(`{"Customer": "a`)); return err },
+			path:   "$.Customer",
+			syntax: true,
+		},
+		{
+			name:   "trailing data",
+			decode: func() error { _, err := typedjson.Decode[int]([]byte(`1 2`)); return err },
+			path:   "$",
+			syntax: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.decode()
+			var de *typedjson.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("got %v, want a *DecodeError", err)
+			}
+			if de.Path != tc.path {
+				t.Errorf("Path = %q, want %q", de.Path, tc.path)
+			}
+			var te *typedjson.TypeError
+			if tc.syntax && !errors.Is(err, typedjson.ErrSyntax) {
+				t.Errorf("got %v, want a syntax error", err)
+			} else if !tc.syntax && !errors.As(err, &te) {
+				t.Errorf("got %v, want a *TypeError", err)
+			}
+		})
+	}
+}
+
+type tree struct {
+	Kids []tree
+}
+
+func TestDecodeMaxDepth(t *testing.T) {
+	// nest returns value wrapped in n pairs of open and close.
+	nest := func(n int, open, value, close string) []byte {
+		return []byte(strings.Repeat(open, n) + value + strings.Repeat(close, n))
+	}
+	testCases := []struct {
+		name   string
+		decode func() error
+	}{
+		{
+			name: "skipped array",
+			decode: func() error {
+				_, err := typedjson.Decode[struct{}](nest(10001, `{"x":[`, "", `]}`))
+				return err
+			},
+		},
+		{
+			name: "skipped object",
+			decode: func() error {
+				_, err := typedjson.Decode[[0]int](nest(10001, `[{"x":`, "1", `}]`))
+				return err
+			},
+		},
+		{
+			name: "slice and struct",
+			decode: func() error {
+				_, err := typedjson.Decode[tree](nest(10001, `{"Kids":[`, "", `]}`))
+				return err
+			},
+		},
+		{
+			name: "pointer",
+			decode: func() error {
+				_, err := typedjson.Decode[recursivePtr]([]byte(`1`))
+				return err
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.decode()
+			if !errors.Is(err, typedjson.ErrSyntax) || !strings.Contains(err.Error(), "max depth") {
+				t.Fatalf("got %v, want a max depth syntax error", err)
+			}
+		})
+	}
+
+	// Input nested within the limit still decodes.
+	if _, err := typedjson.Decode[tree](nest(1000, `{"Kids":[`, "", `]}`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recursivePtr can only be decoded from null, as anything else would
+// allocate pointers forever.
+type recursivePtr *recursivePtr
+
+func decodeAll[T any](t *testing.T, r io.Reader, format typedjson.Format) ([]T, []error) {
+	t.Helper()
+	dec, err := typedjson.NewDecoder[T](r, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		vals []T
+		errs []error
+	)
+	for {
+		var v T
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			return vals, errs
+		}
+		if err != nil {
+			errs = append(errs, err)
+			if !errors.As(err, new(*typedjson.TypeError)) {
+				return vals, errs
+			}
+			continue
+		}
+		vals = append(vals, v)
+	}
+}
+
+func TestDecoderArray(t *testing.T) {
+	const data = ` [ {"Amounts": [1, 2]}, {"Customer": "b", "Amounts": [3, "4"]}, {"Customer": "c"} ] `
+	vals, errs := decodeAll[Invoice](t, iotest.OneByteReader(strings.NewReader(data)), typedjson.Array)
+	if len(vals) != 2 || vals[0].Amounts[1] != 2 || vals[1].Customer != "c" {
+		t.Errorf("got %+v", vals)
+	}
+	var de *typedjson.DecodeError
+	if len(errs) != 1 || !errors.As(errs[0], &de) || de.Path != "$[1].Amounts[1]" {
+		t.Fatalf("got errors %v, want one at $[1].Amounts[1]", errs)
+	}
+	if got, want := de.Offset, int64(strings.Index(data, `"4"`)); got != want {
+		t.Errorf("Offset = %d, want %d", got, want)
+	}
+
+	for _, data := range []string{`[]`, ` [ ] `} {
+		if vals, errs := decodeAll[int](t, strings.NewReader(data), typedjson.Array); len(vals) != 0 || len(errs) != 0 {
+			t.Errorf("%q: got %v, %v, want no values", data, vals, errs)
+		}
+	}
+	for _, data := range []string{``, `{}`, `[1, 2`, `[1 2]`, `[1,]`} {
+		_, errs := decodeAll[int](t, strings.NewReader(data), typedjson.Array)
+		if len(errs) != 1 || !errors.Is(errs[0], typedjson.ErrSyntax) {
+			t.Errorf("%q: got %v, want a syntax error", data, errs)
+		}
+	}
+}
+
+func TestDecoderNDJSON(t *testing.T) {
+	const data = "1\n-2\n\"3\"\n40000000000\n5"
+	vals, errs := decodeAll[int32](t, iotest.OneByteReader(strings.NewReader(data)), typedjson.NDJSON)
+	if !reflect.DeepEqual(vals, []int32{1, -2, 5}) {
+		t.Errorf("got %v, want [1 -2 5]", vals)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got errors %v, want 2", errs)
+	}
+
+	var big strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&big, `{"Customer": "c%d", "Amounts": [%d]}`+"\n", i, i)
+	}
+	vals2, errs := decodeAll[Invoice](t, strings.NewReader(big.String()), typedjson.NDJSON)
+	if len(errs) != 0 || len(vals2) != 10000 || vals2[9999].Customer != "c9999" {
+		t.Fatalf("got %d values and errors %v", len(vals2), errs)
+	}
+}
+
+func TestUnsupportedDecodeTypes(t *testing.T) {
+	var uerr *typedjson.UnsupportedTypeError
+	if _, err := typedjson.Decode[withMarshaler](nil); !errors.As(err, &uerr) {
+		t.Errorf("got %v, want an *UnsupportedTypeError", err)
+	}
+	if _, err := typedjson.NewDecoder[[]any](nil, typedjson.Array); !errors.As(err, &uerr) {
+		t.Errorf("got %v, want an *UnsupportedTypeError", err)
+	}
+}
@@ -0,0 +1,485 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package typedjson encodes and decodes values of a closed set of types to
+// and from JSON without passing them through the empty interface.
+//
+// The encoding/json package takes values as an interface{} and walks them with
+// reflect, which boxes values along the way. An Encoder[T] or Decoder[T]
+// instead compiles a plan for T once, using reflect only to inspect the type,
+// and then encodes or decodes values of T by accessing their memory directly.
+// Plans are cached, so each type is only compiled once.
+//
+// The supported types are booleans, integers, floats, strings, pointers,
+// slices, arrays, maps with string or integer keys, and structs whose fields
+// are supported types. Types defined in terms of these, such as
+// type ID string, are supported too. Interfaces, channels, functions, complex
+// numbers, and types that implement the marshaler or unmarshaler interfaces
+// of encoding/json or encoding are not supported. Values are encoded and
+// decoded as encoding/json would, including struct tags and map key ordering.
+package typedjson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unsafe"
+
+	"go-generics-the-hard-way/pkg/constraints"
+)
+
+// Encoder encodes values of type T to JSON.
+type Encoder[T any] struct {
+	enc *encoder
+}
+
+// NewEncoder returns an encoder for T. The plan for T is compiled the first
+// time an encoder for T is requested and is shared by all encoders for T.
+//
+// An *UnsupportedTypeError is returned if T is not supported.
+func NewEncoder[T any]() (*Encoder[T], error) {
+	enc, err := encoderFor(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return &Encoder[T]{enc: enc}, nil
+}
+
+// Append appends the JSON encoding of the value v points to to dst and
+// returns the extended buffer. A nil v is encoded as null.
+//
+// Please note v is a pointer so the value is neither copied nor boxed.
+func (e *Encoder[T]) Append(dst []byte, v *T) ([]byte, error) {
+	if v == nil {
+		return append(dst, "null"...), nil
+	}
+	return e.enc.fn(dst, unsafe.Pointer(v))
+}
+
+// Marshal returns the JSON encoding of the value v points to.
+func (e *Encoder[T]) Marshal(v *T) ([]byte, error) {
+	return e.Append(nil, v)
+}
+
+// Marshal returns the JSON encoding of the value v points to. It is
+// shorthand for NewEncoder[T] followed by Encoder.Marshal.
+func Marshal[T any](v *T) ([]byte, error) {
+	e, err := NewEncoder[T]()
+	if err != nil {
+		return nil, err
+	}
+	return e.Marshal(v)
+}
+
+// encodeFn appends the JSON encoding of the value at p to dst.
+type encodeFn func(dst []byte, p unsafe.Pointer) ([]byte, error)
+
+// emptyFn reports whether the value at p is empty for the purposes of the
+// omitempty struct tag option.
+type emptyFn func(p unsafe.Pointer) bool
+
+// encoder is the compiled plan for a type.
+type encoder struct {
+	fn    encodeFn
+	empty emptyFn
+}
+
+var encoders planCache[encoder]
+
+// encoderFor returns the cached plan for t, compiling it if necessary.
+func encoderFor(t reflect.Type) (*encoder, error) {
+	return encoders.get(t, func(c *compiler[encoder], t reflect.Type) (*encoder, error) {
+		return encoderCompiler{c}.compile(t)
+	})
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encoderCompiler compiles the encoders for a type and the types reachable
+// from it.
+type encoderCompiler struct {
+	*compiler[encoder]
+}
+
+func (c encoderCompiler) compile(t reflect.Type) (*encoder, error) {
+	enc, ok := c.plan(t)
+	if !ok {
+		return enc, nil
+	}
+
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return nil, &UnsupportedTypeError{Type: t, Reason: "implements json.Marshaler"}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return nil, &UnsupportedTypeError{Type: t, Reason: "implements encoding.TextMarshaler"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		enc.fn, enc.empty = encodeBool, isZero[bool]
+	case reflect.Int:
+		enc.fn, enc.empty = encodeInt[int], isZero[int]
+	case reflect.Int8:
+		enc.fn, enc.empty = encodeInt[int8], isZero[int8]
+	case reflect.Int16:
+		enc.fn, enc.empty = encodeInt[int16], isZero[int16]
+	case reflect.Int32:
+		enc.fn, enc.empty = encodeInt[int32], isZero[int32]
+	case reflect.Int64:
+		enc.fn, enc.empty = encodeInt[int64], isZero[int64]
+	case reflect.Uint:
+		enc.fn, enc.empty = encodeUint[uint], isZero[uint]
+	case reflect.Uint8:
+		enc.fn, enc.empty = encodeUint[uint8], isZero[uint8]
+	case reflect.Uint16:
+		enc.fn, enc.empty = encodeUint[uint16], isZero[uint16]
+	case reflect.Uint32:
+		enc.fn, enc.empty = encodeUint[uint32], isZero[uint32]
+	case reflect.Uint64:
+		enc.fn, enc.empty = encodeUint[uint64], isZero[uint64]
+	case reflect.Uintptr:
+		enc.fn, enc.empty = encodeUint[uintptr], isZero[uintptr]
+	case reflect.Float32:
+		enc.fn, enc.empty = encodeFloat[float32], isZero[float32]
+	case reflect.Float64:
+		enc.fn, enc.empty = encodeFloat[float64], isZero[float64]
+	case reflect.String:
+		enc.fn, enc.empty = encodeString, isZero[string]
+	case reflect.Pointer:
+		return c.compilePointer(t, enc)
+	case reflect.Slice:
+		return c.compileSlice(t, enc)
+	case reflect.Array:
+		return c.compileArray(t, enc)
+	case reflect.Map:
+		return c.compileMap(t, enc)
+	case reflect.Struct:
+		return c.compileStruct(t, enc)
+	default:
+		return nil, &UnsupportedTypeError{Type: t, Reason: t.Kind().String() + " kind"}
+	}
+	return enc, nil
+}
+
+func (c encoderCompiler) compilePointer(t reflect.Type, enc *encoder) (*encoder, error) {
+	elem, err := c.compile(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	enc.fn = func(dst []byte, p unsafe.Pointer) ([]byte, error) {
+		ptr := *(*unsafe.Pointer)(p)
+		if ptr == nil {
+			return append(dst, "null"...), nil
+		}
+		return elem.fn(dst, ptr)
+	}
+	enc.empty = isNil
+	return enc, nil
+}
+
+// sliceHeader is the runtime representation of a slice.
+type sliceHeader struct {
+	data unsafe.Pointer
+	len  int
+	cap  int
+}
+
+func (c encoderCompiler) compileSlice(t reflect.Type, enc *encoder) (*encoder, error) {
+	enc.empty = func(p unsafe.Pointer) bool {
+		return (*sliceHeader)(p).len == 0
+	}
+
+	// As with encoding/json, a []byte is encoded as a base64 string.
+	if t.Elem().Kind() == reflect.Uint8 {
+		pt := reflect.PointerTo(t.Elem())
+		if !pt.Implements(marshalerType) && !pt.Implements(textMarshalerType) {
+			enc.fn = encodeBytes
+			return enc, nil
+		}
+	}
+
+	elem, err := c.compile(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	size := t.Elem().Size()
+	enc.fn = func(dst []byte, p unsafe.Pointer) ([]byte, error) {
+		s := (*sliceHeader)(p)
+		if s.data == nil {
+			return append(dst, "null"...), nil
+		}
+		return encodeElems(dst, elem, s.data, s.len, size)
+	}
+	return enc, nil
+}
+
+func (c encoderCompiler) compileArray(t reflect.Type, enc *encoder) (*encoder, error) {
+	elem, err := c.compile(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	n, size := t.Len(), t.Elem().Size()
+	enc.fn = func(dst []byte, p unsafe.Pointer) ([]byte, error) {
+		return encodeElems(dst, elem, p, n, size)
+	}
+	enc.empty = func(unsafe.Pointer) bool {
+		return n == 0
+	}
+	return enc, nil
+}
+
+// encodeElems encodes the n elements of the given size at p as a JSON array.
+func encodeElems(dst []byte, elem *encoder, p unsafe.Pointer, n int, size uintptr) ([]byte, error) {
+	var err error
+	dst = append(dst, '[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		if dst, err = elem.fn(dst, unsafe.Add(p, uintptr(i)*size)); err != nil {
+			return nil, err
+		}
+	}
+	return append(dst, ']'), nil
+}
+
+// mapEntry is an encoded map entry. The value is a range of a scratch buffer.
+type mapEntry struct {
+	key        string
+	start, end int
+}
+
+func (c encoderCompiler) compileMap(t reflect.Type, enc *encoder) (*encoder, error) {
+	var key func(k reflect.Value) string
+	switch t.Key().Kind() {
+	case reflect.String:
+		key = reflect.Value.String
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		key = func(k reflect.Value) string {
+			return strconv.FormatInt(k.Int(), 10)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		key = func(k reflect.Value) string {
+			return strconv.FormatUint(k.Uint(), 10)
+		}
+	default:
+		return nil, &UnsupportedTypeError{Type: t, Reason: "map key must be a string or an integer"}
+	}
+	elem, err := c.compile(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	// Go does not expose its map iterator, so maps are iterated with reflect.
+	// The keys and values are copied into reusable variables rather than
+	// being returned as new reflect.Values, which would allocate.
+	enc.fn = func(dst []byte, p unsafe.Pointer) ([]byte, error) {
+		if *(*unsafe.Pointer)(p) == nil {
+			return append(dst, "null"...), nil
+		}
+		m := reflect.NewAt(t, p).Elem()
+		if m.Len() == 0 {
+			return append(dst, "{}"...), nil
+		}
+		k := reflect.New(t.Key()).Elem()
+		v := reflect.New(t.Elem())
+		vp, v := v.UnsafePointer(), v.Elem()
+
+		var (
+			err     error
+			scratch []byte
+			entries = make([]mapEntry, 0, m.Len())
+			iter    = m.MapRange()
+		)
+		for iter.Next() {
+			k.SetIterKey(iter)
+			v.SetIterValue(iter)
+			start := len(scratch)
+			if scratch, err = elem.fn(scratch, vp); err != nil {
+				return nil, err
+			}
+			entries = append(entries, mapEntry{key: key(k), start: start, end: len(scratch)})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].key < entries[j].key
+		})
+
+		dst = append(dst, '{')
+		for i, e := range entries {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendString(dst, e.key)
+			dst = append(dst, ':')
+			dst = append(dst, scratch[e.start:e.end]...)
+		}
+		return append(dst, '}'), nil
+	}
+	enc.empty = func(p unsafe.Pointer) bool {
+		return *(*unsafe.Pointer)(p) == nil || reflect.NewAt(t, p).Elem().Len() == 0
+	}
+	return enc, nil
+}
+
+// fieldEncoder is the compiled plan for a struct field.
+type fieldEncoder struct {
+	field
+	key []byte // the encoded name followed by a colon
+	enc *encoder
+}
+
+func (c encoderCompiler) compileStruct(t reflect.Type, enc *encoder) (*encoder, error) {
+	fields, err := structFields(t)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]fieldEncoder, len(fields))
+	for i, f := range fields {
+		fenc, err := c.compile(f.typ)
+		if err != nil {
+			return nil, err
+		}
+		plan[i] = fieldEncoder{
+			field: f,
+			key:   append(appendString(nil, f.name), ':'),
+			enc:   fenc,
+		}
+	}
+
+	enc.fn = func(dst []byte, p unsafe.Pointer) ([]byte, error) {
+		var err error
+		dst = append(dst, '{')
+		first := true
+		for i := range plan {
+			f := &plan[i]
+			fp := unsafe.Add(p, f.offset)
+			if f.omitEmpty && f.enc.empty(fp) {
+				continue
+			}
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			dst = append(dst, f.key...)
+			if f.quoted {
+				dst, err = encodeQuoted(dst, f.enc, fp, f.typ.Kind())
+			} else {
+				dst, err = f.enc.fn(dst, fp)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return append(dst, '}'), nil
+	}
+	enc.empty = func(unsafe.Pointer) bool {
+		return false
+	}
+	return enc, nil
+}
+
+// encodeQuoted encodes a field with the string tag option, which wraps the
+// encoded value in a JSON string.
+func encodeQuoted(dst []byte, enc *encoder, p unsafe.Pointer, kind reflect.Kind) ([]byte, error) {
+	if kind == reflect.String {
+		b, err := enc.fn(nil, p)
+		if err != nil {
+			return nil, err
+		}
+		return appendString(dst, string(b)), nil
+	}
+	dst = append(dst, '"')
+	dst, err := enc.fn(dst, p)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, '"'), nil
+}
+
+func isZero[T comparable](p unsafe.Pointer) bool {
+	var zero T
+	return *(*T)(p) == zero
+}
+
+func isNil(p unsafe.Pointer) bool {
+	return *(*unsafe.Pointer)(p) == nil
+}
+
+func encodeBool(dst []byte, p unsafe.Pointer) ([]byte, error) {
+	return strconv.AppendBool(dst, *(*bool)(p)), nil
+}
+
+func encodeInt[T constraints.Signed](dst []byte, p unsafe.Pointer) ([]byte, error) {
+	return strconv.AppendInt(dst, int64(*(*T)(p)), 10), nil
+}
+
+func encodeUint[T constraints.Unsigned | ~uintptr](dst []byte, p unsafe.Pointer) ([]byte, error) {
+	return strconv.AppendUint(dst, uint64(*(*T)(p)), 10), nil
+}
+
+// encodeFloat encodes a float the way encoding/json does, using the shortest
+// representation that round trips, and exponents only for very small or very
+// large values.
+func encodeFloat[T constraints.Float](dst []byte, p unsafe.Pointer) ([]byte, error) {
+	f := float64(*(*T)(p))
+	bits := int(unsafe.Sizeof(T(0)) * 8)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, &UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, bits)}
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(dst); n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst, nil
+}
+
+func encodeString(dst []byte, p unsafe.Pointer) ([]byte, error) {
+	return appendString(dst, *(*string)(p)), nil
+}
+
+func encodeBytes(dst []byte, p unsafe.Pointer) ([]byte, error) {
+	b := *(*[]byte)(p)
+	if b == nil {
+		return append(dst, "null"...), nil
+	}
+	n := base64.StdEncoding.EncodedLen(len(b))
+	dst = append(dst, '"')
+	if free := cap(dst) - len(dst); free < n {
+		dst = append(dst[:cap(dst)], make([]byte, n-free)...)[:len(dst)]
+	}
+	base64.StdEncoding.Encode(dst[len(dst):len(dst)+n], b)
+	dst = dst[:len(dst)+n]
+	return append(dst, '"'), nil
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"go-generics-the-hard-way/pkg/typedjson"
+)
+
+type ID string
+
+type Item struct {
+	SKU      ID      `json:"sku"`
+	Quantity int     `json:"qty"`
+	Price    float64 `json:"price"`
+}
+
+type Order struct {
+	ID       ID                `json:"id"`
+	Items    []Item            `json:"items"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Discount *float64          `json:"discount,omitempty"`
+	Paid     bool              `json:"paid"`
+	internal int
+}
+
+func ExampleEncoder() {
+	enc, err := typedjson.NewEncoder[Order]()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	o := Order{
+		ID:    "o-1",
+		Items: []Item{{SKU: "apple", Quantity: 3, Price: 0.5}},
+		Paid:  true,
+	}
+	b, err := enc.Marshal(&o)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(b))
+	// Output: {"id":"o-1","items":[{"sku":"apple","qty":3,"price":0.5}],"paid":true}
+}
+
+func ExampleMarshal() {
+	m := map[int][]string{2: {"b"}, 1: {"a", "<a>"}}
+	b, _ := typedjson.Marshal(&m)
+	fmt.Println(string(b))
+	// Output: {"1":["a","\u003ca\u003e"],"2":["b"]}
+}
+
+// same checks that typedjson and encoding/json encode v the same way.
+func same[T any](t *testing.T, v T) {
+	t.Helper()
+	want, err := json.Marshal(&v)
+	if err != nil {
+		t.Fatalf("json.Marshal(%#v) failed: %v", v, err)
+	}
+	got, err := typedjson.Marshal(&v)
+	if err != nil {
+		t.Fatalf("Marshal(%#v) failed: %v", v, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Marshal(%#v)\n got %s\nwant %s", v, got, want)
+	}
+}
+
+type Embedded struct {
+	A int
+	B string `json:"b"`
+}
+
+type Shadow struct {
+	Embedded
+	A      string // shadows Embedded.A
+	Ignore int    `json:"-"`
+	Dash   int    `json:"-,"`
+	Num    int64  `json:",string"`
+	Str    string `json:",string"`
+	Empty  []int  `json:",omitempty"`
+	Arr    [2]uint8
+	Bytes  []byte
+}
+
+type Node struct {
+	Value    int
+	Children []*Node
+}
+
+func TestMarshalMatchesEncodingJSON(t *testing.T) {
+	discount := 0.1
+	same(t, true)
+	same(t, int8(-8))
+	same(t, uint64(math.MaxUint64))
+	same(t, ID("hello"))
+	same(t, "quotes \" backslash \\ html <>& newline \n tab \t nul \x00 bell \a backspace \b feed \f invalid \xff")
+	same(t, "line separator \u2028 paragraph separator \u2029 emoji \U0001F600")
+	same(t, []float64{0, -0.5, 1e-7, 1e21, 123456789, math.MaxFloat64, math.SmallestNonzeroFloat64})
+	same(t, []float32{1e-7, 3.4e38, 0.1})
+	same(t, []int(nil))
+	same(t, []int{})
+	same(t, map[string]int(nil))
+	same(t, map[string]int{})
+	same(t, map[ID]bool{"b": true, "a": false, "<": true})
+	same(t, map[int64]string{-1: "x", 10: "y", 2: "z"})
+	same(t, map[uint8][]int{1: {1}, 0: nil})
+	same(t, []byte("hello, world"))
+	same(t, []byte(nil))
+	same(t, [3]int{1, 2, 3})
+	same(t, [0]int{})
+	same(t, &discount)
+	same(t, (*int)(nil))
+	same(t, Order{})
+	same(t, Order{
+		ID:       "o-2",
+		Items:    []Item{{"a", 1, 1.5}, {"b", 2, 2}},
+		Tags:     map[string]string{"z": "last", "a": "first"},
+		Discount: &discount,
+		internal: 1,
+	})
+	same(t, Shadow{Embedded: Embedded{A: 1, B: "b"}, A: "a", Num: 42, Str: "s", Bytes: []byte{1, 2}})
+	same(t, Node{Value: 1, Children: []*Node{{Value: 2}, nil, {Value: 3, Children: []*Node{}}}})
+}
+
+func TestEncoderAppend(t *testing.T) {
+	enc, err := typedjson.NewEncoder[Item]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte("items: ")
+	buf, err = enc.Append(buf, &Item{SKU: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err = enc.Append(append(buf, ' '), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(buf), `items: {"sku":"a","qty":0,"price":0} null`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+type withChan struct {
+	C chan int
+}
+
+type withMarshaler struct {
+	T time.Time
+}
+
+type withEmbeddedPointer struct {
+	*Embedded
+}
+
+func TestUnsupportedTypes(t *testing.T) {
+	for name, f := range map[string]func() error{
+		"interface":        func() error { _, err := typedjson.NewEncoder[any](); return err },
+		"chan field":       func() error { _, err := typedjson.NewEncoder[withChan](); return err },
+		"complex":          func() error { _, err := typedjson.NewEncoder[[]complex64](); return err },
+		"map key":          func() error { _, err := typedjson.NewEncoder[map[float64]int](); return err },
+		"marshaler":        func() error { _, err := typedjson.NewEncoder[withMarshaler](); return err },
+		"embedded pointer": func() error { _, err := typedjson.NewEncoder[withEmbeddedPointer](); return err },
+	} {
+		var uerr *typedjson.UnsupportedTypeError
+		if err := f(); !errors.As(err, &uerr) {
+			t.Errorf("%s: got %v, want an *UnsupportedTypeError", name, err)
+		}
+	}
+}
+
+func TestUnsupportedValues(t *testing.T) {
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		var uerr *typedjson.UnsupportedValueError
+		if _, err := typedjson.Marshal(&map[string]float64{"f": f}); !errors.As(err, &uerr) {
+			t.Errorf("%v: got %v, want an *UnsupportedValueError", f, err)
+		}
+	}
+}
+
+func TestEncoderAllocs(t *testing.T) {
+	enc, err := typedjson.NewEncoder[Order]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := Order{ID: "o-1", Items: []Item{{"a", 1, 1.5}, {"b", 2, 2}}}
+	buf := make([]byte, 0, 1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = enc.Append(buf[:0], &o)
+	})
+	if allocs != 0 {
+		t.Fatalf("Append allocated %v times, want 0", allocs)
+	}
+}
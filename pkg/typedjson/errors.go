@@ -0,0 +1,95 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedjson
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// UnsupportedTypeError is returned when a plan cannot be compiled for a type
+// because the type, or a type reachable from it, is outside the closed set of
+// types this package supports.
+type UnsupportedTypeError struct {
+	Type   reflect.Type
+	Reason string
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return "typedjson: unsupported type " + e.Type.String() + ": " + e.Reason
+}
+
+// UnsupportedValueError is returned when a value of a supported type cannot
+// be represented in JSON, such as a NaN or infinite float.
+type UnsupportedValueError struct {
+	Str string
+}
+
+func (e *UnsupportedValueError) Error() string {
+	return "typedjson: unsupported value: " + e.Str
+}
+
+// ErrSyntax is wrapped by the errors for malformed JSON.
+var ErrSyntax = errors.New("syntax error")
+
+// DecodeError is returned when JSON cannot be decoded.
+type DecodeError struct {
+
+	// Path locates the value that could not be decoded, for example
+	// $.Amounts[3], where $ is the value being decoded.
+	Path string
+
+	// Offset is the offset in the input at which the error occurred.
+	Offset int64
+
+	// Err is the underlying error, which is a *TypeError or wraps ErrSyntax.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return "typedjson: " + e.Path + ": " + e.Err.Error() +
+		" (offset " + strconv.FormatInt(e.Offset, 10) + ")"
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// prependPath prepends elem to the path of err if it is a *DecodeError.
+// Paths are built from the innermost value outwards as the error is
+// returned, so decoding does not pay for tracking the path when it succeeds.
+func prependPath(err error, elem string) error {
+	if de, ok := err.(*DecodeError); ok {
+		de.Path = elem + de.Path
+	}
+	return err
+}
+
+// TypeError describes a JSON value that cannot be decoded into a Go type.
+type TypeError struct {
+
+	// Value describes the JSON value, such as "string" or "number 300".
+	Value string
+
+	// Type is the type of the Go value being decoded into.
+	Type reflect.Type
+}
+
+func (e *TypeError) Error() string {
+	return "cannot decode " + e.Value + " into Go value of type " + e.Type.String()
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedjson
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// field is a struct field that is encoded as a JSON object member.
+type field struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	offset    uintptr
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+// structFields returns the fields of t that are encoded, in the order they
+// are encoded. Fields promoted from embedded structs are resolved with the
+// same rules as encoding/json: the shallowest field with a given name wins,
+// and among fields at the same depth a single tagged field wins. Otherwise
+// all the fields with that name are dropped.
+//
+// Unlike encoding/json, embedded pointers to structs are not supported, as
+// promoting their fields would require allocating the embedded struct when
+// decoding.
+func structFields(t reflect.Type) ([]field, error) {
+	type level struct {
+		typ    reflect.Type
+		index  []int
+		offset uintptr
+	}
+
+	var fields []field
+	next := []level{{typ: t}}
+	for depth := 0; len(next) > 0; depth++ {
+		current := next
+		next = nil
+		for _, l := range current {
+			for i := 0; i < l.typ.NumField(); i++ {
+				sf := l.typ.Field(i)
+				ft := sf.Type
+				if sf.Anonymous {
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+
+				index := make([]int, len(l.index)+1)
+				copy(index, l.index)
+				index[len(l.index)] = i
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					if sf.Type.Kind() == reflect.Pointer {
+						return nil, &UnsupportedTypeError{
+							Type:   t,
+							Reason: "embedded pointer field " + sf.Name,
+						}
+					}
+					next = append(next, level{
+						typ:    ft,
+						index:  index,
+						offset: l.offset + sf.Offset,
+					})
+					continue
+				}
+
+				f := field{
+					name:   name,
+					index:  index,
+					typ:    sf.Type,
+					offset: l.offset + sf.Offset,
+					tagged: name != "",
+				}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				for opts != "" {
+					var opt string
+					opt, opts, _ = strings.Cut(opts, ",")
+					switch opt {
+					case "omitempty":
+						f.omitEmpty = true
+					case "string":
+						switch sf.Type.Kind() {
+						case reflect.Bool,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64,
+							reflect.String:
+							f.quoted = true
+						}
+					}
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	// Find the dominant field for each name.
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		return fields[i].tagged && !fields[j].tagged
+	})
+	out := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if f, ok := dominantField(fields[i:j]); ok {
+			out = append(out, f)
+		}
+		i = j
+	}
+	fields = out
+
+	// Restore the declaration order.
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields, nil
+}
+
+// dominantField returns the field that wins among fields with the same name,
+// which are sorted by depth and then by whether they are tagged.
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 &&
+		len(fields[0].index) == len(fields[1].index) &&
+		fields[0].tagged == fields[1].tagged {
+		return field{}, false
+	}
+	return fields[0], true
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedjson
+
+import (
+	"reflect"
+	"sync"
+)
+
+// planCache caches the compiled plans of type P, such as encoders or
+// decoders, by the type they were compiled for.
+type planCache[P any] struct {
+	mu    sync.Mutex
+	plans sync.Map // map[reflect.Type]*P
+}
+
+func (c *planCache[P]) load(t reflect.Type) (*P, bool) {
+	p, ok := c.plans.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return p.(*P), true
+}
+
+// get returns the plan for t, calling compile to compile it if necessary.
+func (c *planCache[P]) get(t reflect.Type, compile func(*compiler[P], reflect.Type) (*P, error)) (*P, error) {
+	if p, ok := c.load(t); ok {
+		return p, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p, ok := c.load(t); ok {
+		return p, nil
+	}
+
+	// Plans are only published once they are complete, so a plan that fails
+	// to compile does not leave behind plans that refer to it.
+	comp := &compiler[P]{cache: c, seen: map[reflect.Type]*P{}}
+	p, err := compile(comp, t)
+	if err != nil {
+		return nil, err
+	}
+	for t, p := range comp.seen {
+		c.plans.Store(t, p)
+	}
+	return p, nil
+}
+
+// compiler tracks the plans compiled for a type and the types reachable
+// from it.
+type compiler[P any] struct {
+	cache *planCache[P]
+	seen  map[reflect.Type]*P
+}
+
+// plan returns the plan for t and whether it is new and must be built.
+//
+// A recursive type refers to its own plan before the plan is complete, so a
+// new plan is recorded before the plans of its elements are compiled. This
+// works because plans call the functions of other plans through a pointer to
+// the plan rather than capturing the functions themselves.
+func (c *compiler[P]) plan(t reflect.Type) (*P, bool) {
+	if p, ok := c.cache.load(t); ok {
+		return p, false
+	}
+	if p, ok := c.seen[t]; ok {
+		return p, false
+	}
+	p := new(P)
+	c.seen[t] = p
+	return p, true
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedjson
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// errUnexpectedEnd is the error of a *DecodeError returned when the input
+// ends in the middle of a value.
+var errUnexpectedEnd = fmt.Errorf("%w: unexpected end of JSON input", ErrSyntax)
+
+// maxDepth is the maximum nesting depth of arrays, objects, and pointers, the
+// same limit as encoding/json. Without a limit, deeply nested input or a
+// recursive pointer type would overflow the stack.
+const maxDepth = 10000
+
+// errMaxDepth is the error of a *DecodeError returned when the input is
+// nested more deeply than maxDepth.
+var errMaxDepth = fmt.Errorf("%w: exceeded max depth", ErrSyntax)
+
+// decodeState is a cursor over the JSON being decoded.
+type decodeState struct {
+	data []byte
+	off  int
+
+	// depth is the number of arrays, objects, and pointers being decoded.
+	depth int
+
+	// scratch is reused to unescape strings.
+	scratch []byte
+}
+
+// error returns a *DecodeError for the current offset.
+func (d *decodeState) error(err error) error {
+	return &DecodeError{Offset: int64(d.off), Err: err}
+}
+
+// enter increments the nesting depth and returns an error if it exceeds
+// maxDepth. Every successful call must be paired with a call to leave.
+func (d *decodeState) enter() error {
+	d.depth++
+	if d.depth > maxDepth {
+		return d.error(errMaxDepth)
+	}
+	return nil
+}
+
+// leave decrements the nesting depth.
+func (d *decodeState) leave() {
+	d.depth--
+}
+
+// invalid returns a syntax error for the character at the current offset,
+// which is not valid in the given context.
+func (d *decodeState) invalid(context string) error {
+	if d.off >= len(d.data) {
+		return d.error(errUnexpectedEnd)
+	}
+	return d.error(fmt.Errorf("%w: invalid character %s %s", ErrSyntax, quoteChar(d.data[d.off]), context))
+}
+
+func (d *decodeState) skipSpace() {
+	for d.off < len(d.data) {
+		switch d.data[d.off] {
+		case ' ', '\t', '\n', '\r':
+			d.off++
+		default:
+			return
+		}
+	}
+}
+
+// peek skips whitespace and returns the next byte without consuming it, or
+// zero at the end of the input.
+func (d *decodeState) peek() byte {
+	d.skipSpace()
+	if d.off < len(d.data) {
+		return d.data[d.off]
+	}
+	return 0
+}
+
+// consume skips whitespace and consumes the byte c, which is expected in the
+// given context.
+func (d *decodeState) consume(c byte, context string) error {
+	if d.peek() != c {
+		return d.invalid(context)
+	}
+	d.off++
+	return nil
+}
+
+// null consumes a null literal if it is next and reports whether it did.
+func (d *decodeState) null() (bool, error) {
+	if d.peek() != 'n' {
+		return false, nil
+	}
+	return true, d.literal("null")
+}
+
+func (d *decodeState) literal(lit string) error {
+	for i := 0; i < len(lit); i++ {
+		if d.off >= len(d.data) || d.data[d.off] != lit[i] {
+			return d.invalid("in literal " + lit)
+		}
+		d.off++
+	}
+	return nil
+}
+
+// typeError returns an error for a JSON value, starting at the current
+// offset, that cannot be decoded into a value of type t.
+func (d *decodeState) typeError(value string, t reflect.Type) error {
+	if value == "" {
+		switch c := d.peek(); {
+		case c == '"':
+			value = "string"
+		case c == '{':
+			value = "object"
+		case c == '[':
+			value = "array"
+		case c == 't' || c == 'f':
+			value = "bool"
+		case c == '-' || c >= '0' && c <= '9':
+			value = "number"
+		default:
+			return d.invalid("looking for beginning of value")
+		}
+	}
+	return d.error(&TypeError{Value: value, Type: t})
+}
+
+// number consumes a number and returns its text.
+func (d *decodeState) number() ([]byte, error) {
+	d.skipSpace()
+	start := d.off
+	if d.off < len(d.data) && d.data[d.off] == '-' {
+		d.off++
+	}
+	switch {
+	case d.off < len(d.data) && d.data[d.off] == '0':
+		d.off++
+	case d.off < len(d.data) && d.data[d.off] >= '1' && d.data[d.off] <= '9':
+		d.digits()
+	default:
+		return nil, d.invalid("in numeric literal")
+	}
+	if d.off < len(d.data) && d.data[d.off] == '.' {
+		d.off++
+		if d.digits() == 0 {
+			return nil, d.invalid("after decimal point in numeric literal")
+		}
+	}
+	if d.off < len(d.data) && (d.data[d.off] == 'e' || d.data[d.off] == 'E') {
+		d.off++
+		if d.off < len(d.data) && (d.data[d.off] == '+' || d.data[d.off] == '-') {
+			d.off++
+		}
+		if d.digits() == 0 {
+			return nil, d.invalid("in exponent of numeric literal")
+		}
+	}
+	return d.data[start:d.off], nil
+}
+
+func (d *decodeState) digits() int {
+	start := d.off
+	for d.off < len(d.data) && d.data[d.off] >= '0' && d.data[d.off] <= '9' {
+		d.off++
+	}
+	return d.off - start
+}
+
+// str consumes a string and returns its unescaped contents. The result may
+// refer to the input or to the scratch buffer, so it is only valid until the
+// next call to str.
+func (d *decodeState) str() ([]byte, error) {
+	if err := d.consume('"', "looking for beginning of string"); err != nil {
+		return nil, err
+	}
+	start := d.off
+	for d.off < len(d.data) {
+		c := d.data[d.off]
+		switch {
+		case c == '"':
+			d.off++
+			return d.data[start : d.off-1], nil
+		case c == '\\' || c < 0x20:
+			return d.unescape(start)
+		case c < utf8.RuneSelf:
+			d.off++
+		default:
+			r, size := utf8.DecodeRune(d.data[d.off:])
+			if r == utf8.RuneError && size == 1 {
+				return d.unescape(start)
+			}
+			d.off += size
+		}
+	}
+	return nil, d.error(errUnexpectedEnd)
+}
+
+// unescape is the slow path of str for strings with escape sequences or
+// invalid UTF-8. The string starts at start and no bytes before d.off need
+// unescaping.
+func (d *decodeState) unescape(start int) ([]byte, error) {
+	b := append(d.scratch[:0], d.data[start:d.off]...)
+	for d.off < len(d.data) {
+		c := d.data[d.off]
+		switch {
+		case c == '"':
+			d.off++
+			d.scratch = b
+			return b, nil
+		case c < 0x20:
+			return nil, d.invalid("in string literal")
+		case c == '\\':
+			d.off++
+			if d.off >= len(d.data) {
+				return nil, d.error(errUnexpectedEnd)
+			}
+			switch e := d.data[d.off]; e {
+			case '"', '\\', '/':
+				b = append(b, e)
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'u':
+				if d.off+5 > len(d.data) {
+					return nil, d.error(errUnexpectedEnd)
+				}
+				r, ok := d.hex4(d.off + 1)
+				if !ok {
+					return nil, d.invalid("in \\u hexadecimal character escape")
+				}
+				d.off += 4
+				if utf16.IsSurrogate(r) {
+					// A surrogate must be followed by a second escape that
+					// completes the pair, otherwise it is invalid.
+					r1 := r
+					r = utf8.RuneError
+					if d.off+6 < len(d.data) && d.data[d.off+1] == '\\' && d.data[d.off+2] == 'u' {
+						if r2, ok := d.hex4(d.off + 3); ok {
+							if dec := utf16.DecodeRune(r1, r2); dec != utf8.RuneError {
+								r = dec
+								d.off += 6
+							}
+						}
+					}
+				}
+				b = utf8.AppendRune(b, r)
+			default:
+				return nil, d.invalid("in string escape code")
+			}
+			d.off++
+		case c < utf8.RuneSelf:
+			b = append(b, c)
+			d.off++
+		default:
+			r, size := utf8.DecodeRune(d.data[d.off:])
+			d.off += size
+			b = utf8.AppendRune(b, r)
+		}
+	}
+	return nil, d.error(errUnexpectedEnd)
+}
+
+// hex4 decodes the four hexadecimal digits at i.
+func (d *decodeState) hex4(i int) (rune, bool) {
+	if i+4 > len(d.data) {
+		return 0, false
+	}
+	var r rune
+	for _, c := range d.data[i : i+4] {
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, true
+}
+
+// skip consumes a value of any type.
+func (d *decodeState) skip() error {
+	switch c := d.peek(); {
+	case c == '"':
+		_, err := d.str()
+		return err
+	case c == '{':
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		d.off++
+		if d.peek() == '}' {
+			d.off++
+			return nil
+		}
+		for {
+			if _, err := d.str(); err != nil {
+				return err
+			}
+			if err := d.consume(':', "after object key"); err != nil {
+				return err
+			}
+			if err := d.skip(); err != nil {
+				return err
+			}
+			if d.peek() == '}' {
+				d.off++
+				return nil
+			}
+			if err := d.consume(',', "after object key:value pair"); err != nil {
+				return err
+			}
+		}
+	case c == '[':
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer d.leave()
+		d.off++
+		if d.peek() == ']' {
+			d.off++
+			return nil
+		}
+		for {
+			if err := d.skip(); err != nil {
+				return err
+			}
+			if d.peek() == ']' {
+				d.off++
+				return nil
+			}
+			if err := d.consume(',', "after array element"); err != nil {
+				return err
+			}
+		}
+	case c == 't':
+		return d.literal("true")
+	case c == 'f':
+		return d.literal("false")
+	case c == 'n':
+		return d.literal("null")
+	case c == '-' || c >= '0' && c <= '9':
+		_, err := d.number()
+		return err
+	default:
+		return d.invalid("looking for beginning of value")
+	}
+}
+
+func quoteChar(c byte) string {
+	if c == '\'' {
+		return `'\''`
+	}
+	if c == '"' {
+		return `'"'`
+	}
+	s := strconv.Quote(string(rune(c)))
+	return "'" + s[1:len(s)-1] + "'"
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typedjson
+
+import (
+	"io"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// Format is the framing of a stream of JSON values.
+type Format int
+
+const (
+	// Array is a stream of values that are the elements of a single JSON
+	// array.
+	Array Format = iota
+
+	// NDJSON is a stream of newline-delimited JSON values. As with
+	// json.Decoder, any whitespace is accepted between values.
+	NDJSON
+)
+
+// minRead is the minimum number of bytes a Decoder reads at a time.
+const minRead = 4096
+
+type arrayState int
+
+const (
+	arrayStart arrayState = iota
+	arrayFirst
+	arrayNext
+	arrayDone
+)
+
+// Decoder decodes a stream of values of type T, one value at a time, without
+// reading the entire stream into memory.
+type Decoder[T any] struct {
+	r      io.Reader
+	format Format
+	dec    *decoder
+
+	buf     []byte
+	off     int   // the offset of the next unread byte in buf
+	base    int64 // the offset of buf[0] in the stream
+	eof     bool
+	scratch []byte
+
+	state arrayState
+	index int
+	err   error
+}
+
+// NewDecoder returns a decoder that reads values of type T from r in the
+// given format. An *UnsupportedTypeError is returned if T is not supported.
+func NewDecoder[T any](r io.Reader, format Format) (*Decoder[T], error) {
+	dec, err := decoderFor(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return &Decoder[T]{r: r, format: format, dec: dec}, nil
+}
+
+// Decode decodes the next value in the stream into the value v points to.
+// It returns io.EOF when there are no more values.
+//
+// If the value cannot be decoded into a T, a *DecodeError is returned and
+// the stream is positioned at the next value. Other errors, such as syntax
+// errors and errors reading from the stream, are returned by every
+// subsequent call to Decode.
+func (d *Decoder[T]) Decode(v *T) error {
+	if d.err != nil {
+		return d.err
+	}
+	if err := d.next(); err != nil {
+		d.err = err
+		return err
+	}
+	end, err := d.frame()
+	if err != nil {
+		d.err = err
+		return err
+	}
+
+	s := decodeState{data: d.buf[:end], off: d.off, scratch: d.scratch}
+	err = d.dec.fn(&s, unsafe.Pointer(v))
+	d.off, d.scratch = end, s.scratch
+
+	index := d.index
+	d.index++
+	if d.format == Array {
+		d.state = arrayNext
+		err = prependPath(err, "["+strconv.Itoa(index)+"]")
+	}
+	return d.streamError(err)
+}
+
+// next skips the framing before the next value and returns io.EOF if there
+// are no more values.
+func (d *Decoder[T]) next() error {
+	if d.format == NDJSON {
+		_, err := d.peek()
+		return err
+	}
+
+	switch d.state {
+	case arrayStart:
+		c, err := d.peek()
+		if err == io.EOF {
+			return d.streamError(&DecodeError{Offset: int64(d.off), Err: errUnexpectedEnd})
+		} else if err != nil {
+			return err
+		}
+		if c != '[' {
+			s := decodeState{data: d.buf, off: d.off}
+			return d.streamError(s.invalid("looking for beginning of array"))
+		}
+		d.off++
+		d.state = arrayFirst
+		if c, err = d.peek(); err != nil {
+			return d.unexpectedEnd(err)
+		}
+		if c == ']' {
+			d.off++
+			d.state = arrayDone
+			return io.EOF
+		}
+	case arrayNext:
+		c, err := d.peek()
+		if err != nil {
+			return d.unexpectedEnd(err)
+		}
+		switch c {
+		case ']':
+			d.off++
+			d.state = arrayDone
+			return io.EOF
+		case ',':
+			d.off++
+		default:
+			s := decodeState{data: d.buf, off: d.off}
+			return d.streamError(s.invalid("after array element"))
+		}
+	case arrayDone:
+		return io.EOF
+	}
+	return nil
+}
+
+// frame reads until the value at the current offset is complete and returns
+// its end.
+func (d *Decoder[T]) frame() (int, error) {
+	for {
+		s := decodeState{data: d.buf, off: d.off}
+		err := s.skip()
+
+		// A number at the end of the buffer may continue in the next read.
+		if err == nil && (s.off < len(d.buf) || d.eof) {
+			return s.off, nil
+		}
+		if de, ok := err.(*DecodeError); ok && (de.Err != errUnexpectedEnd || d.eof) {
+			return 0, d.streamError(err)
+		}
+		if err := d.fill(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// peek skips whitespace and returns the next byte without consuming it.
+func (d *Decoder[T]) peek() (byte, error) {
+	for {
+		for ; d.off < len(d.buf); d.off++ {
+			switch c := d.buf[d.off]; c {
+			case ' ', '\t', '\n', '\r':
+			default:
+				return c, nil
+			}
+		}
+		if d.eof {
+			return 0, io.EOF
+		}
+		if err := d.fill(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// fill reads more of the stream into the buffer, discarding the bytes that
+// have been consumed.
+func (d *Decoder[T]) fill() error {
+	if d.off > 0 {
+		n := copy(d.buf, d.buf[d.off:])
+		d.buf = d.buf[:n]
+		d.base += int64(d.off)
+		d.off = 0
+	}
+	if cap(d.buf)-len(d.buf) < minRead {
+		buf := make([]byte, len(d.buf), 2*cap(d.buf)+minRead)
+		copy(buf, d.buf)
+		d.buf = buf
+	}
+	n, err := d.r.Read(d.buf[len(d.buf):cap(d.buf)])
+	d.buf = d.buf[:len(d.buf)+n]
+	if err == io.EOF {
+		d.eof = true
+		return nil
+	}
+	return err
+}
+
+// unexpectedEnd converts io.EOF in the middle of an array to a syntax error.
+func (d *Decoder[T]) unexpectedEnd(err error) error {
+	if err == io.EOF {
+		return d.streamError(&DecodeError{Offset: int64(d.off), Err: errUnexpectedEnd})
+	}
+	return err
+}
+
+// streamError completes the path of err, if it is a *DecodeError, and makes
+// its offset relative to the start of the stream.
+func (d *Decoder[T]) streamError(err error) error {
+	if de, ok := err.(*DecodeError); ok {
+		de.Path = "$" + de.Path
+		de.Offset += d.base
+	}
+	return err
+}
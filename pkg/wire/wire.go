@@ -0,0 +1,437 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wire implements the compact binary format used by the
+// MarshalBinary and UnmarshalBinary methods of the generic containers.
+//
+// Every encoding starts with a header:
+//
+//	version   byte  the version of the format, currently 1
+//	container byte  the kind of container, such as List or Ledger
+//	elem      byte  the type of the elements
+//
+// A List or Set is followed by the number of elements as a uvarint and then
+// the elements. A Ledger is followed by its ID, encoded as a string, and then
+// its amounts, encoded the same way as the elements of a List.
+//
+// Elements are encoded by the kind of their type. Signed integers are
+// zig-zag varints, unsigned integers are uvarints, floats are their IEEE 754
+// bits in little-endian order, complex numbers are their real and imaginary
+// parts, and strings are their length as a uvarint followed by their bytes.
+// Types defined in terms of these, such as type ID string, are encoded the
+// same way as their underlying types.
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// Version is the version of the format written by this package.
+const Version = 1
+
+// Container identifies the kind of container that was encoded.
+type Container byte
+
+const (
+	List Container = iota + 1
+	Set
+	Ledger
+)
+
+func (c Container) String() string {
+	switch c {
+	case List:
+		return "List"
+	case Set:
+		return "Set"
+	case Ledger:
+		return "Ledger"
+	}
+	return fmt.Sprintf("Container(%d)", byte(c))
+}
+
+// Type identifies the type of the encoded elements.
+type Type byte
+
+const (
+	Int Type = iota + 1
+	Int8
+	Int16
+	Int32
+	Int64
+	Uint
+	Uint8
+	Uint16
+	Uint32
+	Uint64
+	Float32
+	Float64
+	Complex64
+	Complex128
+	String
+)
+
+var typeNames = [...]string{
+	Int:        "int",
+	Int8:       "int8",
+	Int16:      "int16",
+	Int32:      "int32",
+	Int64:      "int64",
+	Uint:       "uint",
+	Uint8:      "uint8",
+	Uint16:     "uint16",
+	Uint32:     "uint32",
+	Uint64:     "uint64",
+	Float32:    "float32",
+	Float64:    "float64",
+	Complex64:  "complex64",
+	Complex128: "complex128",
+	String:     "string",
+}
+
+func (t Type) String() string {
+	if int(t) < len(typeNames) && typeNames[t] != "" {
+		return typeNames[t]
+	}
+	return fmt.Sprintf("Type(%d)", byte(t))
+}
+
+var (
+	// ErrVersion is returned when data was encoded with an unsupported
+	// version of the format.
+	ErrVersion = errors.New("wire: unsupported version")
+
+	// ErrMismatch is returned when data encodes a different container or
+	// element type than the one it is being decoded into.
+	ErrMismatch = errors.New("wire: container or type mismatch")
+
+	// ErrCorrupt is returned when data is truncated or malformed.
+	ErrCorrupt = errors.New("wire: corrupt data")
+)
+
+// UnsupportedTypeError is returned when a container's element type is not a
+// number or a string.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return "wire: unsupported type " + e.Type.String()
+}
+
+// Codec encodes and decodes values of type T.
+type Codec[T any] struct {
+	typ    Type
+	append func(b []byte, p unsafe.Pointer) []byte
+	read   func(r *Reader, p unsafe.Pointer) error
+}
+
+// CodecFor returns a codec for T, or an *UnsupportedTypeError if the
+// underlying type of T is not a number or a string.
+func CodecFor[T any]() (*Codec[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	c := &Codec[T]{}
+	switch t.Kind() {
+	case reflect.Int:
+		c.typ, c.append, c.read = Int, appendInt[int], readInt[int]
+	case reflect.Int8:
+		c.typ, c.append, c.read = Int8, appendInt[int8], readInt[int8]
+	case reflect.Int16:
+		c.typ, c.append, c.read = Int16, appendInt[int16], readInt[int16]
+	case reflect.Int32:
+		c.typ, c.append, c.read = Int32, appendInt[int32], readInt[int32]
+	case reflect.Int64:
+		c.typ, c.append, c.read = Int64, appendInt[int64], readInt[int64]
+	case reflect.Uint:
+		c.typ, c.append, c.read = Uint, appendUint[uint], readUint[uint]
+	case reflect.Uint8:
+		c.typ, c.append, c.read = Uint8, appendUint[uint8], readUint[uint8]
+	case reflect.Uint16:
+		c.typ, c.append, c.read = Uint16, appendUint[uint16], readUint[uint16]
+	case reflect.Uint32:
+		c.typ, c.append, c.read = Uint32, appendUint[uint32], readUint[uint32]
+	case reflect.Uint64:
+		c.typ, c.append, c.read = Uint64, appendUint[uint64], readUint[uint64]
+	case reflect.Float32:
+		c.typ, c.append, c.read = Float32, appendFloat32, readFloat32
+	case reflect.Float64:
+		c.typ, c.append, c.read = Float64, appendFloat64, readFloat64
+	case reflect.Complex64:
+		c.typ, c.append, c.read = Complex64, appendComplex64, readComplex64
+	case reflect.Complex128:
+		c.typ, c.append, c.read = Complex128, appendComplex128, readComplex128
+	case reflect.String:
+		c.typ, c.append, c.read = String, appendString, readString
+	default:
+		return nil, &UnsupportedTypeError{Type: t}
+	}
+	return c, nil
+}
+
+// Type returns the type of the values encoded by c.
+func (c *Codec[T]) Type() Type {
+	return c.typ
+}
+
+// Append appends the encoding of v to b and returns the extended buffer.
+func (c *Codec[T]) Append(b []byte, v T) []byte {
+	return c.append(b, unsafe.Pointer(&v))
+}
+
+// Read decodes a value from r.
+func (c *Codec[T]) Read(r *Reader) (T, error) {
+	var v T
+	err := c.read(r, unsafe.Pointer(&v))
+	return v, err
+}
+
+// AppendAll appends the number of values as a uvarint followed by the
+// values.
+func (c *Codec[T]) AppendAll(b []byte, vals []T) []byte {
+	b = AppendUvarint(b, uint64(len(vals)))
+	for i := range vals {
+		b = c.append(b, unsafe.Pointer(&vals[i]))
+	}
+	return b
+}
+
+// ReadAll reads the number of values followed by the values.
+func (c *Codec[T]) ReadAll(r *Reader) ([]T, error) {
+	n, err := r.Count()
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]T, n)
+	for i := range vals {
+		if err := c.read(r, unsafe.Pointer(&vals[i])); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// AppendHeader appends a header for the given container and element type.
+func AppendHeader(b []byte, c Container, elem Type) []byte {
+	return append(b, Version, byte(c), byte(elem))
+}
+
+// AppendUvarint appends n as a uvarint.
+func AppendUvarint(b []byte, n uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], n)]...)
+}
+
+// Reader decodes the encoded form of a container.
+type Reader struct {
+	data []byte
+	off  int
+}
+
+// NewReader returns a reader for data.
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+// Header reads a header and checks it is for the given container and
+// element type.
+func (r *Reader) Header(c Container, elem Type) error {
+	if len(r.data)-r.off < 3 {
+		return fmt.Errorf("%w: truncated header", ErrCorrupt)
+	}
+	h := r.data[r.off : r.off+3]
+	r.off += 3
+	if h[0] != Version {
+		return fmt.Errorf("%w %d", ErrVersion, h[0])
+	}
+	if Container(h[1]) != c || Type(h[2]) != elem {
+		return fmt.Errorf("%w: got %s of %s, want %s of %s",
+			ErrMismatch, Container(h[1]), Type(h[2]), c, elem)
+	}
+	return nil
+}
+
+// Uvarint reads a uvarint.
+func (r *Reader) Uvarint() (uint64, error) {
+	n, size := binary.Uvarint(r.data[r.off:])
+	if size <= 0 {
+		return 0, fmt.Errorf("%w: invalid varint at offset %d", ErrCorrupt, r.off)
+	}
+	r.off += size
+	return n, nil
+}
+
+// Count reads the number of elements that follow, which is checked against
+// the number of bytes remaining so corrupt data cannot cause a large
+// allocation.
+func (r *Reader) Count() (int, error) {
+	off := r.off
+	n, err := r.Uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(r.Len()) {
+		return 0, fmt.Errorf("%w: count %d at offset %d exceeds the remaining data", ErrCorrupt, n, off)
+	}
+	return int(n), nil
+}
+
+// Len returns the number of unread bytes.
+func (r *Reader) Len() int {
+	return len(r.data) - r.off
+}
+
+// Close returns an error if any bytes are unread.
+func (r *Reader) Close() error {
+	if r.Len() > 0 {
+		return fmt.Errorf("%w: %d bytes of trailing data", ErrCorrupt, r.Len())
+	}
+	return nil
+}
+
+func (r *Reader) varint() (int64, error) {
+	n, size := binary.Varint(r.data[r.off:])
+	if size <= 0 {
+		return 0, fmt.Errorf("%w: invalid varint at offset %d", ErrCorrupt, r.off)
+	}
+	r.off += size
+	return n, nil
+}
+
+func (r *Reader) bytes(n int) ([]byte, error) {
+	if n > r.Len() {
+		return nil, fmt.Errorf("%w: truncated at offset %d", ErrCorrupt, r.off)
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+	return b, nil
+}
+
+func appendInt[T int | int8 | int16 | int32 | int64](b []byte, p unsafe.Pointer) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], int64(*(*T)(p)))]...)
+}
+
+func readInt[T int | int8 | int16 | int32 | int64](r *Reader, p unsafe.Pointer) error {
+	off := r.off
+	n, err := r.varint()
+	if err != nil {
+		return err
+	}
+	if int64(T(n)) != n {
+		return fmt.Errorf("%w: %d at offset %d overflows %T", ErrCorrupt, n, off, T(0))
+	}
+	*(*T)(p) = T(n)
+	return nil
+}
+
+func appendUint[T uint | uint8 | uint16 | uint32 | uint64](b []byte, p unsafe.Pointer) []byte {
+	return AppendUvarint(b, uint64(*(*T)(p)))
+}
+
+func readUint[T uint | uint8 | uint16 | uint32 | uint64](r *Reader, p unsafe.Pointer) error {
+	off := r.off
+	n, err := r.Uvarint()
+	if err != nil {
+		return err
+	}
+	if uint64(T(n)) != n {
+		return fmt.Errorf("%w: %d at offset %d overflows %T", ErrCorrupt, n, off, T(0))
+	}
+	*(*T)(p) = T(n)
+	return nil
+}
+
+func appendFloat32(b []byte, p unsafe.Pointer) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], math.Float32bits(*(*float32)(p)))
+	return append(b, buf[:]...)
+}
+
+func readFloat32(r *Reader, p unsafe.Pointer) error {
+	b, err := r.bytes(4)
+	if err != nil {
+		return err
+	}
+	*(*float32)(p) = math.Float32frombits(binary.LittleEndian.Uint32(b))
+	return nil
+}
+
+func appendFloat64(b []byte, p unsafe.Pointer) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(*(*float64)(p)))
+	return append(b, buf[:]...)
+}
+
+func readFloat64(r *Reader, p unsafe.Pointer) error {
+	b, err := r.bytes(8)
+	if err != nil {
+		return err
+	}
+	*(*float64)(p) = math.Float64frombits(binary.LittleEndian.Uint64(b))
+	return nil
+}
+
+func appendComplex64(b []byte, p unsafe.Pointer) []byte {
+	parts := (*[2]float32)(p)
+	b = appendFloat32(b, unsafe.Pointer(&parts[0]))
+	return appendFloat32(b, unsafe.Pointer(&parts[1]))
+}
+
+func readComplex64(r *Reader, p unsafe.Pointer) error {
+	parts := (*[2]float32)(p)
+	if err := readFloat32(r, unsafe.Pointer(&parts[0])); err != nil {
+		return err
+	}
+	return readFloat32(r, unsafe.Pointer(&parts[1]))
+}
+
+func appendComplex128(b []byte, p unsafe.Pointer) []byte {
+	parts := (*[2]float64)(p)
+	b = appendFloat64(b, unsafe.Pointer(&parts[0]))
+	return appendFloat64(b, unsafe.Pointer(&parts[1]))
+}
+
+func readComplex128(r *Reader, p unsafe.Pointer) error {
+	parts := (*[2]float64)(p)
+	if err := readFloat64(r, unsafe.Pointer(&parts[0])); err != nil {
+		return err
+	}
+	return readFloat64(r, unsafe.Pointer(&parts[1]))
+}
+
+func appendString(b []byte, p unsafe.Pointer) []byte {
+	s := *(*string)(p)
+	b = AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func readString(r *Reader, p unsafe.Pointer) error {
+	n, err := r.Count()
+	if err != nil {
+		return err
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return err
+	}
+	*(*string)(p) = string(b)
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wire_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"go-generics-the-hard-way/pkg/wire"
+)
+
+type ID string
+
+func appendAll[T any](t *testing.T, c wire.Container, vals ...T) []byte {
+	t.Helper()
+	codec, err := wire.CodecFor[T]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return codec.AppendAll(wire.AppendHeader(nil, c, codec.Type()), vals)
+}
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		name string
+		got  []byte
+		want []byte
+	}{
+		{
+			name: "signed",
+			got:  appendAll[int16](t, wire.List, 0, -1, 1, -64, 64),
+			want: []byte{1, byte(wire.List), byte(wire.Int16), 5, 0, 1, 2, 127, 128, 1},
+		},
+		{
+			name: "unsigned",
+			got:  appendAll[uint](t, wire.Set, 1, 300),
+			want: []byte{1, byte(wire.Set), byte(wire.Uint), 2, 1, 0xac, 0x02},
+		},
+		{
+			name: "float",
+			got:  appendAll[float32](t, wire.List, 1),
+			want: []byte{1, byte(wire.List), byte(wire.Float32), 1, 0, 0, 0x80, 0x3f},
+		},
+		{
+			name: "complex",
+			got:  appendAll[complex64](t, wire.List, complex(1, -2)),
+			want: []byte{1, byte(wire.List), byte(wire.Complex64), 1, 0, 0, 0x80, 0x3f, 0, 0, 0, 0xc0},
+		},
+		{
+			name: "string",
+			got:  appendAll[ID](t, wire.List, "ab", ""),
+			want: []byte{1, byte(wire.List), byte(wire.String), 2, 2, 'a', 'b', 0},
+		},
+	}
+	for _, tc := range testCases {
+		if !bytes.Equal(tc.got, tc.want) {
+			t.Errorf("%s: got % x, want % x", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func readAll[T any](data []byte, c wire.Container) ([]T, error) {
+	codec, err := wire.CodecFor[T]()
+	if err != nil {
+		return nil, err
+	}
+	r := wire.NewReader(data)
+	if err := r.Header(c, codec.Type()); err != nil {
+		return nil, err
+	}
+	vals, err := codec.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return vals, r.Close()
+}
+
+func TestRoundTrip(t *testing.T) {
+	vals := []float64{0, math.Inf(-1), math.SmallestNonzeroFloat64, -0.5}
+	got, err := readAll[float64](appendAll(t, wire.List, vals...), wire.List)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range vals {
+		if math.Float64bits(got[i]) != math.Float64bits(vals[i]) {
+			t.Errorf("got %v, want %v", got, vals)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ints := appendAll[int8](t, wire.List, 1, 2)
+	testCases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "empty", err: ignore(readAll[int8](nil, wire.List)), want: wire.ErrCorrupt},
+		{name: "version", err: ignore(readAll[int8](append([]byte{2}, ints[1:]...), wire.List)), want: wire.ErrVersion},
+		{name: "container", err: ignore(readAll[int8](ints, wire.Set)), want: wire.ErrMismatch},
+		{name: "type", err: ignore(readAll[uint8](ints, wire.List)), want: wire.ErrMismatch},
+		{name: "truncated", err: ignore(readAll[int8](ints[:len(ints)-1], wire.List)), want: wire.ErrCorrupt},
+		{name: "trailing", err: ignore(readAll[int8](append(ints, 0), wire.List)), want: wire.ErrCorrupt},
+		{name: "count", err: ignore(readAll[int8]([]byte{1, byte(wire.List), byte(wire.Int8), 0xff, 0xff, 0xff, 0x7f}, wire.List)), want: wire.ErrCorrupt},
+		{name: "overflow", err: ignore(readAll[int8]([]byte{1, byte(wire.List), byte(wire.Int8), 1, 0x80, 0x02}, wire.List)), want: wire.ErrCorrupt},
+		{name: "varint", err: ignore(readAll[int8]([]byte{1, byte(wire.List), byte(wire.Int8), 1, 0x80}, wire.List)), want: wire.ErrCorrupt},
+	}
+	for _, tc := range testCases {
+		if !errors.Is(tc.err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, tc.err, tc.want)
+		}
+	}
+
+	var uerr *wire.UnsupportedTypeError
+	if _, err := wire.CodecFor[bool](); !errors.As(err, &uerr) {
+		t.Errorf("got %v, want an *UnsupportedTypeError", err)
+	}
+}
+
+func ignore[T any](_ T, err error) error {
+	return err
+}
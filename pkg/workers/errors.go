@@ -0,0 +1,95 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrClosed is returned when a job is submitted to a pool that has been shut
+// down.
+var ErrClosed = errors.New("workers: pool is shut down")
+
+// PanicError is the error for a job whose function panicked.
+type PanicError struct {
+
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("workers: job panicked: %v", e.Value)
+}
+
+// JobError is the error for a single job run by Map or Stream.
+type JobError struct {
+
+	// Index is the position of the job's input.
+	Index int
+
+	// Err is the error returned by the job.
+	Err error
+}
+
+func (e *JobError) Error() string {
+	return fmt.Sprintf("job %d: %v", e.Index, e.Err)
+}
+
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// Errors aggregates the errors from multiple jobs.
+type Errors []error
+
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d errors occurred:", len(e))
+	for _, err := range e {
+		sb.WriteString("\n\t* ")
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// Is returns true if any of the aggregated errors match target.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first aggregated error that matches target.
+func (e Errors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
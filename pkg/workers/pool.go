@@ -0,0 +1,280 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workers provides a generic worker pool.
+//
+// A Pool[In, Out] is the multiple generic types pattern from "Getting
+// started" applied to concurrency: the type of a job's input and the type of
+// its result are separate type parameters, so neither has to be boxed in the
+// empty interface on its way to or from a worker.
+package workers
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// Func is the function a pool runs for each job.
+type Func[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Future is the eventual result of a submitted job.
+type Future[Out any] struct {
+	done chan struct{}
+	val  Out
+	err  error
+}
+
+// Done returns a channel that is closed when the job has completed.
+func (f *Future[Out]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the job completes or ctx is done, and returns the job's
+// result.
+func (f *Future[Out]) Wait(ctx context.Context) (Out, error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero Out
+		return zero, ctx.Err()
+	}
+}
+
+type job[In, Out any] struct {
+	ctx    context.Context
+	in     In
+	future *Future[Out]
+
+	// onDone, if not nil, is called after the future is completed.
+	onDone func(Out, error)
+}
+
+// Pool runs jobs on a fixed number of worker goroutines.
+//
+// The zero value is not usable, please use New.
+type Pool[In, Out any] struct {
+	fn   Func[In, Out]
+	jobs chan job[In, Out]
+	wg   sync.WaitGroup
+
+	// closing is closed by Shutdown to wake any Submit calls blocked on a
+	// full queue, and stopped is closed once every worker has exited. The
+	// jobs channel is only closed after all of the in-flight sends, tracked
+	// by sends, have returned.
+	mu      sync.RWMutex
+	closed  bool
+	sends   sync.WaitGroup
+	closing chan struct{}
+	stopped chan struct{}
+}
+
+// New returns a new pool that runs fn on the provided number of workers. Up
+// to queue jobs may be waiting for a worker before Submit blocks.
+func New[In, Out any](workers, queue int, fn Func[In, Out]) *Pool[In, Out] {
+	if workers < 1 {
+		workers = 1
+	}
+	if queue < 0 {
+		queue = 0
+	}
+	p := &Pool[In, Out]{
+		fn:      fn,
+		jobs:    make(chan job[In, Out], queue),
+		closing: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Submit queues a job for in and returns a future for its result. Submit
+// blocks while the queue is full, and returns an error if ctx is done first
+// or if the pool has been shut down.
+//
+// The job's function is called with ctx, and if ctx is done before a worker
+// starts the job, the job fails with ctx's error without being run.
+func (p *Pool[In, Out]) Submit(ctx context.Context, in In) (*Future[Out], error) {
+	return p.submit(ctx, in, nil)
+}
+
+func (p *Pool[In, Out]) submit(
+	ctx context.Context, in In, onDone func(Out, error)) (*Future[Out], error) {
+
+	// The lock is only held to register the send, not across it, so a
+	// Submit blocked on a full queue does not prevent Shutdown.
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, ErrClosed
+	}
+	p.sends.Add(1)
+	p.mu.RUnlock()
+	defer p.sends.Done()
+
+	j := job[In, Out]{
+		ctx:    ctx,
+		in:     in,
+		future: &Future[Out]{done: make(chan struct{})},
+		onDone: onDone,
+	}
+	select {
+	case p.jobs <- j:
+		return j.future, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.closing:
+		return nil, ErrClosed
+	}
+}
+
+// Shutdown stops the pool from accepting new jobs and waits for the queued
+// and running jobs to complete. If ctx is done first then Shutdown returns
+// ctx's error, although the workers continue until the queue is drained.
+//
+// Submit calls that are blocked on a full queue when Shutdown is called
+// return ErrClosed.
+func (p *Pool[In, Out]) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.closing)
+		go func() {
+			p.sends.Wait()
+			close(p.jobs)
+			p.wg.Wait()
+			close(p.stopped)
+		}()
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Map runs a job for each of the inputs and returns the results in the same
+// order as the inputs. If any jobs fail, the error is an Errors with a
+// *JobError for each failed job, and the results of the failed jobs are the
+// zero value of Out.
+func (p *Pool[In, Out]) Map(ctx context.Context, ins []In) ([]Out, error) {
+	futures := make([]*Future[Out], 0, len(ins))
+	var errs Errors
+	for i := range ins {
+		f, err := p.Submit(ctx, ins[i])
+		if err != nil {
+			errs = append(errs, &JobError{Index: i, Err: err})
+		}
+		futures = append(futures, f)
+	}
+
+	outs := make([]Out, len(ins))
+	for i, f := range futures {
+		if f == nil {
+			continue
+		}
+		out, err := f.Wait(ctx)
+		if err != nil {
+			errs = append(errs, &JobError{Index: i, Err: err})
+			continue
+		}
+		outs[i] = out
+	}
+	if len(errs) > 0 {
+		return outs, errs
+	}
+	return outs, nil
+}
+
+// Result is the result of a job run by Stream.
+type Result[Out any] struct {
+
+	// Index is the position of the job's input.
+	Index int
+
+	Value Out
+	Err   error
+}
+
+// Stream runs a job for each of the inputs and returns a channel that emits
+// the results in the order in which the jobs complete. The channel is closed
+// after every job has completed. A job's Err is a *JobError.
+func (p *Pool[In, Out]) Stream(ctx context.Context, ins []In) <-chan Result[Out] {
+	// The channel is large enough for every result, so workers never block
+	// on a reader.
+	results := make(chan Result[Out], len(ins))
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+
+	emit := func(i int, out Out, err error) {
+		if err != nil {
+			err = &JobError{Index: i, Err: err}
+		}
+		results <- Result[Out]{Index: i, Value: out, Err: err}
+		wg.Done()
+	}
+
+	go func() {
+		for i := range ins {
+			i := i
+			_, err := p.submit(ctx, ins[i], func(out Out, err error) {
+				emit(i, out, err)
+			})
+			if err != nil {
+				var zero Out
+				emit(i, zero, err)
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func (p *Pool[In, Out]) work() {
+	defer p.wg.Done()
+	for j := range p.jobs {
+		out, err := p.run(j)
+		j.future.val, j.future.err = out, err
+		close(j.future.done)
+		if j.onDone != nil {
+			j.onDone(out, err)
+		}
+	}
+}
+
+// run calls the pool's function for a job and turns a panic into an error.
+func (p *Pool[In, Out]) run(j job[In, Out]) (out Out, err error) {
+	if err := j.ctx.Err(); err != nil {
+		return out, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			var zero Out
+			out, err = zero, &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return p.fn(j.ctx, j.in)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-generics-the-hard-way/pkg/workers"
+)
+
+func atoi(_ context.Context, s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+func ExamplePool_Submit() {
+	ctx := context.Background()
+	p := workers.New(2, 10, atoi)
+	defer p.Shutdown(ctx)
+
+	f, _ := p.Submit(ctx, "42")
+	fmt.Println(f.Wait(ctx))
+	// Output: 42 <nil>
+}
+
+func ExamplePool_Map() {
+	ctx := context.Background()
+	p := workers.New(4, 0, atoi)
+	defer p.Shutdown(ctx)
+
+	fmt.Println(p.Map(ctx, []string{"1", "2", "3"}))
+
+	_, err := p.Map(ctx, []string{"1", "two", "3", "four"})
+	fmt.Println(err)
+	// Output:
+	// [1 2 3] <nil>
+	// 2 errors occurred:
+	//	* job 1: strconv.Atoi: parsing "two": invalid syntax
+	//	* job 3: strconv.Atoi: parsing "four": invalid syntax
+}
+
+func ExamplePool_Stream() {
+	ctx := context.Background()
+	p := workers.New(4, 0, atoi)
+	defer p.Shutdown(ctx)
+
+	// The results arrive in the order in which they complete, so sort them
+	// by index to print them.
+	var results []workers.Result[int]
+	for r := range p.Stream(ctx, []string{"1", "2", "x"}) {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+	for _, r := range results {
+		fmt.Println(r.Index, r.Value, r.Err)
+	}
+	// Output:
+	// 0 1 <nil>
+	// 1 2 <nil>
+	// 2 0 job 2: strconv.Atoi: parsing "x": invalid syntax
+}
+
+func TestPoolPanic(t *testing.T) {
+	ctx := context.Background()
+	p := workers.New(1, 0, func(_ context.Context, i int) (int, error) {
+		if i == 0 {
+			panic("divide by zero")
+		}
+		return 10 / i, nil
+	})
+	defer p.Shutdown(ctx)
+
+	f, err := p.Submit(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Wait(ctx)
+	var perr *workers.PanicError
+	if !errors.As(err, &perr) || perr.Value != "divide by zero" {
+		t.Fatalf("err = %v, want a *PanicError", err)
+	}
+
+	// The worker must survive the panic.
+	f, _ = p.Submit(ctx, 2)
+	if v, err := f.Wait(ctx); v != 5 || err != nil {
+		t.Fatalf("got %d, %v", v, err)
+	}
+}
+
+func TestPoolShutdown(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	p := workers.New(2, 4, func(_ context.Context, i int) (int, error) {
+		<-release
+		return i, nil
+	})
+
+	var futures []*workers.Future[int]
+	for i := 0; i < 6; i++ {
+		f, err := p.Submit(ctx, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		futures = append(futures, f)
+	}
+
+	// The jobs are blocked, so a shutdown with a short deadline times out.
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(tctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err := p.Submit(ctx, 7); !errors.Is(err, workers.ErrClosed) {
+		t.Fatalf("Submit() = %v, want %v", err, workers.ErrClosed)
+	}
+
+	// Once released, the queued jobs still complete.
+	close(release)
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i, f := range futures {
+		if v, err := f.Wait(ctx); v != i || err != nil {
+			t.Fatalf("job %d: got %d, %v", i, v, err)
+		}
+	}
+}
+
+func TestPoolShutdownBlockedSubmit(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	defer close(release)
+	p := workers.New(1, 0, func(_ context.Context, i int) (int, error) {
+		<-release
+		return i, nil
+	})
+
+	// The first job occupies the only worker and there is no queue, so the
+	// second Submit blocks until Shutdown is called.
+	if _, err := p.Submit(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Submit(ctx, 2)
+		errc <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// Shutdown must honor its deadline even though a Submit is blocked.
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(tctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := <-errc; !errors.Is(err, workers.ErrClosed) {
+		t.Fatalf("Submit() = %v, want %v", err, workers.ErrClosed)
+	}
+}
+
+func TestPoolCanceledJob(t *testing.T) {
+	ctx := context.Background()
+	block := make(chan struct{})
+	p := workers.New(1, 1, func(_ context.Context, i int) (int, error) {
+		<-block
+		return i, nil
+	})
+	defer p.Shutdown(ctx)
+
+	// The first job occupies the only worker and the second waits in the
+	// queue, where it is canceled before it starts.
+	first, _ := p.Submit(ctx, 1)
+	cctx, cancel := context.WithCancel(ctx)
+	second, _ := p.Submit(cctx, 2)
+	cancel()
+	close(block)
+
+	if _, err := first.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+}